@@ -14,7 +14,10 @@
 
 package backend
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrInputNotSet can be generated when solving the R1CS (a missing assignment) or running a Verifier
 var ErrInputNotSet = errors.New("variable is not allocated")
@@ -22,6 +25,15 @@ var ErrInputNotSet = errors.New("variable is not allocated")
 // ErrUnsatisfiedConstraint can be generated when solving a R1CS
 var ErrUnsatisfiedConstraint = errors.New("constraint is not satisfied")
 
+// BatchVerificationError is returned by a batch verification that failed, when the invalid proofs are looked for
+type BatchVerificationError struct {
+	InvalidProofs []int // sorted indexes of the invalid proofs in the batch
+}
+
+func (err *BatchVerificationError) Error() string {
+	return fmt.Sprintf("invalid proofs at indexes %v", err.InvalidProofs)
+}
+
 // note: this types are shared between frontend and backend packages and are here to avoid import cycles
 // probably need a better naming / home for them
 
@@ -0,0 +1,182 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"io"
+
+	groth16_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16"
+	groth16_bls381 "github.com/consensys/gnark/internal/backend/bls381/groth16"
+	groth16_bn256 "github.com/consensys/gnark/internal/backend/bn256/groth16"
+	groth16_bw761 "github.com/consensys/gnark/internal/backend/bw761/groth16"
+	"github.com/consensys/gurvy"
+)
+
+// AggregateProof represents a SnarkPack aggregate of Groth16 proofs generated by groth16.Aggregate
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type AggregateProof interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// AggregationSRS represents the structured reference string used to aggregate Groth16 proofs
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type AggregationSRS interface {
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// AggregationSetup samples an AggregationSRS allowing the aggregation of up to maxProofs proofs
+// maxProofs must be a power of 2
+func AggregationSetup(curveID gurvy.ID, maxProofs int) (AggregationSRS, error) {
+	switch curveID {
+	case gurvy.BN256:
+		var srs groth16_bn256.AggregationSRS
+		if err := groth16_bn256.AggregationSetup(maxProofs, &srs); err != nil {
+			return nil, err
+		}
+		return &srs, nil
+	case gurvy.BLS377:
+		var srs groth16_bls377.AggregationSRS
+		if err := groth16_bls377.AggregationSetup(maxProofs, &srs); err != nil {
+			return nil, err
+		}
+		return &srs, nil
+	case gurvy.BLS381:
+		var srs groth16_bls381.AggregationSRS
+		if err := groth16_bls381.AggregationSetup(maxProofs, &srs); err != nil {
+			return nil, err
+		}
+		return &srs, nil
+	case gurvy.BW761:
+		var srs groth16_bw761.AggregationSRS
+		if err := groth16_bw761.AggregationSetup(maxProofs, &srs); err != nil {
+			return nil, err
+		}
+		return &srs, nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// Aggregate compresses proofs verifying against vk into a single AggregateProof of logarithmic size.
+// publicWitnesses[i] is the public witness of proofs[i], and len(proofs) must be a power of 2
+//
+// each public witness must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
+func Aggregate(proofs []Proof, vk VerifyingKey, srs AggregationSRS, publicWitnesses []interface{}) (AggregateProof, error) {
+	_publicWitnesses, err := parseWitnesses(publicWitnesses)
+	if err != nil {
+		return nil, err
+	}
+
+	switch _vk := vk.(type) {
+	case *groth16_bls377.VerifyingKey:
+		_proofs := make([]*groth16_bls377.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bls377.Proof)
+		}
+		return groth16_bls377.Aggregate(_proofs, _vk, srs.(*groth16_bls377.AggregationSRS), _publicWitnesses)
+	case *groth16_bls381.VerifyingKey:
+		_proofs := make([]*groth16_bls381.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bls381.Proof)
+		}
+		return groth16_bls381.Aggregate(_proofs, _vk, srs.(*groth16_bls381.AggregationSRS), _publicWitnesses)
+	case *groth16_bn256.VerifyingKey:
+		_proofs := make([]*groth16_bn256.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bn256.Proof)
+		}
+		return groth16_bn256.Aggregate(_proofs, _vk, srs.(*groth16_bn256.AggregationSRS), _publicWitnesses)
+	case *groth16_bw761.VerifyingKey:
+		_proofs := make([]*groth16_bw761.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bw761.Proof)
+		}
+		return groth16_bw761.Aggregate(_proofs, _vk, srs.(*groth16_bw761.AggregationSRS), _publicWitnesses)
+	default:
+		panic("unrecognized VerifyingKey curve type")
+	}
+}
+
+// VerifyAggregate verifies an AggregateProof against vk and the public witnesses of the aggregated proofs
+// (see Aggregate)
+func VerifyAggregate(aggregate AggregateProof, vk VerifyingKey, srs AggregationSRS, publicWitnesses []interface{}) error {
+	_publicWitnesses, err := parseWitnesses(publicWitnesses)
+	if err != nil {
+		return err
+	}
+
+	switch _aggregate := aggregate.(type) {
+	case *groth16_bls377.AggregateProof:
+		return groth16_bls377.VerifyAggregate(_aggregate, vk.(*groth16_bls377.VerifyingKey), srs.(*groth16_bls377.AggregationSRS), _publicWitnesses)
+	case *groth16_bls381.AggregateProof:
+		return groth16_bls381.VerifyAggregate(_aggregate, vk.(*groth16_bls381.VerifyingKey), srs.(*groth16_bls381.AggregationSRS), _publicWitnesses)
+	case *groth16_bn256.AggregateProof:
+		return groth16_bn256.VerifyAggregate(_aggregate, vk.(*groth16_bn256.VerifyingKey), srs.(*groth16_bn256.AggregationSRS), _publicWitnesses)
+	case *groth16_bw761.AggregateProof:
+		return groth16_bw761.VerifyAggregate(_aggregate, vk.(*groth16_bw761.VerifyingKey), srs.(*groth16_bw761.AggregationSRS), _publicWitnesses)
+	default:
+		panic("unrecognized AggregateProof curve type")
+	}
+}
+
+// NewAggregateProof instantiates a curve-typed AggregateProof and returns an interface
+// This function exists for serialization purposes
+func NewAggregateProof(curveID gurvy.ID) AggregateProof {
+	switch curveID {
+	case gurvy.BN256:
+		return &groth16_bn256.AggregateProof{}
+	case gurvy.BLS377:
+		return &groth16_bls377.AggregateProof{}
+	case gurvy.BLS381:
+		return &groth16_bls381.AggregateProof{}
+	case gurvy.BW761:
+		return &groth16_bw761.AggregateProof{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewAggregationSRS instantiates a curve-typed AggregationSRS and returns an interface
+// This function exists for serialization purposes
+func NewAggregationSRS(curveID gurvy.ID) AggregationSRS {
+	switch curveID {
+	case gurvy.BN256:
+		return &groth16_bn256.AggregationSRS{}
+	case gurvy.BLS377:
+		return &groth16_bls377.AggregationSRS{}
+	case gurvy.BLS381:
+		return &groth16_bls381.AggregationSRS{}
+	case gurvy.BW761:
+		return &groth16_bw761.AggregationSRS{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// parseWitnesses runs parseWitness on each witness
+func parseWitnesses(witnesses []interface{}) ([]interface{}, error) {
+	res := make([]interface{}, len(witnesses))
+	for i := 0; i < len(witnesses); i++ {
+		var err error
+		if res[i], err = parseWitness(witnesses[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
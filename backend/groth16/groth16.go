@@ -16,10 +16,12 @@
 package groth16
 
 import (
+	"errors"
 	"io"
 
 	"github.com/consensys/gurvy"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
 	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
@@ -61,11 +63,14 @@ type VerifyingKey interface {
 	io.WriterTo
 	io.ReaderFrom
 	IsDifferent(interface{}) bool
+	ExportSolidity(w io.Writer) error
 }
 
 // Verify runs the groth16.Verify algorithm on provided proof with given solution
+//
+// solution must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
 func Verify(proof Proof, vk VerifyingKey, solution interface{}) error {
-	_solution, err := frontend.ParseWitness(solution)
+	_solution, err := parseWitness(solution)
 	if err != nil {
 		return err
 	}
@@ -83,12 +88,83 @@ func Verify(proof Proof, vk VerifyingKey, solution interface{}) error {
 	}
 }
 
+// BatchVerify verifies a batch of proofs against the same verifying key, with one multi Miller loop
+// and one final exponentiation. publicWitnesses[i] is the public witness of proofs[i].
+// if findInvalid flag is set and the batch doesn't verify, BatchVerify looks for the invalid proofs
+// and returns a *backend.BatchVerificationError listing them
+//
+// each public witness must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []interface{}, findInvalid ...bool) error {
+	_publicWitnesses, err := parseWitnesses(publicWitnesses)
+	if err != nil {
+		return err
+	}
+
+	_findInvalid := false
+	if len(findInvalid) > 0 {
+		_findInvalid = findInvalid[0]
+	}
+
+	switch _vk := vk.(type) {
+	case *groth16_bls377.VerifyingKey:
+		_proofs := make([]*groth16_bls377.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bls377.Proof)
+		}
+		return groth16_bls377.BatchVerify(_proofs, _vk, _publicWitnesses, _findInvalid)
+	case *groth16_bls381.VerifyingKey:
+		_proofs := make([]*groth16_bls381.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bls381.Proof)
+		}
+		return groth16_bls381.BatchVerify(_proofs, _vk, _publicWitnesses, _findInvalid)
+	case *groth16_bn256.VerifyingKey:
+		_proofs := make([]*groth16_bn256.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bn256.Proof)
+		}
+		return groth16_bn256.BatchVerify(_proofs, _vk, _publicWitnesses, _findInvalid)
+	case *groth16_bw761.VerifyingKey:
+		_proofs := make([]*groth16_bw761.Proof, len(proofs))
+		for i := 0; i < len(proofs); i++ {
+			_proofs[i] = proofs[i].(*groth16_bw761.Proof)
+		}
+		return groth16_bw761.BatchVerify(_proofs, _vk, _publicWitnesses, _findInvalid)
+	default:
+		panic("unrecognized VerifyingKey curve type")
+	}
+}
+
+// FormatCalldata returns the calldata of a call to the verifyProof function of the contract
+// exported by vk.ExportSolidity, with given proof and public witness.
+// Only BN256 proofs can be verified on the EVM.
+//
+// publicWitness must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
+func FormatCalldata(proof Proof, vk VerifyingKey, publicWitness interface{}) ([]byte, error) {
+	_publicWitness, err := parseWitness(publicWitness)
+	if err != nil {
+		return nil, err
+	}
+	switch _proof := proof.(type) {
+	case *groth16_bn256.Proof:
+		_vk, ok := vk.(*groth16_bn256.VerifyingKey)
+		if !ok {
+			return nil, errors.New("proof and verifying key are not defined on the same curve")
+		}
+		return groth16_bn256.FormatCalldata(_proof, _vk, _publicWitness)
+	default:
+		return nil, errors.New("calldata is only defined for BN256 proofs")
+	}
+}
+
 // Prove generates the proof of knoweldge of a r1cs with solution.
 // if force flag is set, Prove ignores R1CS solving error (ie invalid solution) and executes
 // the FFTs and MultiExponentiations to compute an (invalid) Proof object
+//
+// solution must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
 func Prove(r1cs r1cs.R1CS, pk ProvingKey, solution interface{}, force ...bool) (Proof, error) {
 
-	_solution, err := frontend.ParseWitness(solution)
+	_solution, err := parseWitness(solution)
 
 	if err != nil {
 		return nil, err
@@ -241,3 +317,12 @@ func NewProof(curveID gurvy.ID) Proof {
 
 	return proof
 }
+
+// parseWitness returns witness if it is a curve-typed witness.Witness (which the curve specific
+// implementations accept as is), and the result of frontend.ParseWitness otherwise
+func parseWitness(witness interface{}) (interface{}, error) {
+	if w, ok := witness.(backend.Witness); ok {
+		return w, nil
+	}
+	return frontend.ParseWitness(witness)
+}
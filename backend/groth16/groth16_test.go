@@ -0,0 +1,33 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"testing"
+
+	groth16_bls381 "github.com/consensys/gnark/internal/backend/bls381/groth16"
+	groth16_bn256 "github.com/consensys/gnark/internal/backend/bn256/groth16"
+)
+
+func TestFormatCalldataCurveMismatch(t *testing.T) {
+	publicWitness := map[string]interface{}{}
+
+	if _, err := FormatCalldata(&groth16_bn256.Proof{}, &groth16_bls381.VerifyingKey{}, publicWitness); err == nil {
+		t.Fatal("a BN256 proof with a BLS381 verifying key should be rejected")
+	}
+	if _, err := FormatCalldata(&groth16_bls381.Proof{}, &groth16_bls381.VerifyingKey{}, publicWitness); err == nil {
+		t.Fatal("calldata is only defined for BN256 proofs")
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mpcsetup implements a multi-party computation ceremony to generate the Groth16 keys
+// (https://eprint.iacr.org/2017/1050.pdf)
+//
+// The ceremony has two phases:
+//   - phase 1 (powers of τ) is independent of the circuit and can be reused by any circuit
+//     with less constraints than its size
+//   - phase 2 is specific to a circuit, and is initialized from the result of the phase 1
+//
+// Each participant calls Contribute on the output of the previous participant, and anyone can
+// check a contribution with VerifyContribution (or VerifyPhase1 and VerifyPhase2).
+// The keys are built by ExtractKeys and are usable by groth16.Prove and groth16.Verify.
+// The ceremony is secure as long as one of the participants discarded its randomness.
+package mpcsetup
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gurvy"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/r1cs"
+	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
+	groth16_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16"
+	mpcsetup_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16/mpcsetup"
+	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
+	groth16_bls381 "github.com/consensys/gnark/internal/backend/bls381/groth16"
+	mpcsetup_bls381 "github.com/consensys/gnark/internal/backend/bls381/groth16/mpcsetup"
+	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/internal/backend/bn256/groth16"
+	mpcsetup_bn256 "github.com/consensys/gnark/internal/backend/bn256/groth16/mpcsetup"
+	backend_bw761 "github.com/consensys/gnark/internal/backend/bw761"
+	groth16_bw761 "github.com/consensys/gnark/internal/backend/bw761/groth16"
+	mpcsetup_bw761 "github.com/consensys/gnark/internal/backend/bw761/groth16/mpcsetup"
+)
+
+var (
+	// ErrCurveMismatch is returned when the objects of a ceremony step are not defined on the same curve
+	ErrCurveMismatch = errors.New("ceremony objects are defined on different curves")
+
+	// ErrPhaseMismatch is returned by VerifyContribution when prev and next are not of the same phase
+	ErrPhaseMismatch = errors.New("contributions are not of the same phase")
+)
+
+// Phase1 represents the state of the powers of τ ceremony (phase 1)
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Phase1 interface {
+	io.WriterTo
+	io.ReaderFrom
+	Contribute(randomness io.Reader) error
+	GetCurveID() gurvy.ID
+}
+
+// Phase2 represents the state of the circuit specific ceremony (phase 2)
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Phase2 interface {
+	io.WriterTo
+	io.ReaderFrom
+	Contribute(randomness io.Reader) error
+	GetCurveID() gurvy.ID
+}
+
+// Phase2Evaluations represents the parts of the keys computed from the phase 1 that
+// don't change during the phase 2
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Phase2Evaluations interface {
+	io.WriterTo
+	io.ReaderFrom
+	GetCurveID() gurvy.ID
+}
+
+// InitPhase1 initializes a powers of τ ceremony for circuits with at most 2ᵖᵒʷᵉʳ constraints
+func InitPhase1(curveID gurvy.ID, power uint8) Phase1 {
+	switch curveID {
+	case gurvy.BLS377:
+		return mpcsetup_bls377.InitPhase1(power)
+	case gurvy.BLS381:
+		return mpcsetup_bls381.InitPhase1(power)
+	case gurvy.BN256:
+		return mpcsetup_bn256.InitPhase1(power)
+	case gurvy.BW761:
+		return mpcsetup_bw761.InitPhase1(power)
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewPhase1 instantiates a curve-typed Phase1 and returns an interface object
+// This function exists for serialization purposes
+func NewPhase1(curveID gurvy.ID) Phase1 {
+	switch curveID {
+	case gurvy.BLS377:
+		return &mpcsetup_bls377.Phase1{}
+	case gurvy.BLS381:
+		return &mpcsetup_bls381.Phase1{}
+	case gurvy.BN256:
+		return &mpcsetup_bn256.Phase1{}
+	case gurvy.BW761:
+		return &mpcsetup_bw761.Phase1{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// VerifyPhase1 checks that next is a valid contribution on top of prev
+//
+// it returns ErrCurveMismatch if prev and next are not defined on the same curve
+func VerifyPhase1(prev, next Phase1) error {
+	switch _prev := prev.(type) {
+	case *mpcsetup_bls377.Phase1:
+		_next, ok := next.(*mpcsetup_bls377.Phase1)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bls377.VerifyPhase1(_prev, _next)
+	case *mpcsetup_bls381.Phase1:
+		_next, ok := next.(*mpcsetup_bls381.Phase1)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bls381.VerifyPhase1(_prev, _next)
+	case *mpcsetup_bn256.Phase1:
+		_next, ok := next.(*mpcsetup_bn256.Phase1)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bn256.VerifyPhase1(_prev, _next)
+	case *mpcsetup_bw761.Phase1:
+		_next, ok := next.(*mpcsetup_bw761.Phase1)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bw761.VerifyPhase1(_prev, _next)
+	default:
+		panic("unrecognized Phase1 curve type")
+	}
+}
+
+// InitPhase2 initializes the circuit specific ceremony from the result of the powers of τ ceremony
+//
+// it returns ErrCurveMismatch if r1cs and phase1 are not defined on the same curve
+func InitPhase2(r1cs r1cs.R1CS, phase1 Phase1) (Phase2, Phase2Evaluations, error) {
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls377.R1CS:
+		_phase1, ok := phase1.(*mpcsetup_bls377.Phase1)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		phase2, evals, err := mpcsetup_bls377.InitPhase2(_r1cs, _phase1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return phase2, evals, nil
+	case *backend_bls381.R1CS:
+		_phase1, ok := phase1.(*mpcsetup_bls381.Phase1)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		phase2, evals, err := mpcsetup_bls381.InitPhase2(_r1cs, _phase1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return phase2, evals, nil
+	case *backend_bn256.R1CS:
+		_phase1, ok := phase1.(*mpcsetup_bn256.Phase1)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		phase2, evals, err := mpcsetup_bn256.InitPhase2(_r1cs, _phase1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return phase2, evals, nil
+	case *backend_bw761.R1CS:
+		_phase1, ok := phase1.(*mpcsetup_bw761.Phase1)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		phase2, evals, err := mpcsetup_bw761.InitPhase2(_r1cs, _phase1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return phase2, evals, nil
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// NewPhase2 instantiates a curve-typed Phase2 and returns an interface object
+// This function exists for serialization purposes
+func NewPhase2(curveID gurvy.ID) Phase2 {
+	switch curveID {
+	case gurvy.BLS377:
+		return &mpcsetup_bls377.Phase2{}
+	case gurvy.BLS381:
+		return &mpcsetup_bls381.Phase2{}
+	case gurvy.BN256:
+		return &mpcsetup_bn256.Phase2{}
+	case gurvy.BW761:
+		return &mpcsetup_bw761.Phase2{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewPhase2Evaluations instantiates a curve-typed Phase2Evaluations and returns an interface object
+// This function exists for serialization purposes
+func NewPhase2Evaluations(curveID gurvy.ID) Phase2Evaluations {
+	switch curveID {
+	case gurvy.BLS377:
+		return &mpcsetup_bls377.Phase2Evaluations{}
+	case gurvy.BLS381:
+		return &mpcsetup_bls381.Phase2Evaluations{}
+	case gurvy.BN256:
+		return &mpcsetup_bn256.Phase2Evaluations{}
+	case gurvy.BW761:
+		return &mpcsetup_bw761.Phase2Evaluations{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// VerifyPhase2 checks that next is a valid contribution on top of prev
+//
+// it returns ErrCurveMismatch if prev and next are not defined on the same curve
+func VerifyPhase2(prev, next Phase2) error {
+	switch _prev := prev.(type) {
+	case *mpcsetup_bls377.Phase2:
+		_next, ok := next.(*mpcsetup_bls377.Phase2)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bls377.VerifyPhase2(_prev, _next)
+	case *mpcsetup_bls381.Phase2:
+		_next, ok := next.(*mpcsetup_bls381.Phase2)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bls381.VerifyPhase2(_prev, _next)
+	case *mpcsetup_bn256.Phase2:
+		_next, ok := next.(*mpcsetup_bn256.Phase2)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bn256.VerifyPhase2(_prev, _next)
+	case *mpcsetup_bw761.Phase2:
+		_next, ok := next.(*mpcsetup_bw761.Phase2)
+		if !ok {
+			return ErrCurveMismatch
+		}
+		return mpcsetup_bw761.VerifyPhase2(_prev, _next)
+	default:
+		panic("unrecognized Phase2 curve type")
+	}
+}
+
+// VerifyContribution checks that next is a valid contribution on top of prev, for either phase
+// of the ceremony: prev and next must both be a Phase1 or both be a Phase2 (see VerifyPhase1
+// and VerifyPhase2)
+func VerifyContribution(prev, next interface{}) error {
+	phase := ceremonyPhase(prev)
+	if ceremonyPhase(next) != phase {
+		return ErrPhaseMismatch
+	}
+	switch phase {
+	case 1:
+		return VerifyPhase1(prev.(Phase1), next.(Phase1))
+	case 2:
+		return VerifyPhase2(prev.(Phase2), next.(Phase2))
+	default:
+		panic("unrecognized contribution type")
+	}
+}
+
+// ceremonyPhase returns the phase (1 or 2) of a contribution, 0 if it isn't one.
+// Phase1 and Phase2 have the same methods, so the phase is given by the concrete type
+func ceremonyPhase(contribution interface{}) int {
+	switch contribution.(type) {
+	case *mpcsetup_bls377.Phase1, *mpcsetup_bls381.Phase1, *mpcsetup_bn256.Phase1, *mpcsetup_bw761.Phase1:
+		return 1
+	case *mpcsetup_bls377.Phase2, *mpcsetup_bls381.Phase2, *mpcsetup_bn256.Phase2, *mpcsetup_bw761.Phase2:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ExtractKeys builds the Groth16 keys of the circuit from the last contribution of the ceremony
+//
+// it returns ErrCurveMismatch if r1cs, phase2 and evals are not defined on the same curve
+func ExtractKeys(r1cs r1cs.R1CS, phase2 Phase2, evals Phase2Evaluations) (groth16.ProvingKey, groth16.VerifyingKey, error) {
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls377.R1CS:
+		var pk groth16_bls377.ProvingKey
+		var vk groth16_bls377.VerifyingKey
+		_phase2, ok := phase2.(*mpcsetup_bls377.Phase2)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		_evals, ok := evals.(*mpcsetup_bls377.Phase2Evaluations)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		if err := mpcsetup_bls377.ExtractKeys(_r1cs, _phase2, _evals, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bls381.R1CS:
+		var pk groth16_bls381.ProvingKey
+		var vk groth16_bls381.VerifyingKey
+		_phase2, ok := phase2.(*mpcsetup_bls381.Phase2)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		_evals, ok := evals.(*mpcsetup_bls381.Phase2Evaluations)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		if err := mpcsetup_bls381.ExtractKeys(_r1cs, _phase2, _evals, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bn256.R1CS:
+		var pk groth16_bn256.ProvingKey
+		var vk groth16_bn256.VerifyingKey
+		_phase2, ok := phase2.(*mpcsetup_bn256.Phase2)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		_evals, ok := evals.(*mpcsetup_bn256.Phase2Evaluations)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		if err := mpcsetup_bn256.ExtractKeys(_r1cs, _phase2, _evals, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bw761.R1CS:
+		var pk groth16_bw761.ProvingKey
+		var vk groth16_bw761.VerifyingKey
+		_phase2, ok := phase2.(*mpcsetup_bw761.Phase2)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		_evals, ok := evals.(*mpcsetup_bw761.Phase2Evaluations)
+		if !ok {
+			return nil, nil, ErrCurveMismatch
+		}
+		if err := mpcsetup_bw761.ExtractKeys(_r1cs, _phase2, _evals, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
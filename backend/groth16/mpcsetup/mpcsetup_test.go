@@ -0,0 +1,110 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpcsetup
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+// Define declares x**3 + x + 5 == y
+func (circuit *cubicCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	x3 := cs.Mul(circuit.X, circuit.X, circuit.X)
+	cs.AssertIsEqual(circuit.Y, cs.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestCeremony(t *testing.T) {
+	var circuit cubicCircuit
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// phase 1
+	phase1 := InitPhase1(gurvy.BN256, 4)
+	prev1 := NewPhase1(gurvy.BN256)
+	clone(t, phase1, prev1)
+	if err := phase1.Contribute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyContribution(prev1, phase1); err != nil {
+		t.Fatal(err)
+	}
+
+	// phase 2
+	phase2, evals, err := InitPhase2(r1cs, phase1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev2 := NewPhase2(gurvy.BN256)
+	clone(t, phase2, prev2)
+	if err := phase2.Contribute(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyContribution(prev2, phase2); err != nil {
+		t.Fatal(err)
+	}
+
+	// the keys are usable by groth16
+	pk, vk, err := ExtractKeys(r1cs, phase2, evals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution := map[string]interface{}{"X": 3, "Y": 35}
+	proof, err := groth16.Prove(r1cs, pk, solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, solution); err != nil {
+		t.Fatal(err)
+	}
+
+	// the objects of a step must be of the same phase and on the same curve
+	if err := VerifyContribution(prev1, phase2); err != ErrPhaseMismatch {
+		t.Fatal("expected ErrPhaseMismatch, got", err)
+	}
+	other := InitPhase1(gurvy.BLS381, 4)
+	if err := VerifyContribution(prev1, other); err != ErrCurveMismatch {
+		t.Fatal("expected ErrCurveMismatch, got", err)
+	}
+	if _, _, err := InitPhase2(r1cs, other); err != ErrCurveMismatch {
+		t.Fatal("expected ErrCurveMismatch, got", err)
+	}
+	if _, _, err := ExtractKeys(r1cs, phase2, NewPhase2Evaluations(gurvy.BLS381)); err != ErrCurveMismatch {
+		t.Fatal("expected ErrCurveMismatch, got", err)
+	}
+}
+
+// clone copies src into dst through their serialization
+func clone(t *testing.T, src io.WriterTo, dst io.ReaderFrom) {
+	var buf bytes.Buffer
+	if _, err := src.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+}
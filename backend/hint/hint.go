@@ -0,0 +1,105 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hint provides the registry of hint functions: Go functions called by the R1CS solver
+// to compute the value of a wire (non-deterministic advice), see frontend.ConstraintSystem.NewHint.
+//
+// A R1CS references its hints by ID, which is derived from the function name. A R1CS deserialized
+// in another process can only be solved if its hints were registered beforehand (hint.Register),
+// typically in an init() function of the package declaring them.
+package hint
+
+import (
+	"errors"
+	"hash/fnv"
+	"math/big"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gurvy"
+)
+
+// ID is a unique identifier of a hint function, see UUID
+type ID uint32
+
+// Function computes result from the values of the inputs (big.Int in regular form, reduced
+// modulo the scalar field of curveID). result is reduced by the solver.
+//
+// the output of a hint function is not constrained: the circuit must constrain it
+type Function func(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error
+
+var errInvalidNbInputs = errors.New("invalid number of inputs")
+
+var (
+	registry = make(map[ID]Function)
+	lock     sync.RWMutex
+)
+
+func init() {
+	Register(IsZero)
+	Register(IthBit)
+}
+
+// UUID returns the identifier of f, derived from its fully qualified name.
+// f must be a named function (closures of a same function share their name)
+func UUID(f Function) ID {
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return ID(h.Sum32())
+}
+
+// Register registers f so that a R1CS referencing it can be solved, and returns its ID
+func Register(f Function) ID {
+	id := UUID(f)
+	lock.Lock()
+	registry[id] = f
+	lock.Unlock()
+	return id
+}
+
+// Lookup returns the hint function registered with id
+func Lookup(id ID) (Function, bool) {
+	lock.RLock()
+	f, ok := registry[id]
+	lock.RUnlock()
+	return f, ok
+}
+
+// IsZero sets result to 1 if inputs[0] == 0, and to 0 otherwise
+func IsZero(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	if len(inputs) != 1 {
+		return errInvalidNbInputs
+	}
+	if inputs[0].Sign() == 0 {
+		result.SetUint64(1)
+	} else {
+		result.SetUint64(0)
+	}
+	return nil
+}
+
+// IthBit sets result to the inputs[1]-th bit of inputs[0]
+func IthBit(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	if len(inputs) != 2 {
+		return errInvalidNbInputs
+	}
+	if !inputs[1].IsUint64() {
+		result.SetUint64(0)
+		return nil
+	}
+	result.SetUint64(uint64(inputs[0].Bit(int(inputs[1].Uint64()))))
+	return nil
+}
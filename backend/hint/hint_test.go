@@ -0,0 +1,77 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hint
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gurvy"
+)
+
+func double(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	result.Lsh(inputs[0], 1)
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	if UUID(double) != UUID(double) {
+		t.Fatal("the hint identifier should be deterministic")
+	}
+	if UUID(double) == UUID(IsZero) || UUID(IsZero) == UUID(IthBit) {
+		t.Fatal("different hints should have different identifiers")
+	}
+
+	if _, ok := Lookup(UUID(double)); ok {
+		t.Fatal("double shouldn't be registered yet")
+	}
+	id := Register(double)
+	f, ok := Lookup(id)
+	if !ok {
+		t.Fatal("double should be registered")
+	}
+	var result big.Int
+	if err := f(gurvy.BN256, []*big.Int{big.NewInt(21)}, &result); err != nil || result.Uint64() != 42 {
+		t.Fatal("lookup returned the wrong function")
+	}
+
+	if _, ok := Lookup(UUID(IthBit)); !ok {
+		t.Fatal("the built-in hints should be registered")
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	var result big.Int
+
+	for _, c := range []struct {
+		input    int64
+		expected uint64
+	}{{0, 1}, {1, 0}, {42, 0}} {
+		if err := IsZero(gurvy.BN256, []*big.Int{big.NewInt(c.input)}, &result); err != nil || result.Uint64() != c.expected {
+			t.Fatal("IsZero", c.input)
+		}
+	}
+
+	// 42 = 0b101010
+	for i, expected := range []uint64{0, 1, 0, 1, 0, 1, 0} {
+		if err := IthBit(gurvy.BN256, []*big.Int{big.NewInt(42), big.NewInt(int64(i))}, &result); err != nil || result.Uint64() != expected {
+			t.Fatal("IthBit", i)
+		}
+	}
+
+	if err := IthBit(gurvy.BN256, []*big.Int{big.NewInt(42)}, &result); err == nil {
+		t.Fatal("IthBit takes 2 inputs")
+	}
+}
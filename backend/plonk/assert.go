@@ -0,0 +1,153 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plonk
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/frontend"
+	gnarkio "github.com/consensys/gnark/io"
+	"github.com/stretchr/testify/require"
+)
+
+// Assert is a helper to test circuits
+type Assert struct {
+	*require.Assertions
+}
+
+// NewAssert returns an Assert helper
+func NewAssert(t *testing.T) *Assert {
+	return &Assert{require.New(t)}
+}
+
+// ProverFailed check that a solution does NOT solve a circuit
+//
+// solution must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) ProverFailed(r1cs r1cs.R1CS, solution interface{}) {
+	// setup
+	pk, _, err := Setup(r1cs)
+	assert.NoError(err)
+
+	_, err = Prove(r1cs, pk, assert.parseSolution(solution))
+	assert.Error(err, "proving with bad solution should output an error")
+}
+
+// ProverSucceeded check that a solution solves a circuit
+//
+// solution must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+//
+// 1. Runs plonk.Setup()
+//
+// 2. Solves the R1CS
+//
+// 3. Runs plonk.Prove()
+//
+// 4. Runs plonk.Verify()
+//
+// 5. Ensure deserialization(serialization) of generated objects is correct
+//
+// ensure result vectors a*b=c, and check other properties like random sampling
+func (assert *Assert) ProverSucceeded(r1cs r1cs.R1CS, solution interface{}) {
+	_solution := assert.parseSolution(solution)
+
+	// setup
+	pk, vk, err := Setup(r1cs)
+	assert.NoError(err)
+
+	// ensure expected Values are computed correctly
+	assert.SolvingSucceeded(r1cs, _solution)
+
+	// prover
+	proof, err := Prove(r1cs, pk, _solution)
+	assert.NoError(err, "proving with good solution should not output an error")
+
+	// ensure random sampling; calling prove twice with same input should produce different proof
+	{
+		proof2, err := Prove(r1cs, pk, _solution)
+		assert.NoError(err, "proving with good solution should not output an error")
+		assert.False(reflect.DeepEqual(proof, proof2), "calling prove twice with same input should produce different proof")
+	}
+
+	// verifier
+	{
+		err := Verify(proof, vk, _solution)
+		assert.NoError(err, "verifying proof with good solution should not output an error")
+	}
+
+	// forcing the prover with a bad solution should produce a proof that doesn't verify
+	{
+		proof, err := Prove(r1cs, pk, map[string]interface{}{}, true)
+		assert.NoError(err, "proving with force flag should not output an error")
+		assert.Error(Verify(proof, vk, _solution), "verifying forced proof should output an error")
+	}
+
+	// serialization
+	assert.serializationSucceeded(proof, NewProof(r1cs.GetCurveID()))
+	assert.serializationSucceeded(pk, NewProvingKey(r1cs.GetCurveID()))
+	assert.serializationSucceeded(vk, NewVerifyingKey(r1cs.GetCurveID()))
+	assert.serializationRawSucceeded(proof, NewProof(r1cs.GetCurveID()))
+	assert.serializationRawSucceeded(pk, NewProvingKey(r1cs.GetCurveID()))
+	assert.serializationRawSucceeded(vk, NewVerifyingKey(r1cs.GetCurveID()))
+}
+
+func (assert *Assert) serializationSucceeded(from io.WriterTo, to io.ReaderFrom) {
+	var buf bytes.Buffer
+	written, err := from.WriteTo(&buf)
+	assert.NoError(err, "serializing to buffer failed")
+
+	read, err := to.ReadFrom(&buf)
+	assert.NoError(err, "desererializing from buffer failed")
+
+	assert.EqualValues(written, read, "number of bytes read and written don't match")
+}
+
+func (assert *Assert) serializationRawSucceeded(from gnarkio.WriterRawTo, to io.ReaderFrom) {
+	var buf bytes.Buffer
+	written, err := from.WriteRawTo(&buf)
+	assert.NoError(err, "serializing raw to buffer failed")
+
+	read, err := to.ReadFrom(&buf)
+	assert.NoError(err, "desererializing raw from buffer failed")
+
+	assert.EqualValues(written, read, "number of bytes read and written don't match")
+}
+
+// SolvingSucceeded Verifies that the R1CS is solved with the given solution, without executing plonk workflow
+//
+// solution must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) SolvingSucceeded(r1cs r1cs.R1CS, solution interface{}) {
+	assert.NoError(r1cs.IsSolved(assert.parseSolution(solution)))
+}
+
+// SolvingFailed Verifies that the R1CS is not solved with the given solution, without executing plonk workflow
+//
+// solution must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) SolvingFailed(r1cs r1cs.R1CS, solution interface{}) {
+	assert.Error(r1cs.IsSolved(assert.parseSolution(solution)))
+}
+
+func (assert *Assert) parseSolution(solution interface{}) map[string]interface{} {
+	_solution, err := frontend.ParseWitness(solution)
+	assert.NoError(err)
+	return _solution
+}
@@ -0,0 +1,303 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plonk implements PLONK zkSNARK workflow (https://eprint.iacr.org/2019/953.pdf)
+//
+// The R1CS produced by the frontend is converted in a list of gates of fan-in 2
+// (qL.l + qR.r + qM.l.r + qO.o + qK == 0). Polynomials are committed with the KZG scheme.
+package plonk
+
+import (
+	"io"
+
+	"github.com/consensys/gurvy"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/crypto/kzg"
+	kzg_bls377 "github.com/consensys/gnark/crypto/kzg/bls377"
+	kzg_bls381 "github.com/consensys/gnark/crypto/kzg/bls381"
+	kzg_bn256 "github.com/consensys/gnark/crypto/kzg/bn256"
+	kzg_bw761 "github.com/consensys/gnark/crypto/kzg/bw761"
+	"github.com/consensys/gnark/frontend"
+	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
+	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
+	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
+	backend_bw761 "github.com/consensys/gnark/internal/backend/bw761"
+	gnarkio "github.com/consensys/gnark/io"
+
+	"github.com/consensys/gnark/backend/r1cs"
+	plonk_bls377 "github.com/consensys/gnark/internal/backend/bls377/plonk"
+	plonk_bls381 "github.com/consensys/gnark/internal/backend/bls381/plonk"
+	plonk_bn256 "github.com/consensys/gnark/internal/backend/bn256/plonk"
+	plonk_bw761 "github.com/consensys/gnark/internal/backend/bw761/plonk"
+)
+
+// Proof represents a PLONK proof generated by plonk.Prove
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Proof interface {
+	gnarkio.WriterRawTo
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// ProvingKey represents a PLONK ProvingKey
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type ProvingKey interface {
+	gnarkio.WriterRawTo
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// VerifyingKey represents a PLONK VerifyingKey
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type VerifyingKey interface {
+	gnarkio.WriterRawTo
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// Verify runs the plonk.Verify algorithm on provided proof with given solution
+//
+// solution must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
+func Verify(proof Proof, vk VerifyingKey, solution interface{}) error {
+	_solution, err := parseWitness(solution)
+	if err != nil {
+		return err
+	}
+	switch _proof := proof.(type) {
+	case *plonk_bls377.Proof:
+		return plonk_bls377.Verify(_proof, vk.(*plonk_bls377.VerifyingKey), _solution)
+	case *plonk_bls381.Proof:
+		return plonk_bls381.Verify(_proof, vk.(*plonk_bls381.VerifyingKey), _solution)
+	case *plonk_bn256.Proof:
+		return plonk_bn256.Verify(_proof, vk.(*plonk_bn256.VerifyingKey), _solution)
+	case *plonk_bw761.Proof:
+		return plonk_bw761.Verify(_proof, vk.(*plonk_bw761.VerifyingKey), _solution)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// Prove generates a PLONK proof of knowledge of a solution of the r1cs.
+// if force flag is set, Prove ignores R1CS solving error (ie invalid solution) and executes
+// the FFTs and the commitments to compute an (invalid) Proof object
+//
+// solution must be map[string]interface{}, implement frontend.Circuit or be a witness.Witness
+func Prove(r1cs r1cs.R1CS, pk ProvingKey, solution interface{}, force ...bool) (Proof, error) {
+
+	_solution, err := parseWitness(solution)
+
+	if err != nil {
+		return nil, err
+	}
+
+	_force := false
+	if len(force) > 0 {
+		_force = force[0]
+	}
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls377.R1CS:
+		return plonk_bls377.Prove(_r1cs, pk.(*plonk_bls377.ProvingKey), _solution, _force)
+	case *backend_bls381.R1CS:
+		return plonk_bls381.Prove(_r1cs, pk.(*plonk_bls381.ProvingKey), _solution, _force)
+	case *backend_bn256.R1CS:
+		return plonk_bn256.Prove(_r1cs, pk.(*plonk_bn256.ProvingKey), _solution, _force)
+	case *backend_bw761.R1CS:
+		return plonk_bw761.Prove(_r1cs, pk.(*plonk_bw761.ProvingKey), _solution, _force)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// Setup runs plonk.Setup with provided R1CS
+//
+// the KZG SRS is generated from a random secret, known by the caller of Setup:
+// this is meant for test purposes only
+func Setup(r1cs r1cs.R1CS) (ProvingKey, VerifyingKey, error) {
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls377.R1CS:
+		srs, err := plonk_bls377.NewSRS(_r1cs)
+		if err != nil {
+			return nil, nil, err
+		}
+		var pk plonk_bls377.ProvingKey
+		var vk plonk_bls377.VerifyingKey
+		if err := plonk_bls377.Setup(_r1cs, srs, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bls381.R1CS:
+		srs, err := plonk_bls381.NewSRS(_r1cs)
+		if err != nil {
+			return nil, nil, err
+		}
+		var pk plonk_bls381.ProvingKey
+		var vk plonk_bls381.VerifyingKey
+		if err := plonk_bls381.Setup(_r1cs, srs, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bn256.R1CS:
+		srs, err := plonk_bn256.NewSRS(_r1cs)
+		if err != nil {
+			return nil, nil, err
+		}
+		var pk plonk_bn256.ProvingKey
+		var vk plonk_bn256.VerifyingKey
+		if err := plonk_bn256.Setup(_r1cs, srs, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bw761.R1CS:
+		srs, err := plonk_bw761.NewSRS(_r1cs)
+		if err != nil {
+			return nil, nil, err
+		}
+		var pk plonk_bw761.ProvingKey
+		var vk plonk_bw761.VerifyingKey
+		if err := plonk_bw761.Setup(_r1cs, srs, &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// SetupWithSRS runs plonk.Setup with provided R1CS and KZG SRS
+//
+// the SRS must be defined on the same curve as the R1CS, and contain at least
+// SizeSRS(r1cs) powers (see crypto/kzg to load the SRS of a public ceremony)
+func SetupWithSRS(r1cs r1cs.R1CS, srs kzg.SRS) (ProvingKey, VerifyingKey, error) {
+
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls377.R1CS:
+		var pk plonk_bls377.ProvingKey
+		var vk plonk_bls377.VerifyingKey
+		if err := plonk_bls377.Setup(_r1cs, srs.(*kzg_bls377.SRS), &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bls381.R1CS:
+		var pk plonk_bls381.ProvingKey
+		var vk plonk_bls381.VerifyingKey
+		if err := plonk_bls381.Setup(_r1cs, srs.(*kzg_bls381.SRS), &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bn256.R1CS:
+		var pk plonk_bn256.ProvingKey
+		var vk plonk_bn256.VerifyingKey
+		if err := plonk_bn256.Setup(_r1cs, srs.(*kzg_bn256.SRS), &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	case *backend_bw761.R1CS:
+		var pk plonk_bw761.ProvingKey
+		var vk plonk_bw761.VerifyingKey
+		if err := plonk_bw761.Setup(_r1cs, srs.(*kzg_bw761.SRS), &pk, &vk); err != nil {
+			return nil, nil, err
+		}
+		return &pk, &vk, nil
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// SizeSRS returns the minimal size of the KZG SRS needed to run Setup on the R1CS
+func SizeSRS(r1cs r1cs.R1CS) uint64 {
+	switch _r1cs := r1cs.(type) {
+	case *backend_bls377.R1CS:
+		return plonk_bls377.SizeSRS(_r1cs)
+	case *backend_bls381.R1CS:
+		return plonk_bls381.SizeSRS(_r1cs)
+	case *backend_bn256.R1CS:
+		return plonk_bn256.SizeSRS(_r1cs)
+	case *backend_bw761.R1CS:
+		return plonk_bw761.SizeSRS(_r1cs)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
+// NewProvingKey instantiates a curve-typed ProvingKey and returns an interface object
+// This function exists for serialization purposes
+func NewProvingKey(curveID gurvy.ID) ProvingKey {
+	var pk ProvingKey
+	switch curveID {
+	case gurvy.BN256:
+		pk = &plonk_bn256.ProvingKey{}
+	case gurvy.BLS377:
+		pk = &plonk_bls377.ProvingKey{}
+	case gurvy.BLS381:
+		pk = &plonk_bls381.ProvingKey{}
+	case gurvy.BW761:
+		pk = &plonk_bw761.ProvingKey{}
+	default:
+		panic("not implemented")
+	}
+	return pk
+}
+
+// NewVerifyingKey instantiates a curve-typed VerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewVerifyingKey(curveID gurvy.ID) VerifyingKey {
+	var vk VerifyingKey
+	switch curveID {
+	case gurvy.BN256:
+		vk = &plonk_bn256.VerifyingKey{}
+	case gurvy.BLS377:
+		vk = &plonk_bls377.VerifyingKey{}
+	case gurvy.BLS381:
+		vk = &plonk_bls381.VerifyingKey{}
+	case gurvy.BW761:
+		vk = &plonk_bw761.VerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+	return vk
+}
+
+// NewProof instantiates a curve-typed Proof and returns an interface
+// This function exists for serialization purposes
+func NewProof(curveID gurvy.ID) Proof {
+	var proof Proof
+	switch curveID {
+	case gurvy.BN256:
+		proof = &plonk_bn256.Proof{}
+	case gurvy.BLS377:
+		proof = &plonk_bls377.Proof{}
+	case gurvy.BLS381:
+		proof = &plonk_bls381.Proof{}
+	case gurvy.BW761:
+		proof = &plonk_bw761.Proof{}
+	default:
+		panic("not implemented")
+	}
+	return proof
+}
+
+// parseWitness returns witness if it is a curve-typed witness.Witness (which the curve specific
+// implementations accept as is), and the result of frontend.ParseWitness otherwise
+func parseWitness(witness interface{}) (interface{}, error) {
+	if w, ok := witness.(backend.Witness); ok {
+		return w, nil
+	}
+	return frontend.ParseWitness(witness)
+}
@@ -14,6 +14,8 @@
 
 package r1c
 
+import "github.com/consensys/gnark/backend/hint"
+
 // LinearExpression represent a linear expression of variables
 type LinearExpression []Term
 
@@ -25,6 +27,13 @@ type R1C struct {
 	Solver SolvingMethod
 }
 
+// Hint describes a wire computed by a hint function (see backend/hint) while solving the R1CS
+type Hint struct {
+	ID     hint.ID            // identifier of the registered hint function
+	WireID int                // id of the computed wire
+	Inputs []LinearExpression // the hint function is called on the values of these linear expressions
+}
+
 // SolvingMethod is used by the R1CS solver
 // note: it is not in backend/r1cs to avoid an import cycle
 type SolvingMethod uint8
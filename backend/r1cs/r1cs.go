@@ -30,7 +30,8 @@ import (
 type R1CS interface {
 	io.WriterTo
 	io.ReaderFrom
-	IsSolved(solution map[string]interface{}) error
+	// IsSolved returns nil if solution (a map[string]interface{} or a witness.Witness) solves the R1CS
+	IsSolved(solution interface{}) error
 	GetNbConstraints() uint64
 	GetNbWires() uint64
 	GetNbCoefficients() int
@@ -41,6 +41,7 @@ type UntypedR1CS struct {
 	NbCOConstraints uint64 // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []r1c.R1C
 	Coefficients    []big.Int
+	Hints           []r1c.Hint // wires computed by hint functions, see backend/hint
 }
 
 // GetNbConstraints returns the number of constraints
@@ -74,7 +75,7 @@ func (r1cs *UntypedR1CS) ReadFrom(r io.Reader) (n int64, err error) {
 }
 
 // IsSolved call will panic as we can't solve a UntypedR1CS
-func (r1cs *UntypedR1CS) IsSolved(solution map[string]interface{}) error {
+func (r1cs *UntypedR1CS) IsSolved(solution interface{}) error {
 	panic("not implemented")
 }
 
@@ -0,0 +1,39 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/consensys/gurvy"
+)
+
+// Witness is a curve-typed assignment of the public and secret inputs of a circuit,
+// ordered as the inputs of the compiled circuit (see gnark/backend/witness)
+//
+// note: it is declared here so that the curve specific implementations (see gnark/internal/backend)
+// can return it without import cycles
+type Witness interface {
+	io.WriterTo
+	io.ReaderFrom
+	json.Marshaler
+	json.Unmarshaler
+
+	// Public returns the projection of the witness on its public inputs
+	Public() Witness
+
+	GetCurveID() gurvy.ID
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package witness provides curve-typed witnesses: the assignment of the public and secret inputs
+// of a circuit, as field elements ordered as the inputs of the compiled circuit.
+//
+// A Witness is built from an assigned circuit (FromCircuit) or from a map of values (FromMap),
+// is serializable (binary or JSON) and can be passed directly to groth16.Prove, groth16.Verify
+// and R1CS.IsSolved. Its Public() projection is what a verifier needs.
+package witness
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	witness_bls377 "github.com/consensys/gnark/internal/backend/bls377/witness"
+	witness_bls381 "github.com/consensys/gnark/internal/backend/bls381/witness"
+	witness_bn256 "github.com/consensys/gnark/internal/backend/bn256/witness"
+	witness_bw761 "github.com/consensys/gnark/internal/backend/bw761/witness"
+	"github.com/consensys/gurvy"
+)
+
+// Witness is a curve-typed assignment of the public and secret inputs of a circuit
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type Witness = backend.Witness
+
+// appender is implemented by the curve specific witnesses
+type appender interface {
+	Append(name string, visibility backend.Visibility, value interface{}) error
+}
+
+// New instantiates an empty curve-typed Witness
+// This function exists for serialization purposes
+func New(curveID gurvy.ID) Witness {
+	switch curveID {
+	case gurvy.BN256:
+		return &witness_bn256.Witness{}
+	case gurvy.BLS377:
+		return &witness_bls377.Witness{}
+	case gurvy.BLS381:
+		return &witness_bls381.Witness{}
+	case gurvy.BW761:
+		return &witness_bw761.Witness{}
+	default:
+		panic("not implemented")
+	}
+}
+
+// FromCircuit returns the Witness of an assigned circuit
+//
+// all the public and secret inputs of the circuit must be assigned
+func FromCircuit(curveID gurvy.ID, circuit frontend.Circuit) (Witness, error) {
+	inputs, err := frontend.ParseInputs(circuit)
+	if err != nil {
+		return nil, err
+	}
+
+	w := New(curveID)
+	for _, input := range inputs {
+		if input.Value == nil {
+			return nil, fmt.Errorf("%q: %w", input.Name, backend.ErrInputNotSet)
+		}
+		if err := w.(appender).Append(input.Name, input.Visibility, input.Value); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// FromMap returns the Witness of the circuit described by schema (which doesn't need to be assigned)
+// with the values of the map, indexed by input names
+//
+// the map must contain all the public and secret inputs of the circuit; other entries are ignored
+func FromMap(curveID gurvy.ID, schema frontend.Circuit, values map[string]interface{}) (Witness, error) {
+	inputs, err := frontend.ParseInputs(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	w := New(curveID)
+	for _, input := range inputs {
+		value, ok := values[input.Name]
+		if !ok {
+			return nil, fmt.Errorf("%q: %w", input.Name, backend.ErrInputNotSet)
+		}
+		if err := w.(appender).Append(input.Name, input.Visibility, value); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
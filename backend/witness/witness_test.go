@@ -0,0 +1,125 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	witness_bn256 "github.com/consensys/gnark/internal/backend/bn256/witness"
+	"github.com/consensys/gurvy"
+)
+
+type inputsCircuit struct {
+	X      frontend.Variable
+	Y      frontend.Variable `gnark:",public"`
+	Nested struct {
+		Z, W frontend.Variable
+	}
+}
+
+// Define declares x * w == y + z
+func (circuit *inputsCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(circuit.X, circuit.Nested.W), cs.Add(circuit.Y, circuit.Nested.Z))
+	return nil
+}
+
+func TestFromCircuit(t *testing.T) {
+	var circuit inputsCircuit
+	circuit.X.Assign(3)
+	circuit.Y.Assign(10)
+	circuit.Nested.Z.Assign(2)
+	circuit.Nested.W.Assign(4)
+
+	w, err := witness.FromCircuit(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_w := w.(*witness_bn256.Witness)
+	if !reflect.DeepEqual(_w.PublicNames, []string{"Y"}) || !reflect.DeepEqual(_w.SecretNames, []string{"X", "Nested_Z", "Nested_W"}) {
+		t.Fatal("the witness inputs should be ordered as the circuit inputs", _w.PublicNames, _w.SecretNames)
+	}
+
+	fromMap, err := witness.FromMap(gurvy.BN256, &inputsCircuit{}, map[string]interface{}{
+		"X": 3, "Y": 10, "Nested_Z": 2, "Nested_W": 4, "unused": 42,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(w, fromMap) {
+		t.Fatal("witnesses built from a circuit or a map should be equal")
+	}
+
+	if _, err := witness.FromMap(gurvy.BN256, &inputsCircuit{}, map[string]interface{}{"X": 3}); !errors.Is(err, backend.ErrInputNotSet) {
+		t.Fatal("a missing input should be reported", err)
+	}
+	circuit.Nested.W = frontend.Variable{}
+	if _, err := witness.FromCircuit(gurvy.BN256, &circuit); !errors.Is(err, backend.ErrInputNotSet) {
+		t.Fatal("an unassigned input should be reported", err)
+	}
+}
+
+func TestProveWithWitness(t *testing.T) {
+	var circuit inputsCircuit
+	r1cs, err := frontend.Compile(gurvy.BLS377, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	good, err := witness.FromMap(gurvy.BLS377, &circuit, map[string]interface{}{"X": 3, "Y": 10, "Nested_Z": 2, "Nested_W": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := witness.FromMap(gurvy.BLS377, &circuit, map[string]interface{}{"X": 3, "Y": 10, "Nested_Z": 2, "Nested_W": 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(good); err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(bad); err == nil {
+		t.Fatal("IsSolved should have failed")
+	}
+
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, good.Public()); err != nil {
+		t.Fatal(err)
+	}
+
+	wrongPublic, err := witness.FromMap(gurvy.BLS377, &circuit, map[string]interface{}{"X": 3, "Y": 11, "Nested_Z": 2, "Nested_W": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, wrongPublic.Public()); err == nil {
+		t.Fatal("Verify should have failed")
+	}
+
+	// witnesses of a different curve are rejected
+	if err := r1cs.IsSolved(witness.New(gurvy.BN256)); err == nil {
+		t.Fatal("IsSolved should have failed")
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+// Package pedersen implements the Pedersen hash on the twisted Edwards curve embedded in BLS381.
+//
+// The message is read as a sequence of bits (the bits of each byte, least significant first), padded
+// with zeros to a multiple of 3 bits and split in segments of NbChunksPerSegment chunks of 3 bits.
+// As in Zcash, the chunk (s0, s1, s2) is encoded as enc = (1 - 2*s2) * (1 + s0 + 2*s1) and
+// the hash is the point
+//
+//	H = Σ_i (Σ_j enc(chunk_j of segment i) * 2^(4*j)) * G_i
+//
+// where the generators G_i are derived by hashing to the curve (see Generator).
+// The hash (see New) is the x coordinate of H.
+package pedersen
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/bls381/fr"
+	"github.com/consensys/gurvy/bls381/twistededwards"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// NbChunksPerSegment is the number of 3 bits chunks hashed with the same generator, such that
+	// the scalar of a segment is smaller (in absolute value) than half the order of the curve
+	NbChunksPerSegment = 62
+
+	// Size is the size in bytes of the hash
+	Size = fr.Limbs * 8
+
+	// domain separates the generators from other hashes to the curve
+	domain = "gnark_pedersen_generator"
+)
+
+var (
+	generators   []twistededwards.Point
+	generatorsMu sync.Mutex
+)
+
+// Generator returns the generator of the i-th segment of the messages.
+//
+// The point is the first point P = (x, y) of the subgroup, such that y = blake2b(domain || i || counter)
+// for increasing counters, x is lexicographically smallest, and P is multiplied by the cofactor
+func Generator(i int) twistededwards.Point {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	for len(generators) <= i {
+		generators = append(generators, findGenerator(len(generators)))
+	}
+	return generators[i]
+}
+
+func findGenerator(i int) twistededwards.Point {
+	params := twistededwards.GetEdwardsCurve()
+	var cofactor big.Int
+	params.Cofactor.ToBigInt(&cofactor)
+
+	var one fr.Element
+	one.SetOne()
+
+	buf := make([]byte, len(domain)+8)
+	copy(buf, domain)
+	binary.BigEndian.PutUint32(buf[len(domain):], uint32(i))
+
+	for counter := uint32(0); ; counter++ {
+		binary.BigEndian.PutUint32(buf[len(domain)+4:], counter)
+		h := blake2b.Sum512(buf)
+
+		// x² = (1 - y²) / (a - d*y²)
+		var p twistededwards.Point
+		var yy, num, den fr.Element
+		p.Y.SetBytes(h[:])
+		yy.Square(&p.Y)
+		num.Sub(&one, &yy)
+		den.Mul(&params.D, &yy).Sub(&params.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		num.Div(&num, &den)
+		if p.X.Sqrt(&num) == nil {
+			continue
+		}
+		if p.X.LexicographicallyLargest() {
+			p.X.Neg(&p.X)
+		}
+
+		p.ScalarMul(&p, &cofactor)
+		if p.X.IsZero() {
+			continue
+		}
+		return p
+	}
+}
+
+// HashPoint returns the Pedersen hash of msg, as a point of the twisted Edwards curve
+func HashPoint(msg []byte) twistededwards.Point {
+	bits := make([]bool, 8*len(msg))
+	for i, b := range msg {
+		for j := 0; j < 8; j++ {
+			bits[8*i+j] = (b>>j)&1 == 1
+		}
+	}
+
+	params := twistededwards.GetEdwardsCurve()
+
+	var res twistededwards.Point
+	res.Y.SetOne()
+
+	bit := func(i int) int64 {
+		if i < len(bits) && bits[i] {
+			return 1
+		}
+		return 0
+	}
+
+	for segment, start := 0, 0; start < len(bits); segment, start = segment+1, start+3*NbChunksPerSegment {
+		var scalar, enc big.Int
+		for j := 0; j < NbChunksPerSegment && start+3*j < len(bits); j++ {
+			c := start + 3*j
+			enc.SetInt64((1 + bit(c) + 2*bit(c+1)) * (1 - 2*bit(c+2)))
+			scalar.Add(&scalar, enc.Lsh(&enc, uint(4*j)))
+		}
+		scalar.Mod(&scalar, &params.Order)
+
+		var p twistededwards.Point
+		g := Generator(segment)
+		p.ScalarMul(&g, &scalar)
+		res.Add(&res, &p)
+	}
+
+	return res
+}
+
+// digest accumulates the message, hashed when Sum is called
+type digest struct {
+	data []byte
+}
+
+// New returns a Pedersen hash.Hash, whose checksum is the x coordinate of HashPoint
+// (big-endian, Size bytes)
+func New() hash.Hash {
+	return &digest{}
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	p := HashPoint(d.data)
+	x := p.X.Bytes()
+	return append(b, x[:]...)
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return Size
+}
+
+// BlockSize returns the hash's underlying block size.
+func (d *digest) BlockSize() int {
+	return 1
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+// It never returns an error.
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.data = append(d.data, p...)
+	return len(p), nil
+}
+
+// Sum returns the Pedersen hash of msg
+func Sum(msg []byte) []byte {
+	d := New()
+	_, _ = d.Write(msg)
+	return d.Sum(nil)
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poseidon
+
+import (
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+// isSecureMDS reports whether the linear layer of a Poseidon permutation with one S-box in the
+// partial rounds has no infinitely long subspace trail (https://eprint.iacr.org/2020/500), i.e.
+// that there is no subspace of the state that can go through any number of partial rounds
+// without activating the S-box, or without spreading to the whole state.
+//
+// It checks that:
+//   - the subspace generated by e_0, mds^r e_0, mds^2r e_0, ... is the whole space, for r = 1, ..., 4t
+//     (e_0 being the input of the S-box), as the algorithms 2 and 3 of the reference implementation
+//   - no subspace invariant by mds is in the inactive subspace {v: v_0 = 0}, which is what the
+//     algorithm 1 of the reference implementation looks for. The largest such subspace is orthogonal
+//     to e_0, e_0 mds, e_0 mds^2, ... so this is the first check on the transpose of mds
+func isSecureMDS(mds [][]fr.Element) bool {
+	t := len(mds)
+
+	transpose := make([][]fr.Element, t)
+	for i := range transpose {
+		transpose[i] = make([]fr.Element, t)
+		for j := range transpose[i] {
+			transpose[i][j] = mds[j][i]
+		}
+	}
+	if !isCyclic(transpose) {
+		return false
+	}
+
+	m := mds
+	for r := 1; r <= 4*t; r++ {
+		if !isCyclic(m) {
+			return false
+		}
+		m = matMul(m, mds)
+	}
+	return true
+}
+
+// isCyclic reports whether e_0, m e_0, ..., m^(t-1) e_0 span the whole space, i.e. whether no
+// strict subspace invariant by m contains e_0
+func isCyclic(m [][]fr.Element) bool {
+	t := len(m)
+
+	// the rows of a are e_0, m e_0, ..., m^(t-1) e_0
+	a := make([][]fr.Element, t)
+	a[0] = make([]fr.Element, t)
+	a[0][0].SetOne()
+	for k := 1; k < t; k++ {
+		a[k] = matVec(m, a[k-1])
+	}
+
+	// Gaussian elimination: a has full rank if each column has a pivot
+	for k := 0; k < t; k++ {
+		pivot := -1
+		for i := k; i < t; i++ {
+			if !a[i][k].IsZero() {
+				pivot = i
+				break
+			}
+		}
+		if pivot == -1 {
+			return false
+		}
+		a[k], a[pivot] = a[pivot], a[k]
+
+		var inv fr.Element
+		inv.Inverse(&a[k][k])
+		for i := k + 1; i < t; i++ {
+			if a[i][k].IsZero() {
+				continue
+			}
+			var f fr.Element
+			f.Mul(&a[i][k], &inv)
+			for j := k; j < t; j++ {
+				var tmp fr.Element
+				tmp.Mul(&f, &a[k][j])
+				a[i][j].Sub(&a[i][j], &tmp)
+			}
+		}
+	}
+	return true
+}
+
+// matMul returns a*b
+func matMul(a, b [][]fr.Element) [][]fr.Element {
+	res := make([][]fr.Element, len(a))
+	for i := range a {
+		res[i] = make([]fr.Element, len(b[0]))
+		for j := range res[i] {
+			for k := range b {
+				var tmp fr.Element
+				tmp.Mul(&a[i][k], &b[k][j])
+				res[i][j].Add(&res[i][j], &tmp)
+			}
+		}
+	}
+	return res
+}
+
+// matVec returns m*v
+func matVec(m [][]fr.Element, v []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(m))
+	for i := range m {
+		for j := range v {
+			var tmp fr.Element
+			tmp.Mul(&m[i][j], &v[j])
+			res[i].Add(&res[i], &tmp)
+		}
+	}
+	return res
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poseidon
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+// MaxInputs is the maximum number of inputs of Hash
+const MaxInputs = 16
+
+// circomlib uses 8 full rounds, and the number of partial rounds of the width t is
+// nbPartialRounds[t-2] (the 128 bits security bounds of the Poseidon paper, for the x^5 S-box)
+const nbFullRounds = 8
+
+var nbPartialRounds = [MaxInputs]int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+
+var (
+	circomlibParams   [MaxInputs]*Params
+	circomlibParamsMu sync.Mutex
+)
+
+// CircomlibParams returns the parameters used by circomlib to hash nbInputs elements (the width is
+// nbInputs+1). They are generated once by GenerateParams, and must not be modified.
+func CircomlibParams(nbInputs int) (*Params, error) {
+	if nbInputs < 1 || nbInputs > MaxInputs {
+		return nil, errors.New("the number of inputs must be between 1 and 16")
+	}
+
+	circomlibParamsMu.Lock()
+	defer circomlibParamsMu.Unlock()
+	if circomlibParams[nbInputs-1] == nil {
+		circomlibParams[nbInputs-1] = GenerateParams(nbInputs+1, nbFullRounds, nbPartialRounds[nbInputs-1])
+	}
+	return circomlibParams[nbInputs-1], nil
+}
+
+// GenerateParams returns the parameters of a Poseidon permutation of width t, sampled as in the
+// reference implementation of the Poseidon paper (generate_parameters_grain.sage): the round constants
+// and the Cauchy MDS matrix are drawn from a Grain LFSR seeded with the field, the width and the
+// number of rounds.
+//
+// Like the reference script, GenerateParams samples a new matrix until one has no infinitely long
+// subspace trail (see isSecureMDS). The circomlib parameters, for 1 to 16 inputs, are checked
+// against the test vectors of circomlib.
+func GenerateParams(t, nbFullRounds, nbPartialRounds int) *Params {
+	modulus := fr.Modulus()
+	nbBits := modulus.BitLen()
+
+	g := newGrain(t, nbBits, nbFullRounds, nbPartialRounds)
+
+	params := &Params{
+		T:               t,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+		RoundConstants:  make([]fr.Element, (nbFullRounds+nbPartialRounds)*t),
+	}
+
+	// the round constants are sampled by rejection
+	for i := range params.RoundConstants {
+		c := g.nextInt(nbBits)
+		for c.Cmp(modulus) >= 0 {
+			c = g.nextInt(nbBits)
+		}
+		params.RoundConstants[i].SetBigInt(c)
+	}
+
+	// MDS[i][j] = 1 / (x_i + y_j), for distinct x_0, ..., x_{t-1}, y_0, ..., y_{t-1} (reduced mod p)
+	for {
+		xy := make([]fr.Element, 2*t)
+		for distinct := false; !distinct; {
+			distinct = true
+			for i := range xy {
+				xy[i].SetBigInt(g.nextInt(nbBits))
+				for j := 0; j < i; j++ {
+					if xy[i].Equal(&xy[j]) {
+						distinct = false
+					}
+				}
+			}
+		}
+
+		mds := make([][]fr.Element, t)
+		ok := true
+		for i := 0; i < t && ok; i++ {
+			mds[i] = make([]fr.Element, t)
+			for j := 0; j < t && ok; j++ {
+				mds[i][j].Add(&xy[i], &xy[t+j])
+				if mds[i][j].IsZero() {
+					ok = false
+				}
+				mds[i][j].Inverse(&mds[i][j])
+			}
+		}
+		if ok && isSecureMDS(mds) {
+			params.MDS = mds
+			return params
+		}
+	}
+}
+
+// grain is the Grain LFSR of the reference implementation, in self-shrinking mode
+type grain struct {
+	state [80]bool
+}
+
+func newGrain(t, nbBits, nbFullRounds, nbPartialRounds int) *grain {
+	g := &grain{}
+
+	// field (1: prime field) on 2 bits, S-box (0: x^alpha) on 4 bits, field size on 12 bits,
+	// width on 12 bits, full rounds on 10 bits, partial rounds on 10 bits, and 30 bits set to 1
+	i := 0
+	write := func(v, n int) {
+		for j := n - 1; j >= 0; j-- {
+			g.state[i] = (v>>j)&1 == 1
+			i++
+		}
+	}
+	write(1, 2)
+	write(0, 4)
+	write(nbBits, 12)
+	write(t, 12)
+	write(nbFullRounds, 10)
+	write(nbPartialRounds, 10)
+	write(1<<30-1, 30)
+
+	// the first 160 bits are discarded
+	for j := 0; j < 160; j++ {
+		g.update()
+	}
+	return g
+}
+
+// update shifts the LFSR and returns the new bit
+func (g *grain) update() bool {
+	b := g.state[62] != g.state[51]
+	b = b != g.state[38]
+	b = b != g.state[23]
+	b = b != g.state[13]
+	b = b != g.state[0]
+	copy(g.state[:], g.state[1:])
+	g.state[79] = b
+	return b
+}
+
+// nextBit returns the next bit of the self-shrinking generator: the bits are read in pairs, and
+// the second bit of a pair is output if the first one is set
+func (g *grain) nextBit() bool {
+	for {
+		if g.update() {
+			return g.update()
+		}
+		g.update()
+	}
+}
+
+// nextInt returns the integer whose nbBits bits (most significant first) are the next bits
+func (g *grain) nextInt(nbBits int) *big.Int {
+	res := new(big.Int)
+	for i := 0; i < nbBits; i++ {
+		res.Lsh(res, 1)
+		if g.nextBit() {
+			res.SetBit(res, 0, 1)
+		}
+	}
+	return res
+}
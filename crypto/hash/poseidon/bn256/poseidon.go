@@ -0,0 +1,106 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package poseidon implements the Poseidon hash (https://eprint.iacr.org/2019/458) on the scalar
+// field of BN256, with the x^5 S-box.
+//
+// The width and the round constants are configurable (see Params). CircomlibParams returns the
+// parameters of circomlib, and Hash is the circomlib Poseidon hash.
+package poseidon
+
+import (
+	"github.com/consensys/gnark/crypto/hash/poseidon"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+// Params of a Poseidon permutation of width T
+type Params struct {
+	T               int // width of the permutation (number of field elements of the state)
+	NbFullRounds    int // number of full rounds, half of them before the partial rounds
+	NbPartialRounds int // number of partial rounds
+
+	// RoundConstants are added to the state before each round: the constants of round r are
+	// RoundConstants[r*T:(r+1)*T]
+	RoundConstants []fr.Element
+
+	// MDS is the T x T matrix of the linear layer: state[i] = Σ_j MDS[i][j] * state[j]
+	MDS [][]fr.Element
+}
+
+// Check returns an error if the sizes of the round constants or of the MDS matrix don't match the width
+// and the number of rounds
+func (params *Params) Check() error {
+	mdsRows := make([]int, len(params.MDS))
+	for i := range params.MDS {
+		mdsRows[i] = len(params.MDS[i])
+	}
+	return poseidon.CheckSizes(params.T, params.NbFullRounds, params.NbPartialRounds, len(params.RoundConstants), mdsRows)
+}
+
+// Permutation applies the Poseidon permutation to state (of size params.T), in place
+func (params *Params) Permutation(state []fr.Element) {
+	if len(state) != params.T {
+		panic("the size of the state must be the width of the permutation")
+	}
+
+	tmp := make([]fr.Element, params.T)
+	halfFullRounds := params.NbFullRounds / 2
+
+	for r := 0; r < params.NbFullRounds+params.NbPartialRounds; r++ {
+		for i := range state {
+			state[i].Add(&state[i], &params.RoundConstants[r*params.T+i])
+		}
+
+		if r < halfFullRounds || r >= halfFullRounds+params.NbPartialRounds {
+			for i := range state {
+				sbox(&state[i])
+			}
+		} else {
+			sbox(&state[0])
+		}
+
+		var t fr.Element
+		for i := range tmp {
+			tmp[i].SetZero()
+			for j := range state {
+				t.Mul(&params.MDS[i][j], &state[j])
+				tmp[i].Add(&tmp[i], &t)
+			}
+		}
+		copy(state, tmp)
+	}
+}
+
+// sbox sets x to x^5
+func sbox(x *fr.Element) {
+	var x2 fr.Element
+	x2.Square(x)
+	x2.Square(&x2)
+	x.Mul(x, &x2)
+}
+
+// Hash returns the circomlib Poseidon hash of inputs (1 to MaxInputs elements): the first element
+// of the permutation of [0, inputs...], with the parameters CircomlibParams(len(inputs))
+func Hash(inputs ...fr.Element) (fr.Element, error) {
+	params, err := CircomlibParams(len(inputs))
+	if err != nil {
+		return fr.Element{}, err
+	}
+
+	state := make([]fr.Element, params.T)
+	copy(state[1:], inputs)
+	params.Permutation(state)
+
+	return state[0], nil
+}
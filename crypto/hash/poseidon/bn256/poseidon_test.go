@@ -0,0 +1,120 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poseidon
+
+import (
+	"testing"
+
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+func TestCircomlibParams(t *testing.T) {
+	params, err := CircomlibParams(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := params.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	// first round constant of circomlib poseidon_constants.json, for t = 3 (0x0ee9a592...)
+	var expected fr.Element
+	expected.SetString("6745197990210204598374042828761989596302876299545964402857411729872131034734")
+	if !params.RoundConstants[0].Equal(&expected) {
+		t.Fatal("unexpected round constant", params.RoundConstants[0].String())
+	}
+
+	if _, err := CircomlibParams(0); err == nil {
+		t.Fatal("expected an error for 0 inputs")
+	}
+	if _, err := CircomlibParams(MaxInputs + 1); err == nil {
+		t.Fatal("expected an error for too many inputs")
+	}
+}
+
+func TestHash(t *testing.T) {
+	// test vectors of circomlib: poseidon([1, 2, ..., n]), for every number of inputs (the vectors
+	// of circomlib cover 1, 2, 4, 5 and 6 inputs, the others were computed with go-iden3-crypto
+	// v0.0.17, which uses the constants of circomlib)
+	vectors := map[int]string{
+		1:  "18586133768512220936620570745912940619677854269274689475585506675881198879027",
+		2:  "7853200120776062878684798364095072458815029376092732009249414926327459813530",
+		3:  "6542985608222806190361240322586112750744169038454362455181422643027100751666",
+		4:  "18821383157269793795438455681495246036402687001665670618754263018637548127333",
+		5:  "6183221330272524995739186171720101788151706631170188140075976616310159254464",
+		6:  "20400040500897583745843009878988256314335038853985262692600694741116813247201",
+		7:  "12748163991115452309045839028154629052133952896122405799815156419278439301912",
+		8:  "18604317144381847857886385684060986177838410221561136253933256952257712543953",
+		9:  "13589767895268936107593642967621470491511464502761040466226072462545218539640",
+		10: "3657500514307717306974218405144578736633140001277925127187636780142269815841",
+		11: "3572015662710076994097916907865950486270383304442561406230608893458731714472",
+		12: "2501997477381648492950318384533644783248002172679259592360114615426357826485",
+		13: "7041832639553862712666971417715061873827921493498355005117622707743491651590",
+		14: "8354478399926161176778659061636406690034081872658507739535256090879947077494",
+		15: "4203130618016961831408770638653325366880478848856764494148034853759773445968",
+		16: "9989051620750914585850546081941653841776809718687451684622678807385399211877",
+	}
+
+	for n, vector := range vectors {
+		inputs := make([]fr.Element, n)
+		for i := range inputs {
+			inputs[i].SetUint64(uint64(i + 1))
+		}
+		h, err := Hash(inputs...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected fr.Element
+		expected.SetString(vector)
+		if !h.Equal(&expected) {
+			t.Fatal("unexpected hash of", n, "inputs:", h.String())
+		}
+	}
+
+	if _, err := Hash(make([]fr.Element, MaxInputs+1)...); err == nil {
+		t.Fatal("expected an error for too many inputs")
+	}
+}
+
+func TestIsSecureMDS(t *testing.T) {
+	params, err := CircomlibParams(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSecureMDS(params.MDS) {
+		t.Fatal("the MDS matrix of circomlib should be secure")
+	}
+
+	matrix := func(rows ...[]uint64) [][]fr.Element {
+		res := make([][]fr.Element, len(rows))
+		for i, row := range rows {
+			res[i] = make([]fr.Element, len(row))
+			for j := range row {
+				res[i][j].SetUint64(row[j])
+			}
+		}
+		return res
+	}
+
+	// the identity keeps the subspace generated by e_0
+	if isSecureMDS(matrix([]uint64{1, 0, 0}, []uint64{0, 1, 0}, []uint64{0, 0, 1})) {
+		t.Fatal("the identity should be insecure")
+	}
+
+	// {v: v_0 = 0} is invariant, so the S-box is never activated in this subspace
+	if isSecureMDS(matrix([]uint64{1, 0, 0}, []uint64{1, 1, 0}, []uint64{0, 1, 1})) {
+		t.Fatal("a matrix with an inactive invariant subspace should be insecure")
+	}
+}
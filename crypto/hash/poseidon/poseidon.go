@@ -0,0 +1,44 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package poseidon contains what the Poseidon permutations of each field (e.g. bn256) and the
+// Poseidon gadget (std/hash/poseidon) share: the checks of their parameters.
+package poseidon
+
+import (
+	"errors"
+)
+
+// CheckSizes returns an error if the number of round constants or the size of the MDS matrix (given
+// by the lengths of its rows) don't match the width t and the numbers of rounds of a permutation
+func CheckSizes(t, nbFullRounds, nbPartialRounds, nbRoundConstants int, mdsRows []int) error {
+	if t < 2 {
+		return errors.New("the width must be at least 2")
+	}
+	if nbFullRounds%2 != 0 {
+		return errors.New("the number of full rounds must be even")
+	}
+	if nbRoundConstants != (nbFullRounds+nbPartialRounds)*t {
+		return errors.New("invalid number of round constants")
+	}
+	if len(mdsRows) != t {
+		return errors.New("invalid size of the MDS matrix")
+	}
+	for _, n := range mdsRows {
+		if n != t {
+			return errors.New("invalid size of the MDS matrix")
+		}
+	}
+	return nil
+}
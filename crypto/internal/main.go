@@ -9,7 +9,7 @@ import (
 	"github.com/consensys/bavard"
 )
 
-//go:generate go run main.go mimc_template.go
+//go:generate go run main.go mimc_template.go twistededwards_template.go pedersen_template.go
 func main() {
 
 	// -----------------------------------------------------
@@ -38,11 +38,80 @@ func main() {
 		Package:  "bls377",
 	}
 
+	mimcbw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../hash/mimc/bw761/",
+		FileName: "mimc_bw761.go",
+		Src:      []string{mimcCommonTemplate, mimcCurveTemplate, mimcEncryptTemplate},
+		Package:  "bw761",
+	}
+
+	// -----------------------------------------------------
+	// twisted Edwards curves (the curves embedded in BN256 and BLS381 are in gurvy)
+	var edwards []templateData
+	for _, curve := range []string{"BLS377", "BW761"} {
+		path := "../twistededwards/" + strings.ToLower(curve) + "/"
+		edwards = append(edwards, templateData{
+			Curve:    curve,
+			Path:     path,
+			FileName: "twistededwards.go",
+			Src:      []string{twistedEdwardsCurveTemplate},
+			Package:  "twistededwards",
+		}, templateData{
+			Curve:    curve,
+			Path:     path,
+			FileName: "point.go",
+			Src:      []string{twistedEdwardsPointTemplate},
+			Package:  "twistededwards",
+		}, templateData{
+			Curve:    curve,
+			Path:     path,
+			FileName: "twistededwards_test.go",
+			Src:      []string{twistedEdwardsTestTemplate},
+			Package:  "twistededwards",
+		})
+	}
+
+	// -----------------------------------------------------
+	// Pedersen hash on the twisted Edwards curves of gurvy
+	var pedersen []templateData
+	for _, curve := range []string{"BN256", "BLS381"} {
+		path := "../hash/pedersen/" + strings.ToLower(curve) + "/"
+		doc := "implements the Pedersen hash on the twisted Edwards curve embedded in " + curve + ".\n" +
+			"//\n" +
+			"// The message is read as a sequence of bits (the bits of each byte, least significant first), padded\n" +
+			"// with zeros to a multiple of 3 bits and split in segments of NbChunksPerSegment chunks of 3 bits.\n" +
+			"// As in Zcash, the chunk (s0, s1, s2) is encoded as enc = (1 - 2*s2) * (1 + s0 + 2*s1) and\n" +
+			"// the hash is the point\n" +
+			"//\n" +
+			"//	H = Σ_i (Σ_j enc(chunk_j of segment i) * 2^(4*j)) * G_i\n" +
+			"//\n" +
+			"// where the generators G_i are derived by hashing to the curve (see Generator).\n" +
+			"// The hash (see New) is the x coordinate of H."
+		pedersen = append(pedersen, templateData{
+			Curve:    curve,
+			Path:     path,
+			FileName: "pedersen.go",
+			Src:      []string{pedersenTemplate},
+			Package:  "pedersen",
+			Doc:      doc,
+		}, templateData{
+			Curve:    curve,
+			Path:     path,
+			FileName: "pedersen_test.go",
+			Src:      []string{pedersenTestTemplate},
+			Package:  "pedersen",
+		})
+	}
+
 	data := []templateData{
 		mimcbn256,
 		mimcbls381,
 		mimcbls377,
+		mimcbw761,
 	}
+	data = append(data, edwards...)
+	data = append(data, pedersen...)
 
 	var wg sync.WaitGroup
 	for _, d := range data {
@@ -75,6 +144,7 @@ type templateData struct {
 	FileName string
 	Src      []string
 	Package  string
+	Doc      string // package documentation, after "Package <Package> "
 }
 
 const copyrightHolder = "ConsenSys Software Inc."
@@ -87,7 +157,7 @@ func generate(d templateData) error {
 	}
 
 	if err := bavard.Generate(d.Path+d.FileName, d.Src, d,
-		bavard.Package(d.Package),
+		bavard.Package(d.Package, d.Doc),
 		bavard.Apache2(copyrightHolder, 2020),
 		bavard.GeneratedBy("gnark"),
 		bavard.Format(false),
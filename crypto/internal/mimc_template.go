@@ -39,6 +39,23 @@ const mimcEncryptTemplate = `
 		m.Add(&m, &d.h)
 		d.h = m
 	}
+{{ else if eq .Curve "BW761" }}
+	// plain execution of a mimc run
+	// m: message
+	// k: encryption key
+	func (d *digest) encrypt(m fr.Element) {
+
+		for i:=0; i < len(d.Params); i++ {
+			// m = (m+k+c)^5
+			var tmp fr.Element
+			tmp.Add(&m, &d.h).Add(&tmp, &d.Params[i])
+			m.Square(&tmp).
+				Square(&m).
+				Mul(&m, &tmp)
+		}
+		m.Add(&m, &d.h)
+		d.h = m
+	}
 {{ else if eq .Curve "BLS377" }}
 	// plain execution of a mimc run
 	// m: message
@@ -113,6 +130,42 @@ const mimcCurveTemplate = `
 	// Params constants for the mimc hash function
 	type Params []fr.Element
 
+	// NewParams creates new mimc object
+	func NewParams(seed string) Params {
+
+		// set the constants
+		res := make(Params, mimcNbRounds)
+
+		rnd := sha3.Sum256([]byte(seed))
+		value := new(big.Int).SetBytes(rnd[:])
+
+		for i := 0; i < mimcNbRounds; i++ {
+			rnd = sha3.Sum256(value.Bytes())
+			value.SetBytes(rnd[:])
+			res[i].SetBigInt(value)
+		}
+
+		return res
+	}
+{{ else if eq .Curve "BW761" }}
+	import (
+		"hash"
+		"math/big"
+
+		"github.com/consensys/gurvy/bw761/fr"
+		"golang.org/x/crypto/sha3"
+	)
+
+	// x -> x^5 is a permutation of the field, and 163 rounds ensure that the degree of the
+	// function reaches the size of the field (377 bits)
+	const mimcNbRounds = 163
+
+	// BlockSize size that mimc consumes
+	const BlockSize = fr.Limbs * 8
+
+	// Params constants for the mimc hash function
+	type Params []fr.Element
+
 	// NewParams creates new mimc object
 	func NewParams(seed string) Params {
 
@@ -194,7 +247,7 @@ func NewMiMC(seed string) hash.Hash {
 // Reset resets the Hash to its initial state.
 func (d *digest) Reset() {
 	d.data = nil
-	d.h = fr.Element{0, 0, 0, 0}
+	d.h.SetZero()
 }
 
 // Sum appends the current hash to b and returns the resulting slice.
@@ -233,7 +286,7 @@ func (d *digest) Write(p []byte) (n int, err error) {
 // The XOR operation is replaced by field addition, data is in Montgomery form
 func (d *digest) checksum() fr.Element {
 
-	var buffer [32]byte
+	var buffer [BlockSize]byte
 	var x fr.Element
 
 	// if data size is not multiple of BlockSizes we padd:
@@ -251,7 +304,7 @@ func (d *digest) checksum() fr.Element {
 	}
 
 	if len(d.data) == 0 {
-		d.data = make([]byte, 32)
+		d.data = make([]byte, BlockSize)
 	}
 
 	nbChunks := len(d.data) / BlockSize
@@ -0,0 +1,245 @@
+package main
+
+const pedersenTemplate = `
+import (
+	"encoding/binary"
+	"hash"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// NbChunksPerSegment is the number of 3 bits chunks hashed with the same generator, such that
+	// the scalar of a segment is smaller (in absolute value) than half the order of the curve
+	NbChunksPerSegment = 62
+
+	// Size is the size in bytes of the hash
+	Size = fr.Limbs * 8
+
+	// domain separates the generators from other hashes to the curve
+	domain = "gnark_pedersen_generator"
+)
+
+var (
+	generators   []twistededwards.Point
+	generatorsMu sync.Mutex
+)
+
+// Generator returns the generator of the i-th segment of the messages.
+//
+// The point is the first point P = (x, y) of the subgroup, such that y = blake2b(domain || i || counter)
+// for increasing counters, x is lexicographically smallest, and P is multiplied by the cofactor
+func Generator(i int) twistededwards.Point {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	for len(generators) <= i {
+		generators = append(generators, findGenerator(len(generators)))
+	}
+	return generators[i]
+}
+
+func findGenerator(i int) twistededwards.Point {
+	params := twistededwards.GetEdwardsCurve()
+	var cofactor big.Int
+	params.Cofactor.ToBigInt(&cofactor)
+
+	var one fr.Element
+	one.SetOne()
+
+	buf := make([]byte, len(domain)+8)
+	copy(buf, domain)
+	binary.BigEndian.PutUint32(buf[len(domain):], uint32(i))
+
+	for counter := uint32(0); ; counter++ {
+		binary.BigEndian.PutUint32(buf[len(domain)+4:], counter)
+		h := blake2b.Sum512(buf)
+
+		// x² = (1 - y²) / (a - d*y²)
+		var p twistededwards.Point
+		var yy, num, den fr.Element
+		p.Y.SetBytes(h[:])
+		yy.Square(&p.Y)
+		num.Sub(&one, &yy)
+		den.Mul(&params.D, &yy).Sub(&params.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		num.Div(&num, &den)
+		if p.X.Sqrt(&num) == nil {
+			continue
+		}
+		if p.X.LexicographicallyLargest() {
+			p.X.Neg(&p.X)
+		}
+
+		p.ScalarMul(&p, &cofactor)
+		if p.X.IsZero() {
+			continue
+		}
+		return p
+	}
+}
+
+// HashPoint returns the Pedersen hash of msg, as a point of the twisted Edwards curve
+func HashPoint(msg []byte) twistededwards.Point {
+	bits := make([]bool, 8*len(msg))
+	for i, b := range msg {
+		for j := 0; j < 8; j++ {
+			bits[8*i+j] = (b>>j)&1 == 1
+		}
+	}
+
+	params := twistededwards.GetEdwardsCurve()
+
+	var res twistededwards.Point
+	res.Y.SetOne()
+
+	bit := func(i int) int64 {
+		if i < len(bits) && bits[i] {
+			return 1
+		}
+		return 0
+	}
+
+	for segment, start := 0, 0; start < len(bits); segment, start = segment+1, start+3*NbChunksPerSegment {
+		var scalar, enc big.Int
+		for j := 0; j < NbChunksPerSegment && start+3*j < len(bits); j++ {
+			c := start + 3*j
+			enc.SetInt64((1 + bit(c) + 2*bit(c+1)) * (1 - 2*bit(c+2)))
+			scalar.Add(&scalar, enc.Lsh(&enc, uint(4*j)))
+		}
+		scalar.Mod(&scalar, &params.Order)
+
+		var p twistededwards.Point
+		g := Generator(segment)
+		p.ScalarMul(&g, &scalar)
+		res.Add(&res, &p)
+	}
+
+	return res
+}
+
+// digest accumulates the message, hashed when Sum is called
+type digest struct {
+	data []byte
+}
+
+// New returns a Pedersen hash.Hash, whose checksum is the x coordinate of HashPoint
+// (big-endian, Size bytes)
+func New() hash.Hash {
+	return &digest{}
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	p := HashPoint(d.data)
+	x := p.X.Bytes()
+	return append(b, x[:]...)
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return Size
+}
+
+// BlockSize returns the hash's underlying block size.
+func (d *digest) BlockSize() int {
+	return 1
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+// It never returns an error.
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.data = append(d.data, p...)
+	return len(p), nil
+}
+
+// Sum returns the Pedersen hash of msg
+func Sum(msg []byte) []byte {
+	d := New()
+	_, _ = d.Write(msg)
+	return d.Sum(nil)
+}
+`
+
+const pedersenTestTemplate = `
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+)
+
+func TestGenerators(t *testing.T) {
+	params := twistededwards.GetEdwardsCurve()
+
+	for i := 0; i < 4; i++ {
+		g := Generator(i)
+		if !g.IsOnCurve() {
+			t.Fatal("generator not on curve", i)
+		}
+		var p, identity twistededwards.Point
+		identity.Y.SetOne()
+		p.ScalarMul(&g, &params.Order)
+		if !p.Equal(&identity) {
+			t.Fatal("generator not in the subgroup", i)
+		}
+		if i > 0 && g.Equal(&generators[i-1]) {
+			t.Fatal("generators should be distinct", i)
+		}
+	}
+
+	// the scalar of a segment is in ]-order/2, order/2[
+	var bound, halfOrder big.Int
+	bound.Lsh(big.NewInt(1), 4*NbChunksPerSegment).Sub(&bound, big.NewInt(1)).Mul(&bound, big.NewInt(4)).Div(&bound, big.NewInt(15))
+	halfOrder.Rsh(&params.Order, 1)
+	if bound.Cmp(&halfOrder) >= 0 {
+		t.Fatal("too many chunks per segment")
+	}
+}
+
+func TestPedersen(t *testing.T) {
+	msg := []byte("the quick brown fox jumps over the lazy dog, twice: the quick brown fox jumps over the lazy dog")
+
+	p := HashPoint(msg)
+	if !p.IsOnCurve() {
+		t.Fatal("hash not on curve")
+	}
+
+	h := New()
+	_, _ = h.Write(msg[:10])
+	_, _ = h.Write(msg[10:])
+	x := p.X.Bytes()
+	if !bytes.Equal(h.Sum(nil), x[:]) || !bytes.Equal(Sum(msg), x[:]) || h.Size() != len(x) {
+		t.Fatal("the checksum should be the x coordinate of the hash")
+	}
+
+	// trailing zeros change the hash
+	if bytes.Equal(Sum(msg), Sum(append(msg, 0))) {
+		t.Fatal("padding collision")
+	}
+
+	// 0x0d = 0b00001101: the bits (lsb first) are 101 100 00, so the chunks are (1, 0, 1), (1, 0, 0)
+	// and (0, 0, 0) once padded
+	var expected twistededwards.Point
+	var scalar big.Int
+	scalar.SetInt64(-(1 + 1) + (1+1)*16 + 1*256)
+	g := Generator(0)
+	expected.ScalarMul(&g, &scalar)
+	single := HashPoint([]byte{0x0d})
+	if !single.Equal(&expected) {
+		t.Fatal("unexpected hash of a single byte")
+	}
+}
+`
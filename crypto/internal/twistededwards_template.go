@@ -0,0 +1,344 @@
+package main
+
+const twistedEdwardsCurveTemplate = `
+
+{{ define "edwards_params" }}
+
+{{ if eq .Curve "BLS377" }}
+	// initEdBLS377 sets the parameters of the twisted Edwards curve -x^2 + y^2 = 1 + 3021*x^2*y^2,
+	// defined on the scalar field of BLS377 (same as ed_on_bls12_377 in arkworks)
+	func initEdBLS377() {
+
+		edwards.A.SetOne().Neg(&edwards.A)
+		edwards.D.SetUint64(3021)
+		edwards.Cofactor.SetUint64(4).FromMont()
+		edwards.Order.SetString("2111115437357092606062206234695386632838870926408408195193685246394721360383", 10)
+
+		edwards.Base.X.SetString("4497879464030519973909970603271755437257548612157028181994697785683032656389")
+		edwards.Base.Y.SetString("4357141146396347889246900916607623952598927460421559113092863576544024487809")
+	}
+{{ else if eq .Curve "BW761" }}
+	// initEdBW761 sets the parameters of the twisted Edwards curve -x^2 + y^2 = 1 + 79743*x^2*y^2,
+	// defined on the scalar field of BW761 (same as ed_on_bw6_761 in arkworks)
+	func initEdBW761() {
+
+		edwards.A.SetOne().Neg(&edwards.A)
+		edwards.D.SetUint64(79743)
+		edwards.Cofactor.SetUint64(8).FromMont()
+		edwards.Order.SetString("32333053251621136751331591711861691692049189094364332567435817881934511297123972799646723302813083835942624121493", 10)
+
+		edwards.Base.X.SetString("174701772324485506941690903512423551998294352968833659960042362742684869862495746426366187462669992073196420267127")
+		edwards.Base.Y.SetString("208487200052258845495340374451540775445408439654930191324011635560142523886549663106522691296420655144190624954833")
+	}
+{{end}}
+
+{{end}}
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+// CurveParams stores the parameters of the twisted Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2
+type CurveParams struct {
+	A, D     fr.Element // in Montgomery form
+	Cofactor fr.Element // not in Montgomery form
+	Order    big.Int    // order of the subgroup generated by Base
+	Base     Point
+}
+
+var edwards CurveParams
+var initOnce sync.Once
+
+// GetEdwardsCurve returns the parameters of the twisted Edwards curve embedded in {{.Curve}}
+func GetEdwardsCurve() CurveParams {
+	initOnce.Do(initEd{{.Curve}})
+	return edwards
+}
+
+{{ template "edwards_params" . }}
+`
+
+const twistedEdwardsPointTemplate = `
+
+import (
+	"math/big"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+// Point point on a twisted Edwards curve, in affine coordinates
+type Point struct {
+	X, Y fr.Element
+}
+
+// PointProj point on a twisted Edwards curve, in projective coordinates
+type PointProj struct {
+	X, Y, Z fr.Element
+}
+
+// Set sets p to p1 and returns it
+func (p *PointProj) Set(p1 *PointProj) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// Set sets p to p1 and returns it
+func (p *Point) Set(p1 *Point) *Point {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	return p
+}
+
+// Equal returns true if p = p1
+func (p *Point) Equal(p1 *Point) bool {
+	return p.X.Equal(&p1.X) && p.Y.Equal(&p1.Y)
+}
+
+// Equal returns true if p = p1 (as affine points)
+func (p *PointProj) Equal(p1 *PointProj) bool {
+	if p.Z.IsZero() || p1.Z.IsZero() {
+		return false
+	}
+	var pAffine, p1Affine Point
+	pAffine.FromProj(p)
+	p1Affine.FromProj(p1)
+	return pAffine.Equal(&p1Affine)
+}
+
+// NewPoint creates a new instance of Point
+func NewPoint(x, y fr.Element) Point {
+	return Point{x, y}
+}
+
+// IsOnCurve checks if a point is on the twisted Edwards curve
+func (p *Point) IsOnCurve() bool {
+
+	ecurve := GetEdwardsCurve()
+
+	var lhs, rhs, tmp fr.Element
+
+	tmp.Mul(&p.Y, &p.Y)
+	lhs.Mul(&p.X, &p.X).
+		Mul(&lhs, &ecurve.A).
+		Add(&lhs, &tmp)
+
+	tmp.Mul(&p.X, &p.X).
+		Mul(&tmp, &p.Y).
+		Mul(&tmp, &p.Y).
+		Mul(&tmp, &ecurve.D)
+	rhs.SetOne().Add(&rhs, &tmp)
+
+	return lhs.Equal(&rhs)
+}
+
+// Add adds two points (x,y), (u,v) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *Point) Add(p1, p2 *Point) *Point {
+
+	ecurve := GetEdwardsCurve()
+
+	var xu, yv, xv, yu, dxyuv, one, denx, deny fr.Element
+	pRes := new(Point)
+	xv.Mul(&p1.X, &p2.Y)
+	yu.Mul(&p1.Y, &p2.X)
+	pRes.X.Add(&xv, &yu)
+
+	xu.Mul(&p1.X, &p2.X).Mul(&xu, &ecurve.A)
+	yv.Mul(&p1.Y, &p2.Y)
+	pRes.Y.Sub(&yv, &xu)
+
+	dxyuv.Mul(&xv, &yu).Mul(&dxyuv, &ecurve.D)
+	one.SetOne()
+	denx.Add(&one, &dxyuv)
+	deny.Sub(&one, &dxyuv)
+
+	p.X.Div(&pRes.X, &denx)
+	p.Y.Div(&pRes.Y, &deny)
+
+	return p
+}
+
+// Double doubles point (x,y) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *Point) Double(p1 *Point) *Point {
+	p.Add(p1, p1)
+	return p
+}
+
+// Neg negates point (x,y) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *Point) Neg(p1 *Point) *Point {
+	p.X.Neg(&p1.X)
+	p.Y.Set(&p1.Y)
+	return p
+}
+
+// FromProj sets p in affine from p in projective
+func (p *Point) FromProj(p1 *PointProj) *Point {
+	p.X.Div(&p1.X, &p1.Z)
+	p.Y.Div(&p1.Y, &p1.Z)
+	return p
+}
+
+// FromAffine sets p in projective from p in affine
+func (p *PointProj) FromAffine(p1 *Point) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.SetOne()
+	return p
+}
+
+// Add adds points in projective coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
+func (p *PointProj) Add(p1, p2 *PointProj) *PointProj {
+
+	var res PointProj
+
+	ecurve := GetEdwardsCurve()
+
+	var A, B, C, D, E, F, G, H, I fr.Element
+	A.Mul(&p1.Z, &p2.Z)
+	B.Square(&A)
+	C.Mul(&p1.X, &p2.X)
+	D.Mul(&p1.Y, &p2.Y)
+	E.Mul(&ecurve.D, &C).Mul(&E, &D)
+	F.Sub(&B, &E)
+	G.Add(&B, &E)
+	H.Add(&p1.X, &p1.Y)
+	I.Add(&p2.X, &p2.Y)
+	res.X.Mul(&H, &I).
+		Sub(&res.X, &C).
+		Sub(&res.X, &D).
+		Mul(&res.X, &A).
+		Mul(&res.X, &F)
+	H.Mul(&ecurve.A, &C)
+	res.Y.Sub(&D, &H).
+		Mul(&res.Y, &A).
+		Mul(&res.Y, &G)
+	res.Z.Mul(&F, &G)
+
+	p.Set(&res)
+	return p
+}
+
+// Double doubles a point in projective coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
+func (p *PointProj) Double(p1 *PointProj) *PointProj {
+
+	var res PointProj
+
+	ecurve := GetEdwardsCurve()
+
+	var B, C, D, E, F, H, J, tmp fr.Element
+
+	B.Add(&p1.X, &p1.Y).Square(&B)
+	C.Square(&p1.X)
+	D.Square(&p1.Y)
+	E.Mul(&ecurve.A, &C)
+	F.Add(&E, &D)
+	H.Square(&p1.Z)
+	tmp.Double(&H)
+	J.Sub(&F, &tmp)
+	res.X.Sub(&B, &C).
+		Sub(&res.X, &D).
+		Mul(&res.X, &J)
+	res.Y.Sub(&E, &D).Mul(&res.Y, &F)
+	res.Z.Mul(&F, &J)
+
+	p.Set(&res)
+	return p
+}
+
+// Neg negates point (x,y) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *PointProj) Neg(p1 *PointProj) *PointProj {
+	p.X.Neg(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// ScalarMul scalar multiplication of a point
+// p1 points on the twisted Edwards curve
+// scalar non negative integer
+// modifies p
+func (p *Point) ScalarMul(p1 *Point, scalar *big.Int) *Point {
+
+	var resProj, p1Proj PointProj
+	resProj.X.SetZero()
+	resProj.Y.SetOne()
+	resProj.Z.SetOne()
+
+	p1Proj.FromAffine(p1)
+
+	for i := scalar.BitLen() - 1; i >= 0; i-- {
+		resProj.Double(&resProj)
+		if scalar.Bit(i) == 1 {
+			resProj.Add(&resProj, &p1Proj)
+		}
+	}
+
+	p.FromProj(&resProj)
+
+	return p
+}
+`
+
+const twistedEdwardsTestTemplate = `
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBase(t *testing.T) {
+	params := GetEdwardsCurve()
+
+	if !params.Base.IsOnCurve() {
+		t.Fatal("base point not on curve")
+	}
+
+	var p, identity Point
+	identity.Y.SetOne()
+	p.ScalarMul(&params.Base, &params.Order)
+	if !p.Equal(&identity) {
+		t.Fatal("the order of the base point should be the order of the subgroup")
+	}
+}
+
+func TestAddDouble(t *testing.T) {
+	params := GetEdwardsCurve()
+
+	// 2B + 3B = 5B, in affine and projective coordinates
+	var b2, b3, b5, sum Point
+	b2.Double(&params.Base)
+	b3.ScalarMul(&params.Base, big.NewInt(3))
+	b5.ScalarMul(&params.Base, big.NewInt(5))
+
+	sum.Add(&b2, &b3)
+	if !sum.Equal(&b5) || !sum.IsOnCurve() {
+		t.Fatal("2B + 3B should be 5B")
+	}
+
+	var b2Proj, b3Proj, sumProj PointProj
+	b2Proj.FromAffine(&b2)
+	b3Proj.FromAffine(&b3)
+	sumProj.Add(&b2Proj, &b3Proj)
+	sum.FromProj(&sumProj)
+	if !sum.Equal(&b5) {
+		t.Fatal("2B + 3B should be 5B in projective coordinates")
+	}
+
+	var neg Point
+	neg.Neg(&b5)
+	sum.Add(&b5, &neg)
+	if !sum.X.IsZero() || !sum.IsOnCurve() {
+		t.Fatal("5B - 5B should be the identity")
+	}
+}
+`
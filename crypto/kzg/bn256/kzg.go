@@ -0,0 +1,434 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package bn256
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gurvy/bn256/fr"
+
+	curve "github.com/consensys/gurvy/bn256"
+
+	"github.com/consensys/gurvy"
+)
+
+var (
+	errInvalidNbDigests   = errors.New("number of digests is not the same as the number of polynomials")
+	errUnsupportedSize    = errors.New("the size of the polynomials exceeds the capacity of the SRS")
+	errVerifyOpeningProof = errors.New("can't verify opening proof")
+	errInvalidPoint       = errors.New("points in the opening proof are not in the correct subgroup")
+)
+
+// Digest commitment of a polynomial
+type Digest = curve.G1Affine
+
+// SRS stores the result of the MPC (structured reference string) of a KZG commitment scheme
+// G1 = [1]1, [α]1, [α²]1, ... , [αⁿ⁻¹]1
+// G2 = [1]2, [α]2
+type SRS struct {
+	G1 []curve.G1Affine
+	G2 [2]curve.G2Affine
+}
+
+// NewSRS returns a new SRS using alpha as randomness source
+//
+// In production, a SRS generated through MPC should be used.
+func NewSRS(size uint64, bAlpha *big.Int) (*SRS, error) {
+	if size == 0 {
+		return nil, errUnsupportedSize
+	}
+	var srs SRS
+	_, _, gen1Aff, gen2Aff := curve.Generators()
+
+	var alpha fr.Element
+	alpha.SetBigInt(bAlpha)
+
+	// scalars are given in regular form to the batch scalar multiplication
+	alphas := make([]fr.Element, size)
+	alphas[0].SetOne()
+	for i := 1; i < len(alphas); i++ {
+		alphas[i].Mul(&alphas[i-1], &alpha)
+	}
+	for i := 0; i < len(alphas); i++ {
+		alphas[i].FromMont()
+	}
+	srs.G1 = curve.BatchScalarMultiplicationG1(&gen1Aff, alphas)
+
+	srs.G2[0] = gen2Aff
+	srs.G2[1].ScalarMultiplication(&gen2Aff, bAlpha)
+
+	return &srs, nil
+}
+
+// GetCurveID returns the curveID
+func (srs *SRS) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+// Size returns the number of G1 points in the SRS, that is the maximum number of
+// coefficients a committed polynomial can have
+func (srs *SRS) Size() int {
+	return len(srs.G1)
+}
+
+// OpeningProof KZG proof for opening at a single point.
+type OpeningProof struct {
+	// H quotient polynomial (f - f(z))/(x-z)
+	H curve.G1Affine
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof opening proof for many polynomials at the same point
+type BatchOpeningProof struct {
+	// H quotient polynomial Sum_i gamma**i*(f - f(z))/(x-z)
+	H curve.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+}
+
+// Commit commits to a polynomial using a multi exponentiation with the SRS.
+// p is given in canonical basis (coefficients in Montgomery form)
+func Commit(p []fr.Element, srs *SRS) (Digest, error) {
+
+	var res Digest
+	if len(p) > len(srs.G1) {
+		return res, errUnsupportedSize
+	}
+	if len(p) == 0 {
+		return res, nil
+	}
+
+	// the multi exponentiation expects scalars in regular form
+	scalars := make([]fr.Element, len(p))
+	for i := 0; i < len(p); i++ {
+		scalars[i] = p[i].ToRegular()
+	}
+	res.MultiExp(srs.G1[:len(p)], scalars)
+
+	return res, nil
+}
+
+// Open computes an opening proof of polynomial p at given point.
+func Open(p []fr.Element, point *fr.Element, srs *SRS) (OpeningProof, error) {
+
+	var res OpeningProof
+	if len(p) == 0 || len(p) > len(srs.G1) {
+		return res, errUnsupportedSize
+	}
+
+	// compute the claimed value and the quotient (p - p(point)) / (X - point)
+	res.ClaimedValue = Eval(p, point)
+	h := dividePolyByXminusA(p, res.ClaimedValue, *point)
+
+	// commit to H
+	hCommit, err := Commit(h, srs)
+	if err != nil {
+		return res, err
+	}
+	res.H.Set(&hCommit)
+
+	return res, nil
+}
+
+// Verify verifies a KZG opening proof at a single point
+func Verify(commitment *Digest, proof *OpeningProof, point *fr.Element, srs *SRS) error {
+	return BatchVerifyMultiPoints([]Digest{*commitment}, []OpeningProof{*proof}, []fr.Element{*point}, srs)
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of polynomials.
+// It's an interactive protocol, made non interactive using Fiat Shamir.
+// point is the point at which the polynomials are opened.
+// digests is the list of committed polynomials to open, need to derive the challenge using Fiat Shamir.
+// polynomials is the list of polynomials to open.
+func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point *fr.Element, srs *SRS) (BatchOpeningProof, error) {
+
+	var res BatchOpeningProof
+
+	// check for invalid sizes
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(polynomials) {
+		return res, errInvalidNbDigests
+	}
+	largestPoly := -1
+	for _, p := range polynomials {
+		if len(p) > len(srs.G1) {
+			return res, errUnsupportedSize
+		}
+		if len(p) > largestPoly {
+			largestPoly = len(p)
+		}
+	}
+
+	// compute the purported values
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		res.ClaimedValues[i] = Eval(polynomials[i], point)
+	}
+
+	// derive the challenge gamma, binded to the point and the commitments
+	gamma := deriveGamma(point, digests, res.ClaimedValues)
+
+	// compute sum_i gamma**i*f and sum_i gamma**i*f(a)
+	var sumGammaiTimesEval fr.Element
+	sumGammaiTimesEval.Set(&res.ClaimedValues[nbDigests-1])
+	for i := nbDigests - 2; i >= 0; i-- {
+		sumGammaiTimesEval.Mul(&sumGammaiTimesEval, &gamma).
+			Add(&sumGammaiTimesEval, &res.ClaimedValues[i])
+	}
+
+	sumGammaiTimesPol := make([]fr.Element, largestPoly)
+	for i := nbDigests - 1; i >= 0; i-- {
+		for j := 0; j < len(sumGammaiTimesPol); j++ {
+			sumGammaiTimesPol[j].Mul(&sumGammaiTimesPol[j], &gamma)
+			if j < len(polynomials[i]) {
+				sumGammaiTimesPol[j].Add(&sumGammaiTimesPol[j], &polynomials[i][j])
+			}
+		}
+	}
+
+	// compute H
+	h := dividePolyByXminusA(sumGammaiTimesPol, sumGammaiTimesEval, *point)
+	hCommit, err := Commit(h, srs)
+	if err != nil {
+		return res, err
+	}
+	res.H.Set(&hCommit)
+
+	return res, nil
+}
+
+// FoldProof folds the digests and the proofs in batchOpeningProof using Fiat Shamir
+// to obtain an opening proof at a single point.
+//
+// * digests list of digests on which batchOpeningProof is based
+// * batchOpeningProof opening proof of digests
+// * returns the folded version of batchOpeningProof, Digest, the folded version of digests
+func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point *fr.Element) (OpeningProof, Digest, error) {
+
+	nbDigests := len(digests)
+
+	// check consistancy between numbers of claims vs number of digests
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return OpeningProof{}, Digest{}, errInvalidNbDigests
+	}
+
+	// derive the challenge gamma, binded to the point and the commitments
+	gamma := deriveGamma(point, digests, batchOpeningProof.ClaimedValues)
+
+	// fold the claimed values and digests
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	foldedDigests, foldedEvaluations := fold(digests, batchOpeningProof.ClaimedValues, gammai)
+
+	// create the folded opening proof
+	var res OpeningProof
+	res.ClaimedValue.Set(&foldedEvaluations)
+	res.H.Set(&batchOpeningProof.H)
+
+	return res, foldedDigests, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+//
+// * digests list of digests on which opening proof is done
+// * batchOpeningProof proof of correct opening on the digests
+func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningProof, point *fr.Element, srs *SRS) error {
+
+	// fold the proof
+	foldedProof, foldedDigest, err := FoldProof(digests, batchOpeningProof, point)
+	if err != nil {
+		return err
+	}
+
+	// verify the foldedProof againts the foldedDigest
+	return Verify(&foldedDigest, &foldedProof, point, srs)
+}
+
+// BatchVerifyMultiPoints batch verifies a list of opening proofs at different points.
+// The purpose of the batching is to have only one pairing for verifying several proofs.
+//
+// * digests list of committed polynomials
+// * proofs list of opening proofs, one for each digest
+// * points the list of points at which the opening are done
+func BatchVerifyMultiPoints(digests []Digest, proofs []OpeningProof, points []fr.Element, srs *SRS) error {
+
+	// check consistancy nb proogs vs nb digests
+	if len(digests) == 0 || len(digests) != len(proofs) || len(digests) != len(points) {
+		return errInvalidNbDigests
+	}
+
+	// ensure the points in the proofs are in the correct subgroup
+	for i := 0; i < len(proofs); i++ {
+		if !proofs[i].H.IsInSubGroup() {
+			return errInvalidPoint
+		}
+	}
+
+	// sample random numbers for sampling; the first one is set to 1
+	randomNumbers := make([]fr.Element, len(digests))
+	randomNumbers[0].SetOne()
+	for i := 1; i < len(randomNumbers); i++ {
+		if _, err := randomNumbers[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// fold the committed quotients, Σ rᵢ.[Hᵢ]1
+	var foldedQuotients curve.G1Affine
+	quotients := make([]curve.G1Affine, len(proofs))
+	for i := 0; i < len(randomNumbers); i++ {
+		quotients[i].Set(&proofs[i].H)
+	}
+	foldedQuotients.MultiExp(quotients, toRegular(randomNumbers))
+
+	// fold digests and evals, Σ rᵢ.[fᵢ]1 and Σ rᵢ.fᵢ(zᵢ)
+	evals := make([]fr.Element, len(digests))
+	for i := 0; i < len(randomNumbers); i++ {
+		evals[i].Set(&proofs[i].ClaimedValue)
+	}
+	foldedDigests, foldedEvals := fold(digests, evals, randomNumbers)
+
+	// compute commitment to folded Eval, [Σ rᵢ.fᵢ(zᵢ)]1
+	var foldedEvalsCommit curve.G1Affine
+	var bFoldedEvals big.Int
+	foldedEvals.ToBigIntRegular(&bFoldedEvals)
+	foldedEvalsCommit.ScalarMultiplication(&srs.G1[0], &bFoldedEvals)
+
+	// compute foldedDigests = Σ rᵢ.[fᵢ]1 - [Σ rᵢ.fᵢ(zᵢ)]1
+	var foldedDigestsJac curve.G1Jac
+	foldedDigestsJac.FromAffine(&foldedDigests)
+	foldedEvalsCommit.Neg(&foldedEvalsCommit)
+	foldedDigestsJac.AddMixed(&foldedEvalsCommit)
+
+	// combine the points and the quotients using rᵢ.zᵢ, Σ rᵢ.zᵢ.[Hᵢ]1
+	for i := 0; i < len(randomNumbers); i++ {
+		randomNumbers[i].Mul(&randomNumbers[i], &points[i])
+	}
+	var foldedPointsQuotients curve.G1Affine
+	foldedPointsQuotients.MultiExp(quotients, toRegular(randomNumbers))
+
+	// Σ rᵢ.[fᵢ]1 - [Σ rᵢ.fᵢ(zᵢ)]1 + Σ rᵢ.zᵢ.[Hᵢ]1
+	foldedDigestsJac.AddMixed(&foldedPointsQuotients)
+	foldedDigests.FromJacobian(&foldedDigestsJac)
+
+	// e(Σ rᵢ.[fᵢ]1 - [Σ rᵢ.fᵢ(zᵢ)]1 + Σ rᵢ.zᵢ.[Hᵢ]1, [1]2).e(-Σ rᵢ.[Hᵢ]1, [α]2) == 1
+	foldedQuotients.Neg(&foldedQuotients)
+	check, err := pairingCheck(
+		[]curve.G1Affine{foldedDigests, foldedQuotients},
+		[]curve.G2Affine{srs.G2[0], srs.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return errVerifyOpeningProof
+	}
+
+	return nil
+}
+
+// Eval evaluates p at v, p being given in canonical basis
+func Eval(p []fr.Element, v *fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, v).Add(&res, &p[i])
+	}
+	return res
+}
+
+// fold computes two combinations of digests and evaluations using the coefficients
+// Σ coefficientsᵢ.[fᵢ]1 and Σ coefficientsᵢ.fᵢ(z)
+func fold(digests []Digest, evaluations []fr.Element, coefficients []fr.Element) (Digest, fr.Element) {
+
+	// fold the evaluations
+	var foldedEvaluations, tmp fr.Element
+	for i := 0; i < len(digests); i++ {
+		tmp.Mul(&evaluations[i], &coefficients[i])
+		foldedEvaluations.Add(&foldedEvaluations, &tmp)
+	}
+
+	// fold the digests
+	var foldedDigests Digest
+	foldedDigests.MultiExp(digests, toRegular(coefficients))
+
+	return foldedDigests, foldedEvaluations
+}
+
+// dividePolyByXminusA computes (f-f(a))/(x-a), in canonical basis, in regular form
+func dividePolyByXminusA(f []fr.Element, fa, a fr.Element) []fr.Element {
+
+	// copy f so that it is not modified
+	res := make([]fr.Element, len(f))
+	copy(res, f)
+
+	// first we compute f-f(a)
+	res[0].Sub(&res[0], &fa)
+
+	// now we use syntetic division to divide by x-a
+	var t fr.Element
+	for i := len(res) - 2; i >= 0; i-- {
+		t.Mul(&res[i+1], &a)
+		res[i].Add(&res[i], &t)
+	}
+
+	// the result is of degree deg(f)-1
+	return res[1:]
+}
+
+// deriveGamma derives a challenge using Fiat Shamir to fold proofs.
+func deriveGamma(point *fr.Element, digests []Digest, claimedValues []fr.Element) fr.Element {
+
+	h := sha256.New()
+	h.Write([]byte("gamma"))
+
+	bPoint := point.Bytes()
+	h.Write(bPoint[:])
+	for i := 0; i < len(digests); i++ {
+		h.Write(digests[i].Marshal())
+	}
+	for i := 0; i < len(claimedValues); i++ {
+		bValue := claimedValues[i].Bytes()
+		h.Write(bValue[:])
+	}
+
+	var gamma fr.Element
+	gamma.SetBytes(h.Sum(nil))
+
+	return gamma
+}
+
+// pairingCheck returns true if e(P[0], Q[0]).e(P[1], Q[1])... == 1
+func pairingCheck(P []curve.G1Affine, Q []curve.G2Affine) (bool, error) {
+	return curve.PairingCheck(P, Q)
+}
+
+// toRegular returns a copy of v in regular form, as expected by the multi exponentiation
+func toRegular(v []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(v))
+	for i := 0; i < len(v); i++ {
+		res[i] = v[i].ToRegular()
+	}
+	return res
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kzg implements the KZG polynomial commitment scheme (https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf)
+//
+// The curve-typed implementations of the scheme (commit, open, batch open and verify) are
+// in the sub packages kzg/bls377, kzg/bls381, kzg/bn256 and kzg/bw761.
+// This package creates, serializes and loads structured reference strings (SRS) for any curve.
+package kzg
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/consensys/gurvy"
+
+	kzg_bls377 "github.com/consensys/gnark/crypto/kzg/bls377"
+	kzg_bls381 "github.com/consensys/gnark/crypto/kzg/bls381"
+	kzg_bn256 "github.com/consensys/gnark/crypto/kzg/bn256"
+	kzg_bw761 "github.com/consensys/gnark/crypto/kzg/bw761"
+	gnarkio "github.com/consensys/gnark/io"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+// SRS represents the structured reference string of a KZG commitment scheme
+//
+// it's underlying implementation is curve specific (see gnark/crypto/kzg/...)
+type SRS interface {
+	gnarkio.WriterRawTo
+	io.WriterTo
+	io.ReaderFrom
+	GetCurveID() gurvy.ID
+	Size() int
+}
+
+// NewSRS instantiates a curve-typed SRS and returns an interface object
+// This function exists for serialization purposes
+func NewSRS(curveID gurvy.ID) SRS {
+	var srs SRS
+	switch curveID {
+	case gurvy.BN256:
+		srs = &kzg_bn256.SRS{}
+	case gurvy.BLS377:
+		srs = &kzg_bls377.SRS{}
+	case gurvy.BLS381:
+		srs = &kzg_bls381.SRS{}
+	case gurvy.BW761:
+		srs = &kzg_bw761.SRS{}
+	default:
+		panic("not implemented")
+	}
+	return srs
+}
+
+// NewTestSRS returns a SRS of given size, sampling its secret at random.
+//
+// This is meant for test purposes only, as the party calling NewTestSRS knows the secret.
+// In production, a SRS generated through MPC should be used (see ReadPtau).
+func NewTestSRS(curveID gurvy.ID, size uint64) (SRS, error) {
+	switch curveID {
+	case gurvy.BN256:
+		var alpha fr_bn256.Element
+		if _, err := alpha.SetRandom(); err != nil {
+			return nil, err
+		}
+		srs, err := kzg_bn256.NewSRS(size, alpha.ToBigIntRegular(new(big.Int)))
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case gurvy.BLS377:
+		var alpha fr_bls377.Element
+		if _, err := alpha.SetRandom(); err != nil {
+			return nil, err
+		}
+		srs, err := kzg_bls377.NewSRS(size, alpha.ToBigIntRegular(new(big.Int)))
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case gurvy.BLS381:
+		var alpha fr_bls381.Element
+		if _, err := alpha.SetRandom(); err != nil {
+			return nil, err
+		}
+		srs, err := kzg_bls381.NewSRS(size, alpha.ToBigIntRegular(new(big.Int)))
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case gurvy.BW761:
+		var alpha fr_bw761.Element
+		if _, err := alpha.SetRandom(); err != nil {
+			return nil, err
+		}
+		srs, err := kzg_bw761.NewSRS(size, alpha.ToBigIntRegular(new(big.Int)))
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	default:
+		panic("not implemented")
+	}
+}
+
+// ReadPtau reads the first size powers of τ of a powers of tau file, generated by a public
+// ceremony in the snarkjs format (.ptau), and returns the corresponding SRS.
+func ReadPtau(curveID gurvy.ID, r io.Reader, size uint64) (SRS, error) {
+	switch curveID {
+	case gurvy.BN256:
+		srs, err := kzg_bn256.ReadPtau(r, size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case gurvy.BLS377:
+		srs, err := kzg_bls377.ReadPtau(r, size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case gurvy.BLS381:
+		srs, err := kzg_bls381.ReadPtau(r, size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	case gurvy.BW761:
+		srs, err := kzg_bw761.ReadPtau(r, size)
+		if err != nil {
+			return nil, err
+		}
+		return srs, nil
+	default:
+		panic("not implemented")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/bls377/fr"
+)
+
+// CurveParams stores the parameters of the twisted Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2
+type CurveParams struct {
+	A, D     fr.Element // in Montgomery form
+	Cofactor fr.Element // not in Montgomery form
+	Order    big.Int    // order of the subgroup generated by Base
+	Base     Point
+}
+
+var edwards CurveParams
+var initOnce sync.Once
+
+// GetEdwardsCurve returns the parameters of the twisted Edwards curve embedded in BLS377
+func GetEdwardsCurve() CurveParams {
+	initOnce.Do(initEdBLS377)
+	return edwards
+}
+
+// initEdBLS377 sets the parameters of the twisted Edwards curve -x^2 + y^2 = 1 + 3021*x^2*y^2,
+// defined on the scalar field of BLS377 (same as ed_on_bls12_377 in arkworks)
+func initEdBLS377() {
+
+	edwards.A.SetOne().Neg(&edwards.A)
+	edwards.D.SetUint64(3021)
+	edwards.Cofactor.SetUint64(4).FromMont()
+	edwards.Order.SetString("2111115437357092606062206234695386632838870926408408195193685246394721360383", 10)
+
+	edwards.Base.X.SetString("4497879464030519973909970603271755437257548612157028181994697785683032656389")
+	edwards.Base.Y.SetString("4357141146396347889246900916607623952598927460421559113092863576544024487809")
+}
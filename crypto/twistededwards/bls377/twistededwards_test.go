@@ -0,0 +1,68 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBase(t *testing.T) {
+	params := GetEdwardsCurve()
+
+	if !params.Base.IsOnCurve() {
+		t.Fatal("base point not on curve")
+	}
+
+	var p, identity Point
+	identity.Y.SetOne()
+	p.ScalarMul(&params.Base, &params.Order)
+	if !p.Equal(&identity) {
+		t.Fatal("the order of the base point should be the order of the subgroup")
+	}
+}
+
+func TestAddDouble(t *testing.T) {
+	params := GetEdwardsCurve()
+
+	// 2B + 3B = 5B, in affine and projective coordinates
+	var b2, b3, b5, sum Point
+	b2.Double(&params.Base)
+	b3.ScalarMul(&params.Base, big.NewInt(3))
+	b5.ScalarMul(&params.Base, big.NewInt(5))
+
+	sum.Add(&b2, &b3)
+	if !sum.Equal(&b5) || !sum.IsOnCurve() {
+		t.Fatal("2B + 3B should be 5B")
+	}
+
+	var b2Proj, b3Proj, sumProj PointProj
+	b2Proj.FromAffine(&b2)
+	b3Proj.FromAffine(&b3)
+	sumProj.Add(&b2Proj, &b3Proj)
+	sum.FromProj(&sumProj)
+	if !sum.Equal(&b5) {
+		t.Fatal("2B + 3B should be 5B in projective coordinates")
+	}
+
+	var neg Point
+	neg.Neg(&b5)
+	sum.Add(&b5, &neg)
+	if !sum.X.IsZero() || !sum.IsOnCurve() {
+		t.Fatal("5B - 5B should be the identity")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/bw761/fr"
+)
+
+// CurveParams stores the parameters of the twisted Edwards curve a*x^2 + y^2 = 1 + d*x^2*y^2
+type CurveParams struct {
+	A, D     fr.Element // in Montgomery form
+	Cofactor fr.Element // not in Montgomery form
+	Order    big.Int    // order of the subgroup generated by Base
+	Base     Point
+}
+
+var edwards CurveParams
+var initOnce sync.Once
+
+// GetEdwardsCurve returns the parameters of the twisted Edwards curve embedded in BW761
+func GetEdwardsCurve() CurveParams {
+	initOnce.Do(initEdBW761)
+	return edwards
+}
+
+// initEdBW761 sets the parameters of the twisted Edwards curve -x^2 + y^2 = 1 + 79743*x^2*y^2,
+// defined on the scalar field of BW761 (same as ed_on_bw6_761 in arkworks)
+func initEdBW761() {
+
+	edwards.A.SetOne().Neg(&edwards.A)
+	edwards.D.SetUint64(79743)
+	edwards.Cofactor.SetUint64(8).FromMont()
+	edwards.Order.SetString("32333053251621136751331591711861691692049189094364332567435817881934511297123972799646723302813083835942624121493", 10)
+
+	edwards.Base.X.SetString("174701772324485506941690903512423551998294352968833659960042362742684869862495746426366187462669992073196420267127")
+	edwards.Base.Y.SetString("208487200052258845495340374451540775445408439654930191324011635560142523886549663106522691296420655144190624954833")
+}
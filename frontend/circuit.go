@@ -27,12 +27,57 @@ type Circuit interface {
 // 2. it then calls circuit.Define(curveID, constraintSystem) to build the internal constraint system
 // from the declarative code
 //
-// 3. finally, it converts that to a R1CS
-func Compile(curveID gurvy.ID, circuit Circuit) (r1cs.R1CS, error) {
+// 3. it optimizes the constraint system (see OptimizationReport), unless WithoutOptimization is set
+//
+// 4. finally, it converts that to a R1CS
+//
+// If WithProfile is set, the call stack of each constraint is recorded (see Profile)
+func Compile(curveID gurvy.ID, circuit Circuit, opts ...CompileOption) (r1cs.R1CS, error) {
+
+	var config compileConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
 
 	// instantiate our constraint system
 	cs := newConstraintSystem()
 
+	// allocate the user inputs
+	if err := cs.allocateInputs(circuit); err != nil {
+		return nil, err
+	}
+
+	if config.profile != nil {
+		config.profile.reset()
+		cs.profile = config.profile
+	}
+
+	// call Define() to fill in the Constraints
+	if err := circuit.Define(curveID, &cs); err != nil {
+		return nil, err
+	}
+
+	if !config.noOptimization {
+		report := cs.optimize()
+		if config.optimizationReport != nil {
+			*config.optimizationReport = report
+		}
+	}
+
+	// return R1CS
+	//return cs.toR1CS(curveID), nil
+	res, err := cs.toR1CS(curveID)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// allocateInputs allocates the public and secret inputs of the circuit, and sets the circuit's
+// Variables to them
+func (cs *ConstraintSystem) allocateInputs(circuit Circuit) error {
+
 	// leaf handlers are called when encoutering leafs in the circuit data struct
 	// leafs are Constraints that need to be initialized in the context of compiling a circuit
 	var handler leafHandler = func(visibility backend.Visibility, name string, tInput reflect.Value) error {
@@ -59,21 +104,32 @@ func Compile(curveID gurvy.ID, circuit Circuit) (r1cs.R1CS, error) {
 	// recursively parse through reflection the circuits members to find all Constraints that need to be allOoutputcated
 	// (secret or public inputs)
 	if err := parseType(circuit, "", backend.Unset, handler); err != nil {
-		return nil, err
+		return err
 	}
 
-	// call Define() to fill in the Constraints
-	if err := circuit.Define(curveID, &cs); err != nil {
-		return nil, err
-	}
-	// return R1CS
-	//return cs.toR1CS(curveID), nil
-	res, err := cs.toR1CS(curveID)
-	if err != nil {
-		return nil, err
+	return nil
+}
+
+// ResetInputs sets the inputs of circuit back to unallocated Variables (keeping their assigned values),
+// such that a circuit which was compiled can be compiled again, for instance on another curve
+func ResetInputs(circuit Circuit) {
+	var handler leafHandler = func(visibility backend.Visibility, name string, tInput reflect.Value) error {
+		if tInput.CanSet() {
+			v := tInput.Interface().(Variable)
+			tInput.Set(reflect.ValueOf(Variable{Wire: Wire{val: v.val}}))
+		}
+		return nil
 	}
+	_ = parseType(circuit, "", backend.Unset, handler)
+}
 
-	return res, nil
+// CompileOption enables optional behaviours of Compile
+type CompileOption func(opt *compileConfig)
+
+type compileConfig struct {
+	noOptimization     bool
+	optimizationReport *OptimizationReport
+	profile            *Profile
 }
 
 // ParseWitness will returns a map[string]interface{} to be used as input in
@@ -1,6 +1,8 @@
 package frontend_test
 
 import (
+	"testing"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy"
 )
@@ -39,3 +41,33 @@ func (circuit *benchCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSys
 // 	}
 
 // }
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *cubicCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	x3 := cs.Mul(circuit.X, circuit.X, circuit.X)
+	cs.AssertIsEqual(circuit.Y, cs.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestResetInputs(t *testing.T) {
+	var circuit cubicCircuit
+	if _, err := frontend.Compile(gurvy.BN256, &circuit); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := frontend.Compile(gurvy.BLS381, &circuit); err == nil {
+		t.Fatal("compiling a compiled circuit should fail")
+	}
+
+	frontend.ResetInputs(&circuit)
+	r1cs, err := frontend.Compile(gurvy.BLS381, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(map[string]interface{}{"X": 3, "Y": 35}); err != nil {
+		t.Fatal(err)
+	}
+}
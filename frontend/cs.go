@@ -55,8 +55,9 @@ type ConstraintSystem struct {
 	}
 
 	// Constraints
-	constraints []r1c.R1C // list of R1C that yield an output (for example v3 == v1 * v2, return v3)
-	assertions  []r1c.R1C // list of R1C that yield no output (for example ensuring v1 == v2)
+	constraints []r1c.R1C  // list of R1C that yield an output (for example v3 == v1 * v2, return v3)
+	assertions  []r1c.R1C  // list of R1C that yield no output (for example ensuring v1 == v2)
+	hints       []r1c.Hint // list of internal variables computed by hint functions when solving the R1CS
 	oneTerm     r1c.Term
 
 	// Coefficients in the constraints
@@ -68,10 +69,15 @@ type ConstraintSystem struct {
 	debugInfo      []logEntry // list of logs storing information about assertions. If an assertion fails, it prints it in a friendly format
 	unsetVariables []logEntry // unset variables. If a variable is unset, the error is caught when compiling the circuit
 
+	profile *Profile // if set, records the call stack of each constraint (see WithProfile)
+	engine  *engine  // if set, solves the constraints as they are added (see Execute)
+
+	nbExecuted int // number of constraints solved by the engine, which are not kept
+
 }
 
 func (cs *ConstraintSystem) buildVarFromPartialVar(pv Wire) Variable {
-	return Variable{pv, cs.LinearExpression(cs.makeTerm(pv, bOne)), false}
+	return Variable{pv, cs.LinearExpression(cs.makeTerm(pv, bOne))}
 }
 
 // this has quite some impact on frontend performance, especially on large circuits size
@@ -163,7 +169,7 @@ func (cs *ConstraintSystem) makeTerm(v Wire, coeff *big.Int) r1c.Term {
 // The number returns included both the assertions and the non-assertion constraints
 // (eg: the constraints which creates a new variable)
 func (cs *ConstraintSystem) NbConstraints() int {
-	return len(cs.constraints) + len(cs.assertions)
+	return len(cs.constraints) + len(cs.assertions) + cs.nbExecuted
 }
 
 // LinearExpression packs a list of r1c.Term in a r1c.LinearExpression and returns it.
@@ -230,6 +236,42 @@ func (cs *ConstraintSystem) completeDanglingVariable(v *Variable) {
 	}
 }
 
+// booleans returns the set of boolean constrained wires of the visibility of v, and the id of v in it
+// if v is a single wire (with a coefficient 1), nil otherwise
+func (cs *ConstraintSystem) booleans(v Variable) (map[int]struct{}, int) {
+	if len(v.linExp) != 1 || v.linExp[0].CoeffValue() != 1 {
+		return nil, 0
+	}
+	switch v.linExp[0].ConstraintVisibility() {
+	case backend.Public:
+		return cs.public.booleans, v.linExp[0].VariableID()
+	case backend.Secret:
+		return cs.secret.booleans, v.linExp[0].VariableID()
+	case backend.Internal:
+		return cs.internal.booleans, v.linExp[0].VariableID()
+	default:
+		return nil, 0
+	}
+}
+
+// isBoolean returns true if v is a wire already constrained to be boolean
+func (cs *ConstraintSystem) isBoolean(v Variable) bool {
+	booleans, id := cs.booleans(v)
+	if booleans == nil {
+		return false
+	}
+	_, ok := booleans[id]
+	return ok
+}
+
+// markBoolean records that v is constrained to be boolean, so that it is not constrained twice
+// only single wires are recorded: the linear expressions are always constrained
+func (cs *ConstraintSystem) markBoolean(v Variable) {
+	if booleans, id := cs.booleans(v); booleans != nil {
+		booleans[id] = struct{}{}
+	}
+}
+
 // reduces redundancy in linear expression
 // Non deterministic function
 func (cs *ConstraintSystem) reduce(linExp r1c.LinearExpression) r1c.LinearExpression {
@@ -255,9 +297,30 @@ func (cs *ConstraintSystem) bigIntValue(term r1c.Term) big.Int {
 	return coeff
 }
 
+func (cs *ConstraintSystem) addConstraint(constraint r1c.R1C) {
+	// the engine solves the constraints as they are added: no R1CS is built
+	if cs.engine != nil {
+		cs.nbExecuted++
+		cs.engine.addConstraint(cs, constraint)
+		return
+	}
+	cs.constraints = append(cs.constraints, constraint)
+	if cs.profile != nil {
+		cs.profile.constraints = append(cs.profile.constraints, cs.profile.record())
+	}
+}
+
 func (cs *ConstraintSystem) addAssertion(constraint r1c.R1C, debugInfo logEntry) {
+	if cs.engine != nil {
+		cs.nbExecuted++
+		cs.engine.addAssertion(cs, constraint, debugInfo)
+		return
+	}
 	cs.assertions = append(cs.assertions, constraint)
 	cs.debugInfo = append(cs.debugInfo, debugInfo)
+	if cs.profile != nil {
+		cs.profile.assertions = append(cs.profile.assertions, cs.profile.record())
+	}
 }
 
 // toR1CS constructs a rank-1 constraint sytem
@@ -300,6 +363,21 @@ func (cs *ConstraintSystem) toR1CS(curveID gurvy.ID) (r1cs.R1CS, error) {
 		return nil
 	}
 
+	// hints wires are internal variables, only their inputs need to be offset
+	if len(cs.hints) != 0 {
+		res.Hints = make([]r1c.Hint, len(cs.hints))
+		for i, h := range cs.hints {
+			res.Hints[i] = r1c.Hint{ID: h.ID, WireID: h.WireID, Inputs: make([]r1c.LinearExpression, len(h.Inputs))}
+			for j := 0; j < len(h.Inputs); j++ {
+				res.Hints[i].Inputs[j] = make(r1c.LinearExpression, len(h.Inputs[j]))
+				copy(res.Hints[i].Inputs[j], h.Inputs[j])
+				if err := offsetIDs(res.Hints[i].Inputs[j]); err != nil {
+					return &res, err
+				}
+			}
+		}
+	}
+
 	var err error
 	for i := 0; i < len(res.Constraints); i++ {
 		err = offsetIDs(res.Constraints[i].L)
@@ -392,7 +470,7 @@ func (cs *ConstraintSystem) allocate(v Variable) Variable {
 		iv := cs.newInternalVariable()
 		one := cs.getOneVariable()
 		constraint := r1c.R1C{L: v.getLinExpCopy(), R: one.getLinExpCopy(), O: iv.getLinExpCopy(), Solver: r1c.SingleOutput}
-		cs.constraints = append(cs.constraints, constraint)
+		cs.addConstraint(constraint)
 		return iv
 	}
 	return v
@@ -21,6 +21,7 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/backend/r1cs/r1c"
 )
 
@@ -103,7 +104,7 @@ func (cs *ConstraintSystem) mulConstant(i interface{}, v Variable) Variable {
 		coeffCopy.Mul(&coeff, &lambda)
 		linExp = append(linExp, cs.makeTerm(Wire{constraintVis, variableID, nil}, &coeffCopy))
 	}
-	return Variable{Wire{}, linExp, false}
+	return Variable{Wire{}, linExp}
 }
 
 // Mul returns res = i1 * i2 * ... in
@@ -119,7 +120,7 @@ func (cs *ConstraintSystem) Mul(i1, i2 interface{}, in ...interface{}) Variable
 				cs.completeDanglingVariable(&t2)
 				_res = cs.newInternalVariable() // only in this case we record the constraint in the cs
 				constraint := r1c.R1C{L: t1.getLinExpCopy(), R: t2.getLinExpCopy(), O: _res.getLinExpCopy(), Solver: r1c.SingleOutput}
-				cs.constraints = append(cs.constraints, constraint)
+				cs.addConstraint(constraint)
 				return _res
 			default:
 				_res = cs.mulConstant(t2, t1)
@@ -163,7 +164,7 @@ func (cs *ConstraintSystem) Inverse(v Variable) Variable {
 	R := res.linExp
 	O := cs.LinearExpression(cs.getOneTerm())
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -182,11 +183,11 @@ func (cs *ConstraintSystem) Div(i1, i2 interface{}) Variable {
 		case Variable:
 			cs.completeDanglingVariable(&t2)
 			constraint := r1c.R1C{L: t2.linExp, R: res.linExp, O: t1.linExp, Solver: r1c.SingleOutput}
-			cs.constraints = append(cs.constraints, constraint)
+			cs.addConstraint(constraint)
 		default:
 			tmp := cs.Constant(t2)
 			constraint := r1c.R1C{L: tmp.getLinExpCopy(), R: res.getLinExpCopy(), O: t1.getLinExpCopy(), Solver: r1c.SingleOutput}
-			cs.constraints = append(cs.constraints, constraint)
+			cs.addConstraint(constraint)
 		}
 	default:
 		switch t2 := i2.(type) {
@@ -194,12 +195,12 @@ func (cs *ConstraintSystem) Div(i1, i2 interface{}) Variable {
 			cs.completeDanglingVariable(&t2)
 			tmp := cs.Constant(t1)
 			constraint := r1c.R1C{L: t2.getLinExpCopy(), R: res.getLinExpCopy(), O: tmp.getLinExpCopy(), Solver: r1c.SingleOutput}
-			cs.constraints = append(cs.constraints, constraint)
+			cs.addConstraint(constraint)
 		default:
 			tmp1 := cs.Constant(t1)
 			tmp2 := cs.Constant(t2)
 			constraint := r1c.R1C{L: tmp2.getLinExpCopy(), R: res.getLinExpCopy(), O: tmp1.getLinExpCopy(), Solver: r1c.SingleOutput}
-			cs.constraints = append(cs.constraints, constraint)
+			cs.addConstraint(constraint)
 		}
 	}
 
@@ -221,11 +222,92 @@ func (cs *ConstraintSystem) Xor(a, b Variable) Variable {
 	v2 = cs.Sub(v2, res) // no constraint recorded
 
 	constraint := r1c.R1C{L: v1.getLinExpCopy(), R: b.getLinExpCopy(), O: v2.getLinExpCopy(), Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
+	cs.markBoolean(res) // res is boolean since a and b are
 
 	return res
 }
 
+// Or compute the or between two variables
+func (cs *ConstraintSystem) Or(a, b Variable) Variable {
+
+	cs.completeDanglingVariable(&a)
+	cs.completeDanglingVariable(&b)
+
+	cs.AssertIsBoolean(a)
+	cs.AssertIsBoolean(b)
+
+	// a * b == a + b - res
+	res := cs.newInternalVariable()
+	v := cs.Add(a, b)  // no constraint recorded
+	v = cs.Sub(v, res) // no constraint recorded
+
+	constraint := r1c.R1C{L: a.getLinExpCopy(), R: b.getLinExpCopy(), O: v.getLinExpCopy(), Solver: r1c.SingleOutput}
+	cs.addConstraint(constraint)
+	cs.markBoolean(res) // res is boolean since a and b are
+
+	return res
+}
+
+// And compute the and between two variables
+func (cs *ConstraintSystem) And(a, b Variable) Variable {
+
+	cs.completeDanglingVariable(&a)
+	cs.completeDanglingVariable(&b)
+
+	cs.AssertIsBoolean(a)
+	cs.AssertIsBoolean(b)
+
+	res := cs.Mul(a, b)
+	cs.markBoolean(res) // res is boolean since a and b are
+
+	return res
+}
+
+// Not compute the negation of a variable
+//
+// the result is a linear expression (1 - a): no constraint is recorded, except the boolean constraint on a
+func (cs *ConstraintSystem) Not(a Variable) Variable {
+
+	cs.completeDanglingVariable(&a)
+
+	cs.AssertIsBoolean(a)
+
+	return cs.Sub(1, a) // no constraint recorded
+}
+
+// AndN compute the and of the variables (1 if they are all true, 0 otherwise)
+//
+// It costs len(v)-1 constraints, plus the boolean constraints of the inputs
+func (cs *ConstraintSystem) AndN(v ...Variable) Variable {
+	if len(v) == 0 {
+		panic("AndN needs at least one input")
+	}
+	res := v[0]
+	cs.completeDanglingVariable(&res)
+	cs.AssertIsBoolean(res)
+	for i := 1; i < len(v); i++ {
+		res = cs.And(res, v[i])
+	}
+	return res
+}
+
+// OrN compute the or of the variables (1 if one of them is true, 0 otherwise)
+//
+// It costs len(v)-1 constraints, plus the boolean constraints of the inputs
+func (cs *ConstraintSystem) OrN(v ...Variable) Variable {
+	if len(v) == 0 {
+		panic("OrN needs at least one input")
+	}
+	res := v[0]
+	cs.completeDanglingVariable(&res)
+	cs.AssertIsBoolean(res)
+	for i := 1; i < len(v); i++ {
+		res = cs.Or(res, v[i])
+	}
+	return res
+}
+
 // ToBinary unpacks a variable in binary, n is the number of bits of the variable
 //
 // The result in in little endian (first bit= lsb)
@@ -233,30 +315,41 @@ func (cs *ConstraintSystem) ToBinary(a Variable, nbBits int) []Variable {
 
 	cs.completeDanglingVariable(&a)
 
-	// allocate the resulting variables
+	// allocate the resulting variables, and assert they are boolean (the assertions share the
+	// call stack, which is expensive to get)
 	res := make([]Variable, nbBits)
+	debugInfo := booleanDebugInfo(getCallStack())
+	o := cs.Constant(0)
 	for i := 0; i < nbBits; i++ {
 		res[i] = cs.newInternalVariable()
-		cs.AssertIsBoolean(res[i])
+		_v := cs.Sub(1, res[i])
+		cs.markBoolean(res[i])
+		constraint := r1c.R1C{L: res[i].getLinExpCopy(), R: _v.getLinExpCopy(), O: o.getLinExpCopy(), Solver: r1c.SingleOutput}
+		cs.addAssertion(constraint, debugInfo)
 	}
 
 	var coeff big.Int
 	coeff.Set(bTwo)
 
-	var v, _v Variable
-	v = cs.Mul(res[0], 1) // no constraint is recorded
-
-	// add the constraint
+	// the terms are summed at once, reducing the linear expression once
+	terms := make([]interface{}, nbBits)
+	terms[0] = res[0]
 	for i := 1; i < nbBits; i++ {
-		_v = cs.Mul(coeff, res[i]) // no constraint is recorded
-		v = cs.Add(v, _v)          // no constraint is recorded
+		terms[i] = cs.Mul(coeff, res[i]) // no constraint is recorded
 		coeff.Mul(&coeff, bTwo)
 	}
+	var v Variable
+	if nbBits == 1 {
+		v = cs.Mul(res[0], 1) // no constraint is recorded
+	} else {
+		v = cs.Add(terms[0], terms[1], terms[2:]...) // no constraint is recorded
+	}
 
+	// add the constraint
 	r := cs.getOneVariable()
 
 	constraint := r1c.R1C{L: v.getLinExpCopy(), R: r.getLinExpCopy(), O: a.getLinExpCopy(), Solver: r1c.BinaryDec}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 
@@ -309,7 +402,7 @@ func (cs *ConstraintSystem) Select(b Variable, i1, i2 interface{}) Variable {
 		w := cs.Sub(res, i2) // no constraint is recorded
 		//cs.Println("u-v: ", v)
 		constraint := r1c.R1C{L: b.getLinExpCopy(), R: v.getLinExpCopy(), O: w.getLinExpCopy(), Solver: r1c.SingleOutput}
-		cs.constraints = append(cs.constraints, constraint)
+		cs.addConstraint(constraint)
 		return res
 	default:
 		switch t2 := i2.(type) {
@@ -319,13 +412,13 @@ func (cs *ConstraintSystem) Select(b Variable, i1, i2 interface{}) Variable {
 			v := cs.Sub(t1, t2)  // no constraint is recorded
 			w := cs.Sub(res, t2) // no constraint is recorded
 			constraint := r1c.R1C{L: b.getLinExpCopy(), R: v.getLinExpCopy(), O: w.getLinExpCopy(), Solver: r1c.SingleOutput}
-			cs.constraints = append(cs.constraints, constraint)
+			cs.addConstraint(constraint)
 			return res
 		default:
 			// in this case, no constraint is recorded
 			n1 := backend.FromInterface(t1)
 			n2 := backend.FromInterface(t2)
-			diff := n1.Sub(&n2, &n1)
+			diff := n1.Sub(&n1, &n2)
 			res = cs.Mul(b, diff) // no constraint is recorded
 			res = cs.Add(res, t2) // no constraint is recorded
 			return res
@@ -333,6 +426,100 @@ func (cs *ConstraintSystem) Select(b Variable, i1, i2 interface{}) Variable {
 	}
 }
 
+// Lookup2 performs a 2-bit lookup: it yields i0 if b0 = b1 = 0, i1 if b0 = 1 and b1 = 0,
+// i2 if b0 = 0 and b1 = 1, and i3 if b0 = b1 = 1
+//
+// It costs at most 3 constraints, plus the boolean constraints of b0 and b1
+func (cs *ConstraintSystem) Lookup2(b0, b1 Variable, i0, i1, i2, i3 interface{}) Variable {
+
+	cs.completeDanglingVariable(&b0)
+	cs.completeDanglingVariable(&b1)
+
+	cs.AssertIsBoolean(b0)
+	cs.AssertIsBoolean(b1)
+
+	// res = i0 + b0 * (i1 - i0 + b1 * (i3 - i2 - i1 + i0)) + b1 * (i2 - i0)
+	t1 := cs.Sub(cs.Add(i3, i0), cs.Add(i2, i1)) // no constraint recorded
+	t1 = cs.Mul(b1, t1)
+	t1 = cs.Add(t1, cs.Sub(i1, i0)) // no constraint recorded
+	t1 = cs.Mul(b0, t1)
+
+	t2 := cs.Mul(b1, cs.Sub(i2, i0))
+
+	return cs.Add(t1, t2, i0) // no constraint recorded
+}
+
+// IsZero returns 1 if v == 0, 0 otherwise
+func (cs *ConstraintSystem) IsZero(v Variable) Variable {
+
+	cs.completeDanglingVariable(&v)
+
+	// res = 1 if v == 0, computed by a hint, and m = 1/v if v != 0
+	// v * m == 1 - res ensures that res == 1 if v == 0
+	// v * res == 0 ensures that res == 0 if v != 0
+	res := cs.NewHint(hint.IsZero, v)
+	m := cs.newInternalVariable()
+	_res := cs.Sub(1, res) // no constraint is recorded
+
+	constraint := r1c.R1C{L: v.getLinExpCopy(), R: m.getLinExpCopy(), O: _res.getLinExpCopy(), Solver: r1c.SingleOutput}
+	cs.addConstraint(constraint)
+
+	o := cs.Constant(0) // no constraint is recorded
+	constraint = r1c.R1C{L: v.getLinExpCopy(), R: res.getLinExpCopy(), O: o.getLinExpCopy(), Solver: r1c.SingleOutput}
+	debugInfo := logEntry{format: "error IsZero"}
+	stack := getCallStack()
+	for i := 0; i < len(stack); i++ {
+		debugInfo.format += "\n" + stack[i]
+	}
+	cs.addAssertion(constraint, debugInfo)
+	cs.markBoolean(res) // v * res == 0 and v * m == 1 - res imply that res is boolean
+
+	return res
+}
+
+// IsEqual returns 1 if i1 == i2, 0 otherwise
+func (cs *ConstraintSystem) IsEqual(i1, i2 interface{}) Variable {
+	return cs.IsZero(cs.Sub(i1, i2))
+}
+
+// IsLess returns 1 if i1 < i2, 0 otherwise
+//
+// i1 and i2 are compared as integers (in [0, r), r being the modulus of the scalar field),
+// and |i1 - i2| must be less than 2^nbBits; otherwise, the constraint system has no solution.
+// 2^(nbBits+1) must be less than r.
+//
+// It costs nbBits+1 boolean constraints and a binary decomposition
+func (cs *ConstraintSystem) IsLess(i1, i2 interface{}, nbBits int) Variable {
+
+	// d = i1 - i2 + 2^nbBits is in [1, 2^(nbBits+1)), and its nbBits-th bit is set iff i1 >= i2
+	var shift big.Int
+	shift.Lsh(bOne, uint(nbBits))
+	d := cs.Add(cs.Sub(i1, i2), shift) // no constraint is recorded
+
+	bits := cs.ToBinary(d, nbBits+1)
+
+	return cs.Sub(1, bits[nbBits]) // no constraint is recorded
+}
+
+// cmpNbBits is the number of bits of the difference of the inputs of Cmp:
+// 2^(cmpNbBits+1) is less than the modulus of the scalar field of all the supported curves
+const cmpNbBits = 251
+
+// Cmp returns 1 if i1 > i2, 0 if i1 == i2 and -1 if i1 < i2
+//
+// i1 and i2 are compared as integers, and |i1 - i2| must be less than 2^251 (see IsLess)
+func (cs *ConstraintSystem) Cmp(i1, i2 interface{}) Variable {
+
+	isEqual := cs.IsEqual(i1, i2)
+	isLess := cs.IsLess(i1, i2, cmpNbBits)
+
+	// 1 - isEqual - 2*isLess
+	res := cs.Sub(1, isEqual)            // no constraint is recorded
+	res = cs.Sub(res, cs.Mul(2, isLess)) // no constraint is recorded
+
+	return res
+}
+
 // Constant will return (and allocate if neccesary) a constant Variable
 //
 // input can be a Variable or must be convertible to big.Int (see backend.FromInterface)
@@ -400,13 +587,13 @@ func (cs *ConstraintSystem) AssertIsBoolean(v Variable) {
 
 	cs.completeDanglingVariable(&v)
 
-	if v.isBoolean {
+	if cs.isBoolean(v) {
 		return
 	}
 
 	_v := cs.Sub(1, v)  // no variable is recorded in the cs
 	o := cs.Constant(0) // no variable is recorded in the cs
-	v.isBoolean = true
+	cs.markBoolean(v)
 
 	constraint := r1c.R1C{L: v.getLinExpCopy(), R: _v.getLinExpCopy(), O: o.getLinExpCopy(), Solver: r1c.SingleOutput}
 
@@ -416,16 +603,19 @@ func (cs *ConstraintSystem) AssertIsBoolean(v Variable) {
 	// 	toResolve: []r1c.Term{r1c.Pack(v.id, 0, v.visibility)},
 	// }
 	// stack := getCallStack()
+	cs.addAssertion(constraint, booleanDebugInfo(getCallStack()))
+}
+
+// booleanDebugInfo returns the debug info of a boolean assertion added from stack
+func booleanDebugInfo(stack []string) logEntry {
 	debugInfo := logEntry{
 		format:    fmt.Sprintf("error AssertIsBoolean"),
 		toResolve: nil,
 	}
-	stack := getCallStack()
 	for i := 0; i < len(stack); i++ {
 		debugInfo.format += "\n" + stack[i]
 	}
-
-	cs.addAssertion(constraint, debugInfo)
+	return debugInfo
 }
 
 // AssertIsLessOrEqual adds assertion in constraint system  (v <= bound)
@@ -11,6 +11,7 @@ import (
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/commands"
 	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
 )
 
 var variableName big.Int
@@ -376,7 +377,7 @@ func rfSelect() runfunc {
 	return res
 }
 
-var nsSelect = deltaState{1, 2, 3, 3, 4}
+var nsSelect = deltaState{1, 2, 3, 3, 1}
 
 // copy of variable
 func rfConstant() runfunc {
@@ -508,7 +509,7 @@ func rfIsBoolean() runfunc {
 	return res
 }
 
-var nsIsBoolean = deltaState{1, 1, 0, 0, 4}
+var nsIsBoolean = deltaState{1, 1, 0, 0, 2}
 
 // bound a variable by another variable
 func rfMustBeLessOrEqVar() runfunc {
@@ -540,7 +541,7 @@ func rfMustBeLessOrEqVar() runfunc {
 	return res
 }
 
-var nsMustBeLessOrEqVar = deltaState{1, 1, 1281, 771, 768}
+var nsMustBeLessOrEqVar = deltaState{1, 1, 1280, 770, 768}
 
 // bound a variable by a constant
 func rfMustBeLessOrEqConst() runfunc {
@@ -568,7 +569,118 @@ func rfMustBeLessOrEqConst() runfunc {
 	return res
 }
 
-var nsMustBeLessOrEqConst = csState{1, 0, 257, 2, 511} // nb internal variables: 256+HW(bound), nb constraints: 1+HW(bound), nb assertions: 256+HW(^bound)
+var nsMustBeLessOrEqConst = deltaState{1, 0, 257, 2, 511} // nb internal variables: 256+HW(bound), nb constraints: 1+HW(bound), nb assertions: 256+HW(^bound)
+
+// zero and equality tests
+func rfIsZero() runfunc {
+	res := func(systemUnderTest commands.SystemUnderTest) commands.Result {
+
+		pVariablesCreated := make([]Variable, 0)
+		sVariablesCreated := make([]Variable, 0)
+		iVariablesCreated := make([]Variable, 0)
+
+		a := systemUnderTest.(*ConstraintSystem).newPublicVariable(variableName.String())
+		incVariableName()
+		pVariablesCreated = append(pVariablesCreated, a)
+
+		b := systemUnderTest.(*ConstraintSystem).newSecretVariable(variableName.String())
+		incVariableName()
+		sVariablesCreated = append(sVariablesCreated, b)
+
+		u := systemUnderTest.(*ConstraintSystem).IsZero(a)
+		iVariablesCreated = append(iVariablesCreated, u)
+
+		v := systemUnderTest.(*ConstraintSystem).IsEqual(a, b)
+		iVariablesCreated = append(iVariablesCreated, v)
+
+		csRes := csResult{
+			systemUnderTest.(*ConstraintSystem),
+			pVariablesCreated,
+			sVariablesCreated,
+			iVariablesCreated,
+			r1c.SingleOutput}
+
+		return csRes
+	}
+	return res
+}
+
+var nsIsZero = deltaState{1, 1, 4, 2, 2}
+
+// comparisons
+func rfIsLess() runfunc {
+	res := func(systemUnderTest commands.SystemUnderTest) commands.Result {
+
+		pVariablesCreated := make([]Variable, 0)
+		sVariablesCreated := make([]Variable, 0)
+		iVariablesCreated := make([]Variable, 0)
+
+		a := systemUnderTest.(*ConstraintSystem).newPublicVariable(variableName.String())
+		incVariableName()
+		pVariablesCreated = append(pVariablesCreated, a)
+
+		b := systemUnderTest.(*ConstraintSystem).newSecretVariable(variableName.String())
+		incVariableName()
+		sVariablesCreated = append(sVariablesCreated, b)
+
+		systemUnderTest.(*ConstraintSystem).IsLess(a, b, 8)
+		systemUnderTest.(*ConstraintSystem).Cmp(a, b)
+
+		csRes := csResult{
+			systemUnderTest.(*ConstraintSystem),
+			pVariablesCreated,
+			sVariablesCreated,
+			iVariablesCreated,
+			r1c.BinaryDec}
+
+		return csRes
+	}
+	return res
+}
+
+var nsIsLess = deltaState{1, 1, 263, 3, 262} // IsLess: 9 internal variables, 1 constraint, 9 assertions; Cmp: 254, 2, 253
+
+// boolean operations
+func rfBoolean() runfunc {
+	res := func(systemUnderTest commands.SystemUnderTest) commands.Result {
+
+		pVariablesCreated := make([]Variable, 0)
+		sVariablesCreated := make([]Variable, 0)
+		iVariablesCreated := make([]Variable, 0)
+
+		a := systemUnderTest.(*ConstraintSystem).newPublicVariable(variableName.String())
+		incVariableName()
+		pVariablesCreated = append(pVariablesCreated, a)
+
+		b := systemUnderTest.(*ConstraintSystem).newSecretVariable(variableName.String())
+		incVariableName()
+		sVariablesCreated = append(sVariablesCreated, b)
+
+		// a and b are boolean constrained once, the outputs of And and Or are not constrained again
+		u := systemUnderTest.(*ConstraintSystem).And(a, b)
+		iVariablesCreated = append(iVariablesCreated, u)
+
+		v := systemUnderTest.(*ConstraintSystem).Or(a, b)
+		iVariablesCreated = append(iVariablesCreated, v)
+
+		systemUnderTest.(*ConstraintSystem).Not(a)
+		systemUnderTest.(*ConstraintSystem).AndN(u, v, a)
+		systemUnderTest.(*ConstraintSystem).OrN(u, b)
+		systemUnderTest.(*ConstraintSystem).Lookup2(a, b, a, 3, b, 5)
+
+		csRes := csResult{
+			systemUnderTest.(*ConstraintSystem),
+			pVariablesCreated,
+			sVariablesCreated,
+			iVariablesCreated,
+			r1c.SingleOutput}
+
+		return csRes
+	}
+	return res
+}
+
+var nsBoolean = deltaState{1, 1, 8, 8, 2} // And: 1, 1, 2; Or: 1, 1, 0; Not: 0, 0, 0; AndN: 2, 2, 0; OrN: 1, 1, 0; Lookup2: 3, 3, 0
 
 // ------------------------------------------------------------------------------
 // build the next state function using the delta state
@@ -601,9 +713,12 @@ func TestAPI(t *testing.T) {
 		buildProtoCommands("Constant", rfConstant(), nextStateFunc(nsConstant)),
 		buildProtoCommands("IsEqual", rfIsEqual(), nextStateFunc(nsIsEqual)),
 		buildProtoCommands("FromBinary", rfFromBinary(), nextStateFunc(nsFromBinary)),
-		buildProtoCommands("IsBoolean", rfIsBoolean(), nextStateFunc(nsIsBoolean)), // TODO fix isBoolean to record if it was already boolean constrained
-		// buildProtoCommands("Must be less or eq var", rfMustBeLessOrEqVar(), nextStateFunc(nsMustBeLessOrEqVar)), // TODO restore once isBoolean is fixed
-		// buildProtoCommands("Must be less or eq const", rfMustBeLessOrEqConst(), nextStateFunc(nsMustBeLessOrEqConst)), // TODO idem
+		buildProtoCommands("IsBoolean", rfIsBoolean(), nextStateFunc(nsIsBoolean)),
+		buildProtoCommands("IsZero IsEqual", rfIsZero(), nextStateFunc(nsIsZero)),
+		buildProtoCommands("IsLess Cmp", rfIsLess(), nextStateFunc(nsIsLess)),
+		buildProtoCommands("Boolean operations", rfBoolean(), nextStateFunc(nsBoolean)),
+		buildProtoCommands("Must be less or eq var", rfMustBeLessOrEqVar(), nextStateFunc(nsMustBeLessOrEqVar)),
+		buildProtoCommands("Must be less or eq const", rfMustBeLessOrEqConst(), nextStateFunc(nsMustBeLessOrEqConst)),
 	}
 
 	// generate randomly a sequence of commands
@@ -761,6 +876,46 @@ func (c *isLessOrEq) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
 	return nil
 }
 
+type isZeroCircuit struct {
+	A Variable
+}
+
+func (c *isZeroCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	var unsetVar Variable
+	cs.IsZero(unsetVar)
+	return nil
+}
+
+type isLessCircuit struct {
+	A Variable
+}
+
+func (c *isLessCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	var unsetVar Variable
+	cs.IsLess(unsetVar, c.A, 8)
+	return nil
+}
+
+type andCircuit struct {
+	A Variable
+}
+
+func (c *andCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	var unsetVar Variable
+	cs.AndN(c.A, unsetVar)
+	return nil
+}
+
+type lookup2Circuit struct {
+	A Variable
+}
+
+func (c *lookup2Circuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	var unsetVar Variable
+	cs.Lookup2(c.A, unsetVar, 0, 1, 2, 3)
+	return nil
+}
+
 func TestUnsetVariables(t *testing.T) {
 
 	mapFuncs := map[string]Circuit{
@@ -776,6 +931,10 @@ func TestUnsetVariables(t *testing.T) {
 		"isEqual":      &isEqualCircuit{},
 		"isBoolean":    &isBooleanCircuit{},
 		"isLessOrEq":   &isLessOrEq{},
+		"isZero":       &isZeroCircuit{},
+		"isLess":       &isLessCircuit{},
+		"and":          &andCircuit{},
+		"lookup2":      &lookup2Circuit{},
 	}
 
 	for name, arg := range mapFuncs {
@@ -792,3 +951,224 @@ func TestUnsetVariables(t *testing.T) {
 	}
 
 }
+
+// ------------------------------------------------------------------------------
+// Test the values of the comparisons when solving the R1CS
+
+type cmpCircuit struct {
+	A, B                         Variable
+	IsZero, IsEqual, IsLess, Cmp Variable `gnark:",public"`
+}
+
+func (c *cmpCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	cs.AssertIsEqual(cs.IsZero(c.A), c.IsZero)
+	cs.AssertIsEqual(cs.IsEqual(c.A, c.B), c.IsEqual)
+	cs.AssertIsEqual(cs.Select(cs.IsLess(c.A, c.B, 64), 1, 0), c.IsLess)
+	cs.AssertIsEqual(cs.Cmp(c.A, c.B), c.Cmp)
+	return nil
+}
+
+func TestComparisons(t *testing.T) {
+	var circuit cmpCircuit
+	r1cs, err := Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	// small values, so that zero and equal values are generated
+	genValue := gen.OneGenOf(gen.UInt64(), gen.UInt64Range(0, 3))
+
+	properties.Property("IsZero, IsEqual, IsLess and Cmp should match their expected values", prop.ForAll(
+		func(a, b uint64) bool {
+			expected := map[string]interface{}{
+				"A":       a,
+				"B":       b,
+				"IsZero":  0,
+				"IsEqual": 0,
+				"IsLess":  0,
+				"Cmp":     big.NewInt(1),
+			}
+			if a == 0 {
+				expected["IsZero"] = 1
+			}
+			if a == b {
+				expected["IsEqual"] = 1
+				expected["Cmp"] = big.NewInt(0)
+			}
+			if a < b {
+				expected["IsLess"] = 1
+				expected["Cmp"] = big.NewInt(-1)
+			}
+			if r1cs.IsSolved(expected) != nil {
+				return false
+			}
+
+			// a wrong result must not solve the R1CS
+			expected["IsLess"] = 1 - expected["IsLess"].(int)
+			return r1cs.IsSolved(expected) != nil
+		},
+		genValue, genValue,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// ------------------------------------------------------------------------------
+// Test the values of the boolean operations when solving the R1CS
+
+type booleanCircuit struct {
+	A, B                                 Variable
+	And, Or, Not, Xor, AndN, OrN, Lookup Variable `gnark:",public"`
+}
+
+func (c *booleanCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	cs.AssertIsEqual(cs.And(c.A, c.B), c.And)
+	cs.AssertIsEqual(cs.Or(c.A, c.B), c.Or)
+	cs.AssertIsEqual(cs.Not(c.A), c.Not)
+	cs.AssertIsEqual(cs.Xor(c.A, c.B), c.Xor)
+	cs.AssertIsEqual(cs.AndN(c.A, c.B, cs.Not(c.B)), c.AndN)
+	cs.AssertIsEqual(cs.OrN(c.A, c.B, cs.Not(c.B)), c.OrN)
+	cs.AssertIsEqual(cs.Lookup2(c.A, c.B, 10, c.A, 12, c.B), c.Lookup)
+	return nil
+}
+
+func TestBooleans(t *testing.T) {
+	var circuit booleanCircuit
+	r1cs, err := Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for a := 0; a < 2; a++ {
+		for b := 0; b < 2; b++ {
+			lookup := []int{10, a, 12, b}
+			expected := map[string]interface{}{
+				"A":      a,
+				"B":      b,
+				"And":    a & b,
+				"Or":     a | b,
+				"Not":    1 - a,
+				"Xor":    a ^ b,
+				"AndN":   0,
+				"OrN":    1,
+				"Lookup": lookup[a+2*b],
+			}
+			if err := r1cs.IsSolved(expected); err != nil {
+				t.Fatal(a, b, err)
+			}
+
+			expected["And"] = 1 - a&b
+			if err := r1cs.IsSolved(expected); err == nil {
+				t.Fatal("a wrong result should not solve the R1CS", a, b)
+			}
+		}
+	}
+
+	// the inputs must be boolean
+	expected := map[string]interface{}{
+		"A": 2, "B": 0, "And": 0, "Or": 2, "Not": -1, "Xor": 2, "AndN": 0, "OrN": 1, "Lookup": 12,
+	}
+	if err := r1cs.IsSolved(expected); err == nil {
+		t.Fatal("non boolean inputs should not solve the R1CS")
+	}
+}
+
+// ------------------------------------------------------------------------------
+// Test Select when both branches are constants
+
+type selectConstantsCircuit struct {
+	B   Variable
+	Res Variable `gnark:",public"`
+}
+
+func (c *selectConstantsCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Select(c.B, 3, 5), c.Res)
+	return nil
+}
+
+func TestSelectConstants(t *testing.T) {
+	var circuit selectConstantsCircuit
+	r1cs, err := Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Select(b, i1, i2) yields i1 if b == 1, i2 if b == 0
+	if err := r1cs.IsSolved(map[string]interface{}{"B": 1, "Res": 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(map[string]interface{}{"B": 0, "Res": 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(map[string]interface{}{"B": 1, "Res": 5}); err == nil {
+		t.Fatal("Select(1, 3, 5) should not be 5")
+	}
+}
+
+// ------------------------------------------------------------------------------
+// Test ToBinary when solving the R1CS
+
+type toBinarySolveCircuit struct {
+	A, B Variable
+}
+
+func (c *toBinarySolveCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	a := cs.ToBinary(c.A, 1)
+	cs.AssertIsEqual(a[0], c.A)
+	b := cs.ToBinary(c.B, 8)
+	cs.AssertIsEqual(cs.FromBinary(b...), c.B)
+	return nil
+}
+
+func TestToBinary(t *testing.T) {
+	var circuit toBinarySolveCircuit
+	r1cs, err := Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range []int{0, 1} {
+		for _, b := range []int{0, 1, 42, 255} {
+			if err := r1cs.IsSolved(map[string]interface{}{"A": a, "B": b}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// toBinaryBenchCircuit decomposes 256 variables in 64 bits, as the range checks of the emulated
+// arithmetic do for every limb
+type toBinaryBenchCircuit struct {
+	X Variable
+}
+
+func (circuit *toBinaryBenchCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	for i := 0; i < 256; i++ {
+		cs.ToBinary(cs.Add(circuit.X, i), 64)
+	}
+	return nil
+}
+
+func BenchmarkToBinaryCompile(b *testing.B) {
+	var circuit toBinaryBenchCircuit
+	for i := 0; i < b.N; i++ {
+		if _, err := Compile(gurvy.BN256, &circuit); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkToBinaryExecute(b *testing.B) {
+	var circuit, witness toBinaryBenchCircuit
+	witness.X.Assign(42)
+	for i := 0; i < b.N; i++ {
+		if err := Execute(gurvy.BN256, &circuit, &witness); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
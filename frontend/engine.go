@@ -0,0 +1,403 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gurvy"
+)
+
+var (
+	errUnknownCurve   = errors.New("unknown curve")
+	errUnsolvedWire   = errors.New("wire can't be solved")
+	errTooManyUnknown = errors.New("more than one wire to solve in the constraint")
+)
+
+// Execute runs circuit.Define with the values of assignment, without building a R1CS:
+// each constraint is solved (or checked) as soon as it is added, using big.Int arithmetic
+// modulo the scalar field of curveID.
+//
+// It returns an error at the first constraint which is not satisfied, with the Go stack trace of the
+// API call which added it. It is meant to quickly test circuits and gadgets (see package test).
+//
+// circuit must not be assigned (it may have been compiled), assignment must be map[string]interface{}
+// or implement Circuit (see ParseWitness)
+func Execute(curveID gurvy.ID, circuit Circuit, assignment interface{}) (err error) {
+
+	modulus := utils.ScalarField(curveID)
+	if modulus == nil {
+		return errUnknownCurve
+	}
+
+	values, err := ParseWitness(assignment)
+	if err != nil {
+		return err
+	}
+
+	// unlike Compile, the circuit can be executed several times, or after being compiled
+	ResetInputs(circuit)
+	defer ResetInputs(circuit)
+
+	cs := newConstraintSystem()
+	if err := cs.allocateInputs(circuit); err != nil {
+		return err
+	}
+
+	e := &engine{curveID: curveID, modulus: modulus}
+	e.public = make([]*big.Int, len(cs.public.names))
+	e.public[0] = big.NewInt(1) // ONE_WIRE
+	for i := 1; i < len(cs.public.names); i++ {
+		if e.public[i], err = e.input(cs.public.names[i], values); err != nil {
+			return err
+		}
+	}
+	e.secret = make([]*big.Int, len(cs.secret.names))
+	for i := 0; i < len(cs.secret.names); i++ {
+		if e.secret[i], err = e.input(cs.secret.names[i], values); err != nil {
+			return err
+		}
+	}
+	cs.engine = e
+
+	// the engine panics with an *engineError at the first failure
+	defer func() {
+		if r := recover(); r != nil {
+			engineErr, ok := r.(*engineError)
+			if !ok {
+				panic(r)
+			}
+			err = engineErr
+		}
+	}()
+
+	if err := circuit.Define(curveID, &cs); err != nil {
+		return err
+	}
+
+	// the hints and assertions still pending reference wires which were never solved
+	if len(e.pendingHints) != 0 {
+		e.fail(e.pendingHints[0].stack, fmt.Errorf("hint: %w", errUnsolvedWire))
+	}
+	if len(e.pendingAssertions) != 0 {
+		e.fail(e.pendingAssertions[0].stack, fmt.Errorf("assertion: %w", errUnsolvedWire))
+	}
+
+	return nil
+}
+
+// engine solves the constraints of a ConstraintSystem as they are added (see Execute)
+type engine struct {
+	curveID                  gurvy.ID // passed to the hints
+	modulus                  *big.Int
+	public, secret, internal []*big.Int // values of the wires (nil if not solved yet)
+
+	// hints and assertions whose wires are not solved yet, with the call stack which added them
+	pendingHints      []pendingHint
+	pendingAssertions []pendingAssertion
+}
+
+type pendingHint struct {
+	hint  r1c.Hint
+	stack []uintptr
+}
+
+type pendingAssertion struct {
+	constraint r1c.R1C
+	debugInfo  logEntry
+	stack      []uintptr
+}
+
+// engineError is raised (as a panic) by the engine when a constraint is not satisfied
+type engineError struct {
+	err   error
+	stack string
+}
+
+func (e *engineError) Error() string {
+	return e.err.Error() + "\n\n" + e.stack
+}
+
+func (e *engineError) Unwrap() error {
+	return e.err
+}
+
+// input returns the value of the input name, reduced modulo the scalar field
+func (e *engine) input(name string, values map[string]interface{}) (*big.Int, error) {
+	v, ok := values[name]
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+	}
+	value := backend.FromInterface(v)
+	return value.Mod(&value, e.modulus), nil
+}
+
+// callers returns the call stack of the API call which added a constraint or a hint
+// (skip is the number of frames between the API call and the caller of callers)
+func callers(skip int) []uintptr {
+	pc := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+3, pc)
+	return pc[:n]
+}
+
+// fail raises an *engineError, with the Go stack trace of the API call.
+//
+// A nil stack means that the constraint being added is checked right away: capturing the stack of
+// every constraint is expensive, so it is captured here, from the frame of the API call.
+func (e *engine) fail(stack []uintptr, err error) {
+	skip := stack == nil
+	if skip {
+		stack = callers(0)
+	}
+	var sb strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		if skip {
+			skip = !strings.HasSuffix(frame.Function, "(*ConstraintSystem).addConstraint") &&
+				!strings.HasSuffix(frame.Function, "(*ConstraintSystem).addAssertion")
+			if !more {
+				break
+			}
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	panic(&engineError{err: err, stack: sb.String()})
+}
+
+// wires returns the values of the wires of visibility v
+func (e *engine) wires(v backend.Visibility) *[]*big.Int {
+	switch v {
+	case backend.Public:
+		return &e.public
+	case backend.Secret:
+		return &e.secret
+	case backend.Internal:
+		return &e.internal
+	default:
+		return nil
+	}
+}
+
+// value returns the value of the wire of t (nil if it is not solved yet)
+func (e *engine) value(t r1c.Term) (*big.Int, error) {
+	wires := e.wires(t.ConstraintVisibility())
+	if wires == nil {
+		return nil, backend.ErrInputNotSet
+	}
+	if t.VariableID() >= len(*wires) {
+		return nil, nil
+	}
+	return (*wires)[t.VariableID()], nil
+}
+
+func (e *engine) setValue(t r1c.Term, v *big.Int) {
+	wires := e.wires(t.ConstraintVisibility())
+	for len(*wires) <= t.VariableID() {
+		*wires = append(*wires, nil)
+	}
+	(*wires)[t.VariableID()] = v
+}
+
+// eval returns the value of l, the wires not solved yet being ignored, and these wires
+func (e *engine) eval(cs *ConstraintSystem, l r1c.LinearExpression, stack []uintptr) (big.Int, []r1c.Term) {
+	var res, tmp big.Int
+	var unsolved []r1c.Term
+	for _, t := range l {
+		v, err := e.value(t)
+		if err != nil {
+			e.fail(stack, err)
+		}
+		if v == nil {
+			unsolved = append(unsolved, t)
+			continue
+		}
+		tmp.Mul(&cs.coeffs[t.CoeffID()], v)
+		res.Add(&res, &tmp)
+	}
+	return *res.Mod(&res, e.modulus), unsolved
+}
+
+// isSolved returns true if all the wires of the constraint are solved
+func (e *engine) isSolved(c r1c.R1C) bool {
+	for _, l := range [3]r1c.LinearExpression{c.L, c.R, c.O} {
+		for _, t := range l {
+			if v, err := e.value(t); err == nil && v == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// addConstraint solves the (computational) constraint c, and checks it is satisfied
+func (e *engine) addConstraint(cs *ConstraintSystem, c r1c.R1C) {
+	e.solveHints(cs)
+
+	a, ua := e.eval(cs, c.L, nil)
+	b, ub := e.eval(cs, c.R, nil)
+	o, uo := e.eval(cs, c.O, nil)
+
+	switch c.Solver {
+	case r1c.SingleOutput:
+		if len(ua)+len(ub)+len(uo) > 1 {
+			e.fail(nil, errTooManyUnknown)
+		}
+
+		// same as the R1CS solver: the wire is set to 0 if it can't be computed
+		var v big.Int
+		var t r1c.Term
+		switch {
+		case len(ua) == 1:
+			t = ua[0]
+			if b.Sign() != 0 {
+				v.ModInverse(&b, e.modulus).Mul(&v, &o).Sub(&v, &a)
+			}
+		case len(ub) == 1:
+			t = ub[0]
+			if a.Sign() != 0 {
+				v.ModInverse(&a, e.modulus).Mul(&v, &o).Sub(&v, &b)
+			}
+		case len(uo) == 1:
+			t = uo[0]
+			v.Mul(&a, &b).Sub(&v, &o)
+		}
+		if len(ua)+len(ub)+len(uo) == 1 {
+			var coeff big.Int
+			coeff.Mod(&cs.coeffs[t.CoeffID()], e.modulus)
+			if coeff.ModInverse(&coeff, e.modulus) == nil {
+				v.SetUint64(0)
+			}
+			v.Mul(&v, &coeff).Mod(&v, e.modulus)
+			e.setValue(t, &v)
+		}
+
+	case r1c.BinaryDec:
+		if len(uo) != 0 {
+			e.fail(nil, errUnsolvedWire)
+		}
+		// the coefficients of the bits are powers of 2
+		for _, t := range ua {
+			bit := cs.coeffs[t.CoeffID()].BitLen() - 1
+			e.setValue(t, new(big.Int).SetUint64(uint64(o.Bit(bit))))
+		}
+	}
+
+	e.check(cs, c, logEntry{format: "computational constraint"}, nil)
+	e.checkPendingAssertions(cs)
+}
+
+// addAssertion checks the assertion c, or defers it until its wires are solved
+func (e *engine) addAssertion(cs *ConstraintSystem, c r1c.R1C, debugInfo logEntry) {
+	e.solveHints(cs)
+
+	if !e.isSolved(c) {
+		e.pendingAssertions = append(e.pendingAssertions, pendingAssertion{c, debugInfo, callers(1)})
+		return
+	}
+	e.check(cs, c, debugInfo, nil)
+}
+
+// addHint solves h, or defers it until its inputs are solved
+func (e *engine) addHint(cs *ConstraintSystem, h r1c.Hint) {
+	e.pendingHints = append(e.pendingHints, pendingHint{h, callers(0)})
+	e.solveHints(cs)
+}
+
+// check fails if c is not satisfied, with the debugInfo resolved with the values of the wires
+func (e *engine) check(cs *ConstraintSystem, c r1c.R1C, debugInfo logEntry, stack []uintptr) {
+	a, ua := e.eval(cs, c.L, stack)
+	b, ub := e.eval(cs, c.R, stack)
+	o, uo := e.eval(cs, c.O, stack)
+	if len(ua)+len(ub)+len(uo) != 0 {
+		e.fail(stack, errUnsolvedWire)
+	}
+
+	a.Mul(&a, &b).Mod(&a, e.modulus)
+	if a.Cmp(&o) == 0 {
+		return
+	}
+
+	toResolve := make([]interface{}, len(debugInfo.toResolve))
+	for i, t := range debugInfo.toResolve {
+		if v, err := e.value(t); err == nil && v != nil {
+			toResolve[i] = v.String()
+		} else {
+			toResolve[i] = "<unsolved>"
+		}
+	}
+	e.fail(stack, fmt.Errorf("%w: %s", backend.ErrUnsatisfiedConstraint, fmt.Sprintf(debugInfo.format, toResolve...)))
+}
+
+func (e *engine) checkPendingAssertions(cs *ConstraintSystem) {
+	pending := e.pendingAssertions[:0]
+	for _, p := range e.pendingAssertions {
+		if e.isSolved(p.constraint) {
+			e.check(cs, p.constraint, p.debugInfo, p.stack)
+			continue
+		}
+		pending = append(pending, p)
+	}
+	e.pendingAssertions = pending
+}
+
+// solveHints solves the pending hints whose inputs are solved
+func (e *engine) solveHints(cs *ConstraintSystem) {
+	for solved := true; solved; {
+		solved = false
+		pending := e.pendingHints[:0]
+		for _, p := range e.pendingHints {
+			inputs := make([]*big.Int, len(p.hint.Inputs))
+			for i := 0; i < len(inputs) && inputs != nil; i++ {
+				v, unsolved := e.eval(cs, p.hint.Inputs[i], p.stack)
+				if len(unsolved) != 0 {
+					inputs = nil
+					break
+				}
+				inputs[i] = &v
+			}
+			if inputs == nil {
+				pending = append(pending, p)
+				continue
+			}
+
+			f, ok := hint.Lookup(p.hint.ID)
+			if !ok {
+				e.fail(p.stack, fmt.Errorf("hint %d is not registered", p.hint.ID))
+			}
+			var result big.Int
+			if err := f(e.curveID, inputs, &result); err != nil {
+				e.fail(p.stack, fmt.Errorf("hint %d: %w", p.hint.ID, err))
+			}
+			e.setValue(cs.makeTerm(Wire{backend.Internal, p.hint.WireID, nil}, bOne), result.Mod(&result, e.modulus))
+			solved = true
+		}
+		e.pendingHints = pending
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"testing"
+
+	"github.com/consensys/gurvy"
+)
+
+type executedCircuit struct {
+	X Variable
+	Y Variable `gnark:",public"`
+
+	// recorded at the end of Define
+	nbConstraints, nbKept int
+}
+
+func (c *executedCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	x := c.X
+	for i := 0; i < 10; i++ {
+		x = cs.Mul(x, c.X)
+	}
+	cs.AssertIsBoolean(cs.IsZero(cs.Sub(x, c.Y)))
+	cs.AssertIsLessOrEqual(c.X, 42)
+	c.nbConstraints = cs.NbConstraints()
+	c.nbKept = len(cs.constraints) + len(cs.assertions) + len(cs.debugInfo)
+	return nil
+}
+
+func TestExecuteDropsConstraints(t *testing.T) {
+	var circuit executedCircuit
+	if _, err := Compile(gurvy.BN256, &circuit); err != nil {
+		t.Fatal(err)
+	}
+	compiled := circuit.nbConstraints
+	if circuit.nbKept == 0 {
+		t.Fatal("Compile should keep the constraints")
+	}
+
+	// the engine solves the constraints as they are added and doesn't keep them,
+	// but still counts them
+	var witness executedCircuit
+	witness.X.Assign(2)
+	witness.Y.Assign(2048)
+	if err := Execute(gurvy.BN256, &circuit, &witness); err != nil {
+		t.Fatal(err)
+	}
+	if circuit.nbKept != 0 {
+		t.Fatal("the engine kept", circuit.nbKept, "constraints")
+	}
+	if circuit.nbConstraints != compiled {
+		t.Fatal("executed", circuit.nbConstraints, "constraints, compiled", compiled)
+	}
+}
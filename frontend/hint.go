@@ -0,0 +1,41 @@
+package frontend
+
+import (
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+)
+
+// HintFunction computes the value of a wire from the values of its inputs, when solving the R1CS
+// (see backend/hint)
+type HintFunction = hint.Function
+
+// NewHint initializes an internal variable whose value will be computed by f when solving the R1CS,
+// from the values of inputs (Variables or constants).
+//
+// f is registered (see hint.Register); a R1CS deserialized in another process can only be solved if f
+// is registered there too.
+//
+// No constraint is added: the circuit must constrain the returned variable, the prover being free
+// to set it to any value.
+func (cs *ConstraintSystem) NewHint(f HintFunction, inputs ...interface{}) Variable {
+	hintInputs := make([]r1c.LinearExpression, len(inputs))
+
+	for i := 0; i < len(inputs); i++ {
+		switch t := inputs[i].(type) {
+		case Variable:
+			cs.completeDanglingVariable(&t)
+			hintInputs[i] = t.getLinExpCopy()
+		default:
+			v := cs.Constant(t)
+			hintInputs[i] = v.getLinExpCopy()
+		}
+	}
+
+	res := cs.newInternalVariable()
+	cs.hints = append(cs.hints, r1c.Hint{ID: hint.Register(f), WireID: res.id, Inputs: hintInputs})
+	if cs.engine != nil {
+		cs.engine.addHint(cs, cs.hints[len(cs.hints)-1])
+	}
+
+	return res
+}
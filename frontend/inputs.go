@@ -0,0 +1,36 @@
+package frontend
+
+import (
+	"reflect"
+
+	"github.com/consensys/gnark/backend"
+)
+
+// Input is a public or secret input of a circuit, as parsed by ParseInputs
+type Input struct {
+	Name       string
+	Visibility backend.Visibility // backend.Public or backend.Secret
+	Value      interface{}        // nil if the input is not assigned
+}
+
+// ParseInputs returns the inputs of the circuit, in the order in which Compile allocates them
+//
+// unlike ParseWitness, it keeps the order and visibility of the inputs and is used to build
+// witnesses matching the inputs of the compiled circuit (see gnark/backend/witness)
+func ParseInputs(circuit Circuit) ([]Input, error) {
+	var toReturn []Input
+
+	var extractHandler leafHandler = func(visibility backend.Visibility, name string, tInput reflect.Value) error {
+		v := tInput.Interface().(Variable)
+		if visibility == backend.Unset {
+			visibility = backend.Secret
+		}
+		toReturn = append(toReturn, Input{Name: name, Visibility: visibility, Value: v.val})
+		return nil
+	}
+
+	if err := parseType(circuit, "", backend.Unset, extractHandler); err != nil {
+		return nil, err
+	}
+	return toReturn, nil
+}
@@ -0,0 +1,433 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+)
+
+// OptimizationReport summarizes the simplifications made by Compile to the constraint system
+// built by circuit.Define, before converting it to a R1CS (see WithOptimizationReport)
+type OptimizationReport struct {
+	NbConstraintsBefore int // number of constraints (including assertions) before the optimization
+	NbConstraintsAfter  int // number of constraints (including assertions) after the optimization
+	NbInlined           int // constraints with a constant multiplicand, replaced by a linear expression
+	NbDeduplicated      int // constraints and assertions identical to a previous one
+	NbWiresRemoved      int // internal wires no longer used by any constraint
+}
+
+// NbConstraintsSaved returns the number of constraints removed by the optimization
+func (report OptimizationReport) NbConstraintsSaved() int {
+	return report.NbConstraintsBefore - report.NbConstraintsAfter
+}
+
+func (report OptimizationReport) String() string {
+	return fmt.Sprintf("%d constraints saved (%d -> %d): %d inlined, %d deduplicated, %d internal wires removed",
+		report.NbConstraintsSaved(), report.NbConstraintsBefore, report.NbConstraintsAfter,
+		report.NbInlined, report.NbDeduplicated, report.NbWiresRemoved)
+}
+
+// WithoutOptimization disables the optimization of the constraint system
+func WithoutOptimization() CompileOption {
+	return func(opt *compileConfig) {
+		opt.noOptimization = true
+	}
+}
+
+// WithOptimizationReport sets report to the summary of the optimization of the constraint system
+func WithOptimizationReport(report *OptimizationReport) CompileOption {
+	return func(opt *compileConfig) {
+		opt.optimizationReport = report
+	}
+}
+
+// optimize simplifies the constraint system, without changing the set of solutions
+// of the R1CS (restricted to the public and secret inputs):
+//
+// 1. a computational constraint with a constant multiplicand (c * R == w) is removed, and its
+// output wire w is replaced by the linear expression c * R in the constraints using it
+//
+// 2. a computational constraint with the same multiplicands as a previous one (L * R == w') is removed,
+// and its output wire w' is replaced by the output wire w of the previous one
+//
+// 3. duplicate assertions are removed
+//
+// 4. the internal wires which are no longer used are removed, and the remaining ones are renumbered
+//
+// The wires of the hints are kept as is, and the wires referenced by logs or debug info are not inlined.
+func (cs *ConstraintSystem) optimize() OptimizationReport {
+	var report OptimizationReport
+	report.NbConstraintsBefore = len(cs.constraints) + len(cs.assertions)
+
+	// toR1CS reports the unset variables, there is nothing to optimize
+	if len(cs.unsetVariables) != 0 {
+		report.NbConstraintsAfter = report.NbConstraintsBefore
+		return report
+	}
+
+	report.NbInlined, report.NbDeduplicated = cs.simplifyConstraints()
+	report.NbDeduplicated += cs.deduplicateAssertions()
+	report.NbWiresRemoved = cs.compactWires()
+
+	report.NbConstraintsAfter = len(cs.constraints) + len(cs.assertions)
+	return report
+}
+
+// simplifyConstraints inlines the computational constraints with a constant multiplicand and
+// removes the ones with the same multiplicands as a previous one.
+// It returns the number of constraints inlined and removed.
+func (cs *ConstraintSystem) simplifyConstraints() (nbInlined, nbDeduplicated int) {
+
+	// the wires of the hints are not replaced, and the wires referenced by logs and debug info
+	// are only replaced by another wire
+	pinned := make(map[int]struct{})
+	for _, h := range cs.hints {
+		pinned[h.WireID] = struct{}{}
+	}
+	logged := make(map[int]struct{})
+	for _, entries := range [2][]logEntry{cs.logs, cs.debugInfo} {
+		for _, entry := range entries {
+			for _, t := range entry.toResolve {
+				if t.ConstraintVisibility() == backend.Internal {
+					logged[t.VariableID()] = struct{}{}
+				}
+			}
+		}
+	}
+
+	subs := make(map[int]r1c.LinearExpression) // internal wire -> linear expression replacing it
+	seen := make(map[int]struct{})             // internal wires used by the constraints already processed
+	products := make(map[string]int)           // multiplicands -> internal wire set to their product
+
+	markSeen := func(l r1c.LinearExpression) {
+		for _, t := range l {
+			if t.ConstraintVisibility() == backend.Internal {
+				seen[t.VariableID()] = struct{}{}
+			}
+		}
+	}
+
+	// the constraints are processed in the order they are solved, such that the wires of the
+	// linear expression replacing an output wire are always solved before it is used
+	constraints := cs.constraints[:0]
+	for i, c := range cs.constraints {
+		c.L = cs.substitute(c.L, subs)
+		c.R = cs.substitute(c.R, subs)
+		c.O = cs.substitute(c.O, subs)
+
+		if w, ok := cs.outputWire(c, seen, pinned); ok {
+			if _, ok := logged[w]; !ok {
+				if cst, ok := cs.constantValue(c.L); ok {
+					subs[w] = cs.mulConstant(cst, Variable{linExp: c.R}).linExp
+					nbInlined++
+					continue
+				}
+				if cst, ok := cs.constantValue(c.R); ok {
+					subs[w] = cs.mulConstant(cst, Variable{linExp: c.L}).linExp
+					nbInlined++
+					continue
+				}
+			}
+			key := productKey(c.L, c.R)
+			if previous, ok := products[key]; ok {
+				subs[w] = cs.LinearExpression(cs.makeTerm(Wire{backend.Internal, previous, nil}, bOne))
+				nbDeduplicated++
+				continue
+			}
+			products[key] = w
+		}
+
+		markSeen(c.L)
+		markSeen(c.R)
+		markSeen(c.O)
+		if cs.profile != nil {
+			cs.profile.constraints[len(constraints)] = cs.profile.constraints[i]
+		}
+		constraints = append(constraints, c)
+	}
+	cs.constraints = constraints
+	if cs.profile != nil {
+		cs.profile.constraints = cs.profile.constraints[:len(constraints)]
+	}
+
+	if len(subs) == 0 {
+		return
+	}
+	for i := 0; i < len(cs.assertions); i++ {
+		cs.assertions[i].L = cs.substitute(cs.assertions[i].L, subs)
+		cs.assertions[i].R = cs.substitute(cs.assertions[i].R, subs)
+		cs.assertions[i].O = cs.substitute(cs.assertions[i].O, subs)
+	}
+	for i := 0; i < len(cs.hints); i++ {
+		for j := 0; j < len(cs.hints[i].Inputs); j++ {
+			cs.hints[i].Inputs[j] = cs.substitute(cs.hints[i].Inputs[j], subs)
+		}
+	}
+
+	// the logged wires can only be replaced by the output wire of a previous constraint
+	for _, entries := range [2][]logEntry{cs.logs, cs.debugInfo} {
+		for i := 0; i < len(entries); i++ {
+			toResolve := make([]r1c.Term, len(entries[i].toResolve))
+			copy(toResolve, entries[i].toResolve)
+			for j, t := range toResolve {
+				if t.ConstraintVisibility() != backend.Internal {
+					continue
+				}
+				if s, ok := subs[t.VariableID()]; ok {
+					toResolve[j].SetVariableID(s[0].VariableID())
+				}
+			}
+			entries[i].toResolve = toResolve
+		}
+	}
+
+	return
+}
+
+// outputWire returns the wire w if c is L * R == w, w being an internal wire solved by c
+// (not used by a previous constraint) which may be replaced in the constraint system
+func (cs *ConstraintSystem) outputWire(c r1c.R1C, seen, pinned map[int]struct{}) (int, bool) {
+	if c.Solver != r1c.SingleOutput || len(c.O) != 1 {
+		return 0, false
+	}
+	_, _, w, visibility := c.O[0].Unpack()
+	if visibility != backend.Internal || c.O[0].CoeffValue() != 1 {
+		return 0, false
+	}
+	if _, ok := seen[w]; ok {
+		return 0, false
+	}
+	if _, ok := pinned[w]; ok {
+		return 0, false
+	}
+	for _, l := range [2]r1c.LinearExpression{c.L, c.R} {
+		for _, t := range l {
+			if t.ConstraintVisibility() == backend.Internal && t.VariableID() == w {
+				return 0, false
+			}
+		}
+	}
+	return w, true
+}
+
+// constantValue returns the value of l if it only depends on the wire ONE_WIRE
+func (cs *ConstraintSystem) constantValue(l r1c.LinearExpression) (big.Int, bool) {
+	var res big.Int
+	for _, t := range l {
+		if t.ConstraintVisibility() != backend.Public || t.VariableID() != 0 {
+			return res, false
+		}
+		res.Add(&res, &cs.coeffs[t.CoeffID()])
+	}
+	return res, true
+}
+
+// substitute returns l, where the internal wires in subs are replaced by their linear expression
+func (cs *ConstraintSystem) substitute(l r1c.LinearExpression, subs map[int]r1c.LinearExpression) r1c.LinearExpression {
+	if len(subs) == 0 {
+		return l
+	}
+
+	var res r1c.LinearExpression
+	substituted := false
+	for _, t := range l {
+		if t.ConstraintVisibility() == backend.Internal {
+			if s, ok := subs[t.VariableID()]; ok {
+				res = append(res, cs.mulConstant(cs.coeffs[t.CoeffID()], Variable{linExp: s}).linExp...)
+				substituted = true
+				continue
+			}
+		}
+		res = append(res, t)
+	}
+	if !substituted {
+		return l
+	}
+
+	return cs.reduce(res)
+}
+
+// deduplicateAssertions removes the assertions identical to a previous one (and their debug info)
+// and returns the number of assertions removed
+func (cs *ConstraintSystem) deduplicateAssertions() int {
+	seen := make(map[string]struct{})
+
+	assertions := cs.assertions[:0]
+	debugInfo := cs.debugInfo[:0]
+	for i, c := range cs.assertions {
+		key := productKey(c.L, c.R) + "=" + linExpKey(c.O)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if cs.profile != nil {
+			cs.profile.assertions[len(assertions)] = cs.profile.assertions[i]
+		}
+		assertions = append(assertions, c)
+		debugInfo = append(debugInfo, cs.debugInfo[i])
+	}
+	if cs.profile != nil {
+		cs.profile.assertions = cs.profile.assertions[:len(assertions)]
+	}
+
+	nbRemoved := len(cs.assertions) - len(assertions)
+	cs.assertions = assertions
+	cs.debugInfo = debugInfo
+
+	return nbRemoved
+}
+
+// compactWires removes the internal wires which are not used by any constraint, log or debug info
+// (and their hints), renumbers the remaining ones, and returns the number of wires removed
+func (cs *ConstraintSystem) compactWires() int {
+	used := make([]bool, len(cs.internal.variables))
+	markUsed := func(l r1c.LinearExpression) {
+		for _, t := range l {
+			if t.ConstraintVisibility() == backend.Internal {
+				used[t.VariableID()] = true
+			}
+		}
+	}
+
+	for _, constraints := range [2][]r1c.R1C{cs.constraints, cs.assertions} {
+		for _, c := range constraints {
+			markUsed(c.L)
+			markUsed(c.R)
+			markUsed(c.O)
+		}
+	}
+	for _, entries := range [2][]logEntry{cs.logs, cs.debugInfo} {
+		for _, entry := range entries {
+			markUsed(entry.toResolve)
+		}
+	}
+
+	// the inputs of a hint are created before it: processing the hints backward marks
+	// the wires of the hints only used as inputs of other (used) hints
+	for i := len(cs.hints) - 1; i >= 0; i-- {
+		if used[cs.hints[i].WireID] {
+			for _, input := range cs.hints[i].Inputs {
+				markUsed(input)
+			}
+		}
+	}
+
+	newIDs := make([]int, len(cs.internal.variables))
+	nbWires := 0
+	for i := 0; i < len(used); i++ {
+		if used[i] {
+			newIDs[i] = nbWires
+			nbWires++
+		}
+	}
+
+	nbRemoved := len(cs.internal.variables) - nbWires
+	if nbRemoved == 0 {
+		return 0
+	}
+
+	renumber := func(l r1c.LinearExpression) r1c.LinearExpression {
+		res := make(r1c.LinearExpression, len(l))
+		copy(res, l)
+		for j := 0; j < len(res); j++ {
+			if res[j].ConstraintVisibility() == backend.Internal {
+				res[j].SetVariableID(newIDs[res[j].VariableID()])
+			}
+		}
+		return res
+	}
+
+	for _, constraints := range [2][]r1c.R1C{cs.constraints, cs.assertions} {
+		for i := 0; i < len(constraints); i++ {
+			constraints[i].L = renumber(constraints[i].L)
+			constraints[i].R = renumber(constraints[i].R)
+			constraints[i].O = renumber(constraints[i].O)
+		}
+	}
+	for _, entries := range [2][]logEntry{cs.logs, cs.debugInfo} {
+		for i := 0; i < len(entries); i++ {
+			entries[i].toResolve = renumber(entries[i].toResolve)
+		}
+	}
+
+	hints := cs.hints[:0]
+	for _, h := range cs.hints {
+		if !used[h.WireID] {
+			continue
+		}
+		h.WireID = newIDs[h.WireID]
+		for j := 0; j < len(h.Inputs); j++ {
+			h.Inputs[j] = renumber(h.Inputs[j])
+		}
+		hints = append(hints, h)
+	}
+	cs.hints = hints
+
+	booleans := make(map[int]struct{}, len(cs.internal.booleans))
+	for id := range cs.internal.booleans {
+		if used[id] {
+			booleans[newIDs[id]] = struct{}{}
+		}
+	}
+	cs.internal.booleans = booleans
+
+	cs.internal.variables = cs.internal.variables[:nbWires]
+	for i := 0; i < nbWires; i++ {
+		cs.internal.variables[i] = cs.buildVarFromPartialVar(Wire{visibility: backend.Internal, id: i})
+	}
+
+	return nbRemoved
+}
+
+// linExpKey returns a string identifying l, independent of the order of its terms
+func linExpKey(l r1c.LinearExpression) string {
+	terms := make([]r1c.Term, len(l))
+	copy(terms, l)
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].ConstraintVisibility() != terms[j].ConstraintVisibility() {
+			return terms[i].ConstraintVisibility() < terms[j].ConstraintVisibility()
+		}
+		return terms[i].VariableID() < terms[j].VariableID()
+	})
+
+	var sb strings.Builder
+	for _, t := range terms {
+		sb.WriteString(strconv.Itoa(int(t.ConstraintVisibility())))
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(t.VariableID()))
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(t.CoeffID()))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
+
+// productKey returns a string identifying L * R, independent of the order of the multiplicands
+func productKey(L, R r1c.LinearExpression) string {
+	l, r := linExpKey(L), linExpKey(R)
+	if l > r {
+		l, r = r, l
+	}
+	return l + "*" + r
+}
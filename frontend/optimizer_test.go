@@ -0,0 +1,263 @@
+package frontend
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend/r1cs"
+	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+type optimizerCircuit struct {
+	X, Y Variable
+	Z    Variable `gnark:",public"`
+}
+
+// Define declares 3x * xy + yx == z
+func (c *optimizerCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	a := cs.Mul(c.X, cs.Constant(3)) // inlined
+	b := cs.Mul(c.X, c.Y)
+	_b := cs.Mul(c.Y, c.X) // deduplicated
+	d := cs.Mul(a, b)
+	cs.AssertIsEqual(cs.Add(d, _b), c.Z)
+	cs.AssertIsEqual(cs.Add(d, b), c.Z) // deduplicated
+	return nil
+}
+
+func TestOptimizer(t *testing.T) {
+	var circuit optimizerCircuit
+	var report OptimizationReport
+	r1cs, err := Compile(gurvy.BN256, &circuit, WithOptimizationReport(&report))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := OptimizationReport{
+		NbConstraintsBefore: 6,
+		NbConstraintsAfter:  3,
+		NbInlined:           1,
+		NbDeduplicated:      2,
+		NbWiresRemoved:      2,
+	}
+	if report != expected {
+		t.Fatal("unexpected optimization report", report)
+	}
+	if report.NbConstraintsSaved() != 3 || r1cs.GetNbConstraints() != 3 || r1cs.GetNbWires() != 6 {
+		t.Fatal("the optimized R1CS should have 3 constraints and 6 wires", report)
+	}
+
+	// x = 2, y = 5
+	if err := r1cs.IsSolved(map[string]interface{}{"X": 2, "Y": 5, "Z": 70}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r1cs.IsSolved(map[string]interface{}{"X": 2, "Y": 5, "Z": 71}); err == nil {
+		t.Fatal("IsSolved should have failed")
+	}
+
+	var unoptimized optimizerCircuit
+	r1cs, err = Compile(gurvy.BN256, &unoptimized, WithoutOptimization())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1cs.GetNbConstraints() != 6 {
+		t.Fatal("the R1CS shouldn't be optimized")
+	}
+}
+
+// programCircuit chains operations on its inputs, such that some constraints may be optimized
+type programCircuit struct {
+	X, Y    Variable
+	Z       Variable `gnark:",public"`
+	program []instruction
+}
+
+type instruction struct {
+	op, a, b int
+	c        uint64
+}
+
+const nbOps = 6
+
+func (c *programCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	stack := []Variable{c.X, c.Y}
+	for _, ins := range c.program {
+		a, b := stack[ins.a%len(stack)], stack[ins.b%len(stack)]
+		switch ins.op {
+		case 0:
+			stack = append(stack, cs.Mul(a, b))
+		case 1:
+			stack = append(stack, cs.Mul(b, a))
+		case 2:
+			stack = append(stack, cs.Mul(a, cs.Constant(ins.c)))
+		case 3:
+			stack = append(stack, cs.Add(a, b))
+		case 4:
+			stack = append(stack, cs.Sub(a, ins.c))
+		case 5:
+			stack = append(stack, cs.IsZero(a))
+		}
+	}
+	cs.AssertIsEqual(stack[len(stack)-1], c.Z)
+	cs.AssertIsEqual(stack[len(stack)-1], c.Z)
+	return nil
+}
+
+// eval returns the value of Z
+func (c *programCircuit) eval(x, y uint64) *big.Int {
+	stack := []*big.Int{new(big.Int).SetUint64(x), new(big.Int).SetUint64(y)}
+	for _, ins := range c.program {
+		a, b := stack[ins.a%len(stack)], stack[ins.b%len(stack)]
+		res := new(big.Int)
+		switch ins.op {
+		case 0, 1:
+			res.Mul(a, b)
+		case 2:
+			res.Mul(a, new(big.Int).SetUint64(ins.c))
+		case 3:
+			res.Add(a, b)
+		case 4:
+			res.Sub(a, new(big.Int).SetUint64(ins.c))
+		case 5:
+			if a.Sign() == 0 {
+				res.SetUint64(1)
+			}
+		}
+		stack = append(stack, res.Mod(res, fr.Modulus()))
+	}
+	return stack[len(stack)-1]
+}
+
+func TestOptimizerSolutions(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 50
+
+	properties := gopter.NewProperties(parameters)
+
+	genInstruction := gopter.CombineGens(gen.IntRange(0, nbOps-1), gen.IntRange(0, 3), gen.IntRange(0, 3), gen.UInt64Range(0, 3)).
+		Map(func(values []interface{}) instruction {
+			return instruction{values[0].(int), values[1].(int), values[2].(int), values[3].(uint64)}
+		})
+
+	properties.Property("the optimized R1CS should have the same solutions as the R1CS", prop.ForAll(
+		func(program []instruction, x, y uint64) bool {
+			optimized, err := Compile(gurvy.BN256, &programCircuit{program: program})
+			if err != nil {
+				return false
+			}
+			r1cs, err := Compile(gurvy.BN256, &programCircuit{program: program}, WithoutOptimization())
+			if err != nil {
+				return false
+			}
+			if optimized.GetNbConstraints() > r1cs.GetNbConstraints() {
+				return false
+			}
+
+			z := (&programCircuit{program: program}).eval(x, y)
+			good := map[string]interface{}{"X": x, "Y": y, "Z": z}
+			bad := map[string]interface{}{"X": x, "Y": y, "Z": new(big.Int).Add(z, bOne)}
+
+			return r1cs.IsSolved(good) == nil && optimized.IsSolved(good) == nil &&
+				r1cs.IsSolved(bad) != nil && optimized.IsSolved(bad) != nil
+		},
+		gen.SliceOfN(12, genInstruction),
+		gen.OneGenOf(gen.UInt64(), gen.UInt64Range(0, 3)),
+		gen.OneGenOf(gen.UInt64(), gen.UInt64Range(0, 3)),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// The optimized R1CS must not let a prover pick the internal wires: solving the inputs, then changing the
+// value of any internal wire, should leave a constraint unsatisfied. IsSolved can't check that on its own,
+// as it computes the internal wires from the inputs.
+func TestOptimizerTamperedWires(t *testing.T) {
+	var circuit optimizerCircuit
+	r1cs, err := Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tamperedWiresRejected(r1cs, map[string]interface{}{"X": 2, "Y": 5, "Z": 70}) {
+		t.Fatal("the optimized R1CS accepts a tampered internal wire")
+	}
+
+	// the programs without IsZero, whose inverse hint is free when its input is 0
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 50
+
+	properties := gopter.NewProperties(parameters)
+
+	genInstruction := gopter.CombineGens(gen.IntRange(0, nbOps-2), gen.IntRange(0, 3), gen.IntRange(0, 3), gen.UInt64Range(0, 3)).
+		Map(func(values []interface{}) instruction {
+			return instruction{values[0].(int), values[1].(int), values[2].(int), values[3].(uint64)}
+		})
+
+	properties.Property("the optimized R1CS should reject tampered internal wires", prop.ForAll(
+		func(program []instruction, x, y uint64) bool {
+			optimized, err := Compile(gurvy.BN256, &programCircuit{program: program})
+			if err != nil {
+				return false
+			}
+			z := (&programCircuit{program: program}).eval(x, y)
+			return tamperedWiresRejected(optimized, map[string]interface{}{"X": x, "Y": y, "Z": z})
+		},
+		gen.SliceOfN(12, genInstruction),
+		gen.OneGenOf(gen.UInt64(), gen.UInt64Range(0, 3)),
+		gen.OneGenOf(gen.UInt64(), gen.UInt64Range(0, 3)),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// tamperedWiresRejected solves the (BN256) R1CS with solution, and checks that incrementing any
+// internal wire leaves at least one constraint unsatisfied
+func tamperedWiresRejected(_r1cs r1cs.R1CS, solution map[string]interface{}) bool {
+	r1cs := _r1cs.(*backend_bn256.R1CS)
+	a := make([]fr.Element, r1cs.NbConstraints)
+	b := make([]fr.Element, r1cs.NbConstraints)
+	c := make([]fr.Element, r1cs.NbConstraints)
+	wireValues := make([]fr.Element, r1cs.NbWires)
+	if err := r1cs.Solve(solution, a, b, c, wireValues); err != nil {
+		return false
+	}
+
+	satisfied := func(wireValues []fr.Element) bool {
+		for _, constraint := range r1cs.Constraints {
+			var a, b, c fr.Element
+			for _, t := range constraint.L {
+				r1cs.AddTerm(&a, t, wireValues[t.VariableID()])
+			}
+			for _, t := range constraint.R {
+				r1cs.AddTerm(&b, t, wireValues[t.VariableID()])
+			}
+			for _, t := range constraint.O {
+				r1cs.AddTerm(&c, t, wireValues[t.VariableID()])
+			}
+			if !a.Mul(&a, &b).Equal(&c) {
+				return false
+			}
+		}
+		return true
+	}
+	if !satisfied(wireValues) {
+		return false
+	}
+
+	// wireValues = [internal wires | secret inputs | public inputs]
+	nbInternal := int(r1cs.NbWires - r1cs.NbSecretWires - r1cs.NbPublicWires)
+	var one fr.Element
+	one.SetOne()
+	for i := 0; i < nbInternal; i++ {
+		tampered := make([]fr.Element, len(wireValues))
+		copy(tampered, wireValues)
+		tampered[i].Add(&tampered[i], &one)
+		if satisfied(tampered) {
+			return false
+		}
+	}
+	return true
+}
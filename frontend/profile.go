@@ -0,0 +1,329 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Profile records the call stack of each constraint of a circuit, to find out which parts
+// of the circuit are the most expensive (see WithProfile).
+//
+// The constraints removed by the optimization of the constraint system are not counted.
+//
+// The profile can be written in the pprof format, and analyzed with go tool pprof:
+//
+//	f, _ := os.Create("circuit.pprof")
+//	profile.WriteTo(f)
+//	// go tool pprof -top circuit.pprof
+type Profile struct {
+	frames      []profileFrame       // frames of the call stacks
+	frameIDs    map[profileFrame]int // frame -> index in frames
+	stacks      [][]int              // call stacks, as indexes in frames (leaf first)
+	stackIDs    map[string]int       // call stack key -> index in stacks
+	constraints []int                // call stack (index in stacks) of each computational constraint
+	assertions  []int                // call stack (index in stacks) of each assertion
+}
+
+type profileFrame struct {
+	function string
+	file     string
+	line     int
+}
+
+// WithProfile records the call stack of each constraint in profile
+func WithProfile(profile *Profile) CompileOption {
+	return func(opt *compileConfig) {
+		opt.profile = profile
+	}
+}
+
+func (p *Profile) reset() {
+	*p = Profile{
+		frameIDs: make(map[profileFrame]int),
+		stackIDs: make(map[string]int),
+	}
+}
+
+// maxStackDepth bounds the number of frames recorded for a constraint
+const maxStackDepth = 64
+
+// record returns the index of the call stack of the caller of addConstraint or addAssertion.
+// As in getCallStack, the frames before circuit.Define are ignored
+func (p *Profile) record() int {
+	pc := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	var key strings.Builder
+	var stack []int
+	for {
+		frame, more := frames.Next()
+		f := profileFrame{function: frame.Function, file: frame.File, line: frame.Line}
+		id, ok := p.frameIDs[f]
+		if !ok {
+			id = len(p.frames)
+			p.frames = append(p.frames, f)
+			p.frameIDs[f] = id
+		}
+		stack = append(stack, id)
+		key.WriteString(strconv.Itoa(id))
+		key.WriteByte(',')
+		if !more || strings.HasSuffix(frame.Function, "Define") {
+			break
+		}
+	}
+
+	id, ok := p.stackIDs[key.String()]
+	if !ok {
+		id = len(p.stacks)
+		p.stacks = append(p.stacks, stack)
+		p.stackIDs[key.String()] = id
+	}
+	return id
+}
+
+// NbConstraints returns the number of constraints recorded (including the assertions)
+func (p *Profile) NbConstraints() int {
+	return len(p.constraints) + len(p.assertions)
+}
+
+// counts returns the number of constraints of each call stack
+func (p *Profile) counts() []int {
+	counts := make([]int, len(p.stacks))
+	for _, id := range p.constraints {
+		counts[id]++
+	}
+	for _, id := range p.assertions {
+		counts[id]++
+	}
+	return counts
+}
+
+// Summary returns a text summary of the profile: for each function, the number of constraints
+// it directly adds (flat) and the number of constraints added by it or its callees (cum),
+// sorted by decreasing cum
+func (p *Profile) Summary() string {
+	counts := p.counts()
+
+	type entry struct {
+		function  string
+		flat, cum int
+	}
+	entries := make(map[string]*entry)
+	get := func(function string) *entry {
+		e, ok := entries[function]
+		if !ok {
+			e = &entry{function: function}
+			entries[function] = e
+		}
+		return e
+	}
+
+	for id, stack := range p.stacks {
+		if counts[id] == 0 {
+			continue
+		}
+		get(p.frames[stack[0]].function).flat += counts[id]
+
+		// recursive functions are counted once per call stack
+		seen := make(map[string]struct{})
+		for _, frameID := range stack {
+			function := p.frames[frameID].function
+			if _, ok := seen[function]; ok {
+				continue
+			}
+			seen[function] = struct{}{}
+			get(function).cum += counts[id]
+		}
+	}
+
+	sorted := make([]*entry, 0, len(entries))
+	for _, e := range entries {
+		sorted = append(sorted, e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].cum != sorted[j].cum {
+			return sorted[i].cum > sorted[j].cum
+		}
+		if sorted[i].flat != sorted[j].flat {
+			return sorted[i].flat > sorted[j].flat
+		}
+		return sorted[i].function < sorted[j].function
+	})
+
+	total := p.NbConstraints()
+	percent := func(n int) string {
+		if total == 0 {
+			return "0.00%"
+		}
+		return fmt.Sprintf("%.2f%%", 100*float64(n)/float64(total))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d constraints\n", total))
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "flat\tflat%\tcum\tcum%\t\tfunction")
+	for _, e := range sorted {
+		// same format as getCallStack
+		fe := strings.Split(e.function, "/")
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t\t%s\n", e.flat, percent(e.flat), e.cum, percent(e.cum), fe[len(fe)-1])
+	}
+	_ = w.Flush()
+
+	return sb.String()
+}
+
+// WriteTo writes the profile in the pprof format (gzip compressed protocol buffer, see
+// https://github.com/google/pprof/blob/master/proto/profile.proto) to w
+func (p *Profile) WriteTo(w io.Writer) (int64, error) {
+	// string table, the first entry must be ""
+	strs := []string{""}
+	strIDs := map[string]int{"": 0}
+	str := func(s string) uint64 {
+		id, ok := strIDs[s]
+		if !ok {
+			id = len(strs)
+			strs = append(strs, s)
+			strIDs[s] = id
+		}
+		return uint64(id)
+	}
+
+	var b protoBuffer
+
+	// sample_type
+	var valueType protoBuffer
+	valueType.uint64Field(1, str("constraints"))
+	valueType.uint64Field(2, str("count"))
+	b.bytesField(1, valueType)
+
+	// samples
+	counts := p.counts()
+	for id, stack := range p.stacks {
+		if counts[id] == 0 {
+			continue
+		}
+		locationIDs := make([]uint64, len(stack))
+		for i, frameID := range stack {
+			locationIDs[i] = uint64(frameID) + 1
+		}
+		var sample protoBuffer
+		sample.packedField(1, locationIDs)
+		sample.packedField(2, []uint64{uint64(counts[id])})
+		b.bytesField(2, sample)
+	}
+
+	// locations (one per frame) and functions
+	functionIDs := make(map[[2]string]uint64)
+	var functions protoBuffer
+	for i, f := range p.frames {
+		key := [2]string{f.function, f.file}
+		functionID, ok := functionIDs[key]
+		if !ok {
+			functionID = uint64(len(functionIDs)) + 1
+			functionIDs[key] = functionID
+
+			var function protoBuffer
+			function.uint64Field(1, functionID)
+			function.uint64Field(2, str(f.function))
+			function.uint64Field(3, str(f.function))
+			function.uint64Field(4, str(f.file))
+			functions.bytesField(5, function)
+		}
+
+		var line protoBuffer
+		line.uint64Field(1, functionID)
+		line.uint64Field(2, uint64(f.line))
+
+		var location protoBuffer
+		location.uint64Field(1, uint64(i)+1)
+		location.bytesField(4, line)
+		b.bytesField(4, location)
+	}
+	b = append(b, functions...)
+
+	for _, s := range strs {
+		b.bytesField(6, []byte(s))
+	}
+
+	// period_type and period: each sample counts constraints
+	var periodType protoBuffer
+	periodType.uint64Field(1, str("constraints"))
+	periodType.uint64Field(2, str("count"))
+	b.bytesField(11, periodType)
+	b.uint64Field(12, 1)
+
+	cw := &countingWriter{w: w}
+	zw := gzip.NewWriter(cw)
+	if _, err := zw.Write(b); err != nil {
+		return cw.n, err
+	}
+	err := zw.Close()
+	return cw.n, err
+}
+
+// protoBuffer encodes protocol buffer messages
+type protoBuffer []byte
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		*b = append(*b, byte(x)|0x80)
+		x >>= 7
+	}
+	*b = append(*b, byte(x))
+}
+
+// uint64Field encodes a varint field (wire type 0)
+func (b *protoBuffer) uint64Field(tag int, x uint64) {
+	b.varint(uint64(tag) << 3)
+	b.varint(x)
+}
+
+// bytesField encodes a length-delimited field (wire type 2)
+func (b *protoBuffer) bytesField(tag int, data []byte) {
+	b.varint(uint64(tag)<<3 | 2)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+// packedField encodes a packed repeated varint field
+func (b *protoBuffer) packedField(tag int, xs []uint64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytesField(tag, packed)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,75 @@
+package frontend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gurvy"
+)
+
+type profiledCircuit struct {
+	X, Y Variable
+}
+
+func (c *profiledCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(c.X, c.X), cs.Mul(c.Y, c.Y, c.Y))
+	cs.AssertIsBoolean(cs.Xor(c.X, c.Y))
+	cs.AssertIsBoolean(cs.Mul(c.X, cs.Constant(2))) // Mul is optimized
+	return nil
+}
+
+func TestProfile(t *testing.T) {
+	var profile Profile
+	r1cs, err := Compile(gurvy.BN256, &profiledCircuit{}, WithProfile(&profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.NbConstraints() != int(r1cs.GetNbConstraints()) {
+		t.Fatal("the profile should record all the constraints", profile.NbConstraints(), r1cs.GetNbConstraints())
+	}
+
+	// Mul: 3 constraints, AssertIsEqual: 1, Xor: 1 + 2 boolean constraints
+	// (the output of Xor is already boolean), AssertIsBoolean: 1
+	summary := profile.Summary()
+	for _, expected := range []string{
+		"8 constraints",
+		"0   0.00%    8  100.00%  frontend.(*profiledCircuit).Define",
+		"3  37.50%    3   37.50%  frontend.(*ConstraintSystem).Mul.func1",
+		"1  12.50%    3   37.50%  frontend.(*ConstraintSystem).Xor",
+		"3  37.50%    3   37.50%  frontend.(*ConstraintSystem).AssertIsBoolean",
+		"1  12.50%    1   12.50%  frontend.(*ConstraintSystem).AssertIsEqual",
+	} {
+		if !strings.Contains(summary, expected) {
+			t.Fatal("the summary should contain "+expected, summary)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := profile.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatal("WriteTo should return the number of bytes written")
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(raw, []byte("frontend.(*profiledCircuit).Define")) {
+		t.Fatal("the pprof profile should contain the functions names")
+	}
+
+	// profiling is opt-in
+	var cs ConstraintSystem
+	if cs.profile != nil {
+		t.Fatal("profiling should be disabled by default")
+	}
+}
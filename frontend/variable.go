@@ -38,8 +38,7 @@ type Wire struct {
 // circuit when there is no other choice (to avoid wasting wires doing only linear expressions)
 type Variable struct {
 	Wire
-	linExp    r1c.LinearExpression
-	isBoolean bool // TODO it doesn't work, we need a pointer for that
+	linExp r1c.LinearExpression
 }
 
 // Assign v = value . This must called when using a Circuit as a witness data structure
@@ -98,7 +97,7 @@ func parseType(input interface{}, baseName string, parentVisibility backend.Visi
 		tValue = tValue.Elem()
 	}
 
-	// we either have a pointer, a struct, or a slice / array
+	// we either have a pointer, a struct, a slice / array, or an interface holding a pointer
 	// and recursively parse members / elements until we find a constraint to allOoutputcate in the circuit.
 	switch tValue.Kind() {
 	case reflect.Struct:
@@ -174,6 +173,16 @@ func parseType(input interface{}, baseName string, parentVisibility backend.Visi
 			}
 
 		}
+	case reflect.Interface:
+		// the dynamic value must be a pointer for its variables to be settable
+		if tValue.IsNil() {
+			return nil
+		}
+		if tValue.Elem().Kind() != reflect.Ptr {
+			fmt.Println("warning: interface value is not a pointer, ignoring", baseName)
+			return nil
+		}
+		return parseType(tValue.Elem().Interface(), baseName, parentVisibility, handler)
 	case reflect.Map:
 		fmt.Println("warning: map values are not addressable, ignoring")
 	}
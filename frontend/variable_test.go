@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gurvy"
 )
 
 func TestStructTags(t *testing.T) {
@@ -160,4 +161,60 @@ func TestStructTags(t *testing.T) {
 		testParseType(&s, expected)
 	}
 
+	// interface holding a pointer
+	{
+		type child struct {
+			D Variable
+		}
+		s := struct {
+			A interface{} `gnark:",public"`
+			B interface{}
+			C interface{}
+		}{A: &child{}, C: child{}}
+		expected := make(map[string]backend.Visibility)
+		expected["A_D"] = backend.Public
+		testParseType(&s, expected)
+	}
+
+}
+
+type interfaceFieldCircuit struct {
+	X Variable
+	Y interface{} `gnark:",public"`
+}
+
+type interfaceFieldChild struct {
+	Z Variable
+}
+
+func (c *interfaceFieldCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	cs.AssertIsEqual(cs.Mul(c.X, c.X), c.Y.(*interfaceFieldChild).Z)
+	return nil
+}
+
+func TestInterfaceField(t *testing.T) {
+	circuit := interfaceFieldCircuit{Y: &interfaceFieldChild{}}
+	r1cs, err := Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the variables behind the interface are part of the witness
+	witness := interfaceFieldCircuit{Y: &interfaceFieldChild{}}
+	witness.X.Assign(3)
+	witness.Y.(*interfaceFieldChild).Z.Assign(9)
+	assignment, err := ParseWitness(&witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := assignment["Y_Z"]; !ok {
+		t.Fatal("Y_Z missing from the witness", assignment)
+	}
+	if err := r1cs.IsSolved(assignment); err != nil {
+		t.Fatal(err)
+	}
+	assignment["Y_Z"] = 10
+	if err := r1cs.IsSolved(assignment); err == nil {
+		t.Fatal("witness with a wrong interface field value should not solve the system")
+	}
 }
@@ -17,10 +17,18 @@ limitations under the License.
 package gnark
 
 import (
+	"bytes"
+	"encoding/json"
+	"math/bits"
 	"os"
 	"testing"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/groth16/mpcsetup"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/crypto/kzg"
 	"github.com/consensys/gnark/internal/backend/circuits"
 	"github.com/consensys/gurvy"
 )
@@ -71,7 +79,198 @@ func TestIntegrationAPI(t *testing.T) {
 				t.Fatal("Verify should have failed")
 			}
 
+			// same workflow with a typed witness, through its binary and JSON encodings
+			goodWitness, err := witness.FromCircuit(curve, circuit.Good)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var bufWitness bytes.Buffer
+			if _, err := goodWitness.WriteTo(&bufWitness); err != nil {
+				t.Fatal(err)
+			}
+			goodWitness = witness.New(curve)
+			if _, err := goodWitness.ReadFrom(&bufWitness); err != nil {
+				t.Fatal(err)
+			}
+			if err := typedR1CS.IsSolved(goodWitness); err != nil {
+				t.Fatal("IsSolved should have succeeded", err)
+			}
+			witnessProof, err := groth16.Prove(typedR1CS, pk, goodWitness)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicJSON, err := json.Marshal(goodWitness.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicWitness := witness.New(curve)
+			if err := json.Unmarshal(publicJSON, publicWitness); err != nil {
+				t.Fatal(err)
+			}
+			if err := groth16.Verify(witnessProof, vk, publicWitness); err != nil {
+				t.Fatal("Verify should have succeeded", err)
+			}
+			if err := groth16.Verify(wrongProof, vk, publicWitness); err == nil {
+				t.Fatal("Verify should have failed")
+			}
+
+			// batch verification finds the wrong proof
+			err = groth16.BatchVerify([]groth16.Proof{correctProof, wrongProof}, vk, []interface{}{circuit.Public, publicWitness}, true)
+			if batchErr, ok := err.(*backend.BatchVerificationError); !ok || len(batchErr.InvalidProofs) != 1 || batchErr.InvalidProofs[0] != 1 {
+				t.Fatal("BatchVerify should have found the wrong proof")
+			}
+
+			// aggregation of the proofs, through a serialized SRS
+			aggregationSRS, err := groth16.AggregationSetup(curve, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var bufAggregationSRS bytes.Buffer
+			if _, err := aggregationSRS.WriteTo(&bufAggregationSRS); err != nil {
+				t.Fatal(err)
+			}
+			aggregationSRS = groth16.NewAggregationSRS(curve)
+			if _, err := aggregationSRS.ReadFrom(&bufAggregationSRS); err != nil {
+				t.Fatal(err)
+			}
+			publicWitnesses := []interface{}{circuit.Public, publicWitness}
+			aggregate, err := groth16.Aggregate([]groth16.Proof{correctProof, correctProof}, vk, aggregationSRS, publicWitnesses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := groth16.VerifyAggregate(aggregate, vk, aggregationSRS, publicWitnesses); err != nil {
+				t.Fatal("VerifyAggregate should have succeeded", err)
+			}
+			aggregate, err = groth16.Aggregate([]groth16.Proof{correctProof, wrongProof}, vk, aggregationSRS, publicWitnesses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := groth16.VerifyAggregate(aggregate, vk, aggregationSRS, publicWitnesses); err == nil {
+				t.Fatal("VerifyAggregate should have failed")
+			}
+
+			// same workflow with plonk, using a serialized KZG SRS
+			srs, err := kzg.NewTestSRS(curve, plonk.SizeSRS(typedR1CS))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var bufSRS bytes.Buffer
+			if _, err := srs.WriteTo(&bufSRS); err != nil {
+				t.Fatal(err)
+			}
+			srsReconstructed := kzg.NewSRS(curve)
+			if _, err := srsReconstructed.ReadFrom(&bufSRS); err != nil {
+				t.Fatal(err)
+			}
+			plonkPK, plonkVK, err := plonk.SetupWithSRS(typedR1CS, srsReconstructed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plonkCorrectProof, err := plonk.Prove(typedR1CS, plonkPK, circuit.Good)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plonkWrongProof, err := plonk.Prove(typedR1CS, plonkPK, circuit.Bad, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = plonk.Verify(plonkCorrectProof, plonkVK, circuit.Public)
+			if err != nil {
+				t.Fatal("Verify should have succeeded")
+			}
+			err = plonk.Verify(plonkWrongProof, plonkVK, circuit.Public)
+			if err == nil {
+				t.Fatal("Verify should have failed")
+			}
+
+			// and with a typed witness
+			plonkWitnessProof, err := plonk.Prove(typedR1CS, plonkPK, goodWitness)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := plonk.Verify(plonkWitnessProof, plonkVK, publicWitness); err != nil {
+				t.Fatal("Verify should have succeeded", err)
+			}
+			if err := plonk.Verify(plonkWrongProof, plonkVK, publicWitness); err == nil {
+				t.Fatal("Verify should have failed")
+			}
+
 		}
 	}
 
 }
+
+func TestIntegrationMPCSetup(t *testing.T) {
+	const nbContributions = 2
+	circuit := circuits.Circuits["frombinary"]
+	curves := []gurvy.ID{gurvy.BN256, gurvy.BLS377, gurvy.BLS381, gurvy.BW761}
+
+	for _, curve := range curves {
+		t.Log(curve.String())
+		typedR1CS := circuit.R1CS.ToR1CS(curve)
+
+		// phase 1, each contribution goes through its serialization
+		power := uint8(bits.Len64(typedR1CS.GetNbConstraints() - 1))
+		phase1 := mpcsetup.InitPhase1(curve, power)
+		for i := 0; i < nbContributions; i++ {
+			var buf bytes.Buffer
+			if _, err := phase1.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			next := mpcsetup.NewPhase1(curve)
+			if _, err := next.ReadFrom(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if err := next.Contribute(nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := mpcsetup.VerifyPhase1(phase1, next); err != nil {
+				t.Fatal(err)
+			}
+			phase1 = next
+		}
+
+		// phase 2
+		phase2, evals, err := mpcsetup.InitPhase2(typedR1CS, phase1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < nbContributions; i++ {
+			var buf bytes.Buffer
+			if _, err := phase2.WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+			next := mpcsetup.NewPhase2(curve)
+			if _, err := next.ReadFrom(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if err := next.Contribute(nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := mpcsetup.VerifyPhase2(phase2, next); err != nil {
+				t.Fatal(err)
+			}
+			phase2 = next
+		}
+
+		pk, vk, err := mpcsetup.ExtractKeys(typedR1CS, phase2, evals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		correctProof, err := groth16.Prove(typedR1CS, pk, circuit.Good)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := groth16.Verify(correctProof, vk, circuit.Public); err != nil {
+			t.Fatal("Verify should have succeeded")
+		}
+		wrongProof, err := groth16.Prove(typedR1CS, pk, circuit.Bad, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := groth16.Verify(wrongProof, vk, circuit.Public); err == nil {
+			t.Fatal("Verify should have failed")
+		}
+	}
+}
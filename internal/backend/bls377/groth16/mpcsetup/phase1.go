@@ -0,0 +1,280 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package mpcsetup
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gurvy/bls377/fr"
+
+	curve "github.com/consensys/gurvy/bls377"
+
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gurvy"
+)
+
+var (
+	errInvalidPhase1Size    = errors.New("the contributions don't have the same size")
+	errPhase1KnowledgeProof = errors.New("couldn't verify the proofs of knowledge of τ, α and β")
+	errPhase1Update         = errors.New("couldn't verify that [τ]₁, α[τ⁰]₁, β[τ⁰]₁ and [β]₂ were updated with the contributed τ, α and β")
+	errPhase1Powers         = errors.New("couldn't verify that the parameters are powers of τ")
+	errPhase1Generators     = errors.New("[τ⁰]₁ and [τ⁰]₂ must be the generators of G₁ and G₂")
+	errPhase1Subgroup       = errors.New("the parameters are not in the correct subgroups")
+	errInvalidHash          = errors.New("the hash of the contribution doesn't match its content")
+)
+
+// domain separation tags used to derive the points of G₂ of the proofs of knowledge
+const (
+	dstTau byte = iota + 1
+	dstAlpha
+	dstBeta
+	dstDelta
+)
+
+// Phase1 is the state of the powers of τ ceremony (phase 1 of the ceremony),
+// shared by all the circuits with less than 2ⁿ constraints.
+//
+// Each contribution multiplies the parameters by random (τ, α, β) and attaches a proof of
+// knowledge of (τ, α, β). Notation follows the BGM17 paper https://eprint.iacr.org/2017/1050.pdf
+type Phase1 struct {
+	Parameters struct {
+		G1 struct {
+			Tau      []curve.G1Affine // {[τ⁰]₁, [τ¹]₁, [τ²]₁, …, [τ²ⁿ⁻²]₁}
+			AlphaTau []curve.G1Affine // {α[τ⁰]₁, α[τ¹]₁, α[τ²]₁, …, α[τⁿ⁻¹]₁}
+			BetaTau  []curve.G1Affine // {β[τ⁰]₁, β[τ¹]₁, β[τ²]₁, …, β[τⁿ⁻¹]₁}
+		}
+		G2 struct {
+			Tau  []curve.G2Affine // {[τ⁰]₂, [τ¹]₂, [τ²]₂, …, [τⁿ⁻¹]₂}
+			Beta curve.G2Affine   // [β]₂
+		}
+	}
+
+	// proofs of knowledge of the last contribution
+	PublicKeys struct {
+		Tau, Alpha, Beta PublicKey
+	}
+
+	// Hash of the contribution, used as challenge by the next contributor
+	Hash [sha256.Size]byte
+}
+
+// InitPhase1 initializes the powers of τ ceremony for circuits with at most 2ᵖᵒʷᵉʳ constraints
+// the parameters are set with τ = α = β = 1
+func InitPhase1(power uint8) *Phase1 {
+	n := 1 << power
+
+	var phase1 Phase1
+	_, _, g1, g2 := curve.Generators()
+
+	phase1.Parameters.G1.Tau = make([]curve.G1Affine, 2*n-1)
+	phase1.Parameters.G1.AlphaTau = make([]curve.G1Affine, n)
+	phase1.Parameters.G1.BetaTau = make([]curve.G1Affine, n)
+	phase1.Parameters.G2.Tau = make([]curve.G2Affine, n)
+	for i := 0; i < len(phase1.Parameters.G1.Tau); i++ {
+		phase1.Parameters.G1.Tau[i] = g1
+	}
+	for i := 0; i < n; i++ {
+		phase1.Parameters.G1.AlphaTau[i] = g1
+		phase1.Parameters.G1.BetaTau[i] = g1
+		phase1.Parameters.G2.Tau[i] = g2
+	}
+	phase1.Parameters.G2.Beta = g2
+
+	phase1.Hash = phase1.computeHash()
+
+	return &phase1
+}
+
+// Contribute multiplies the parameters by random (τ, α, β) sampled from randomness,
+// and sets the proofs of knowledge of (τ, α, β).
+// If randomness is nil, crypto/rand is used.
+func (phase1 *Phase1) Contribute(randomness io.Reader) error {
+	n := len(phase1.Parameters.G2.Tau)
+
+	// sample toxic parameters
+	var tau, alpha, beta fr.Element
+	for _, x := range []*fr.Element{&tau, &alpha, &beta} {
+		if err := randomScalar(randomness, x); err != nil {
+			return err
+		}
+	}
+
+	// proofs of knowledge, binded to the previous contribution
+	var err error
+	challenge := phase1.Hash[:]
+	if phase1.PublicKeys.Tau, err = newPublicKey(tau, challenge, dstTau, randomness); err != nil {
+		return err
+	}
+	if phase1.PublicKeys.Alpha, err = newPublicKey(alpha, challenge, dstAlpha, randomness); err != nil {
+		return err
+	}
+	if phase1.PublicKeys.Beta, err = newPublicKey(beta, challenge, dstBeta, randomness); err != nil {
+		return err
+	}
+
+	// powers of τ
+	taus := powers(tau, len(phase1.Parameters.G1.Tau))
+	alphaTaus := make([]fr.Element, n)
+	betaTaus := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		alphaTaus[i].Mul(&taus[i], &alpha)
+		betaTaus[i].Mul(&taus[i], &beta)
+	}
+
+	// update the parameters
+	scaleG1(phase1.Parameters.G1.Tau, taus)
+	scaleG1(phase1.Parameters.G1.AlphaTau, alphaTaus)
+	scaleG1(phase1.Parameters.G1.BetaTau, betaTaus)
+	scaleG2(phase1.Parameters.G2.Tau, taus[:n])
+	var bBeta big.Int
+	beta.ToBigIntRegular(&bBeta)
+	phase1.Parameters.G2.Beta.ScalarMultiplication(&phase1.Parameters.G2.Beta, &bBeta)
+
+	phase1.Hash = phase1.computeHash()
+
+	return nil
+}
+
+// VerifyPhase1 checks that next is a valid contribution on top of prev
+func VerifyPhase1(prev, next *Phase1) error {
+	n := len(prev.Parameters.G2.Tau)
+	if len(next.Parameters.G2.Tau) != n ||
+		len(next.Parameters.G1.Tau) != 2*n-1 ||
+		len(next.Parameters.G1.AlphaTau) != n ||
+		len(next.Parameters.G1.BetaTau) != n {
+		return errInvalidPhase1Size
+	}
+
+	if next.Hash != next.computeHash() {
+		return errInvalidHash
+	}
+
+	// the points must be in the correct subgroups
+	if !next.isValid() {
+		return errPhase1Subgroup
+	}
+
+	// the proofs of knowledge of τ, α, β are binded to the previous contribution
+	challenge := prev.Hash[:]
+	tauR, okTau := next.PublicKeys.Tau.verify(challenge, dstTau)
+	alphaR, okAlpha := next.PublicKeys.Alpha.verify(challenge, dstAlpha)
+	betaR, okBeta := next.PublicKeys.Beta.verify(challenge, dstBeta)
+	if !(okTau && okAlpha && okBeta) {
+		return errPhase1KnowledgeProof
+	}
+
+	// [τ]₁, α[τ⁰]₁, β[τ⁰]₁ and [β]₂ are updated with the contributed τ, α and β
+	if !sameRatio(next.Parameters.G1.Tau[1], prev.Parameters.G1.Tau[1], next.PublicKeys.Tau.XR, tauR) ||
+		!sameRatio(next.Parameters.G1.AlphaTau[0], prev.Parameters.G1.AlphaTau[0], next.PublicKeys.Alpha.XR, alphaR) ||
+		!sameRatio(next.Parameters.G1.BetaTau[0], prev.Parameters.G1.BetaTau[0], next.PublicKeys.Beta.XR, betaR) ||
+		!sameRatio(next.PublicKeys.Beta.SXG, next.PublicKeys.Beta.SG, next.Parameters.G2.Beta, prev.Parameters.G2.Beta) {
+		return errPhase1Update
+	}
+
+	// [τ⁰] are the generators
+	_, _, g1, g2 := curve.Generators()
+	if !next.Parameters.G1.Tau[0].Equal(&g1) || !next.Parameters.G2.Tau[0].Equal(&g2) {
+		return errPhase1Generators
+	}
+
+	// the parameters are powers of τ
+	tau1 := next.Parameters.G1.Tau[1]
+	tau2 := next.Parameters.G2.Tau[1]
+	l1, l2 := linearCombinationG1(next.Parameters.G1.Tau)
+	if !sameRatio(l1, l2, g2, tau2) {
+		return errPhase1Powers
+	}
+	l1, l2 = linearCombinationG1(next.Parameters.G1.AlphaTau)
+	if !sameRatio(l1, l2, g2, tau2) {
+		return errPhase1Powers
+	}
+	l1, l2 = linearCombinationG1(next.Parameters.G1.BetaTau)
+	if !sameRatio(l1, l2, g2, tau2) {
+		return errPhase1Powers
+	}
+	l3, l4 := linearCombinationG2(next.Parameters.G2.Tau)
+	if !sameRatio(tau1, g1, l4, l3) {
+		return errPhase1Powers
+	}
+
+	return nil
+}
+
+// GetCurveID returns the curveID
+func (phase1 *Phase1) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+// isValid returns true if the parameters are in the correct subgroups
+func (phase1 *Phase1) isValid() bool {
+	valid := true
+	for _, points := range [][]curve.G1Affine{phase1.Parameters.G1.Tau, phase1.Parameters.G1.AlphaTau, phase1.Parameters.G1.BetaTau} {
+		for i := 0; i < len(points) && valid; i++ {
+			valid = points[i].IsInSubGroup()
+		}
+	}
+	for i := 0; i < len(phase1.Parameters.G2.Tau) && valid; i++ {
+		valid = phase1.Parameters.G2.Tau[i].IsInSubGroup()
+	}
+	return valid && phase1.Parameters.G2.Beta.IsInSubGroup()
+}
+
+// computeHash returns the sha256 hash of the serialized contribution (parameters and public keys)
+func (phase1 *Phase1) computeHash() [sha256.Size]byte {
+	h := sha256.New()
+	if _, err := phase1.writeTo(h); err != nil {
+		panic(err) // writing to a hash doesn't fail
+	}
+	var res [sha256.Size]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+// powers returns [1, x, x², …, xⁿ⁻¹]
+func powers(x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &x)
+	}
+	return res
+}
+
+// scaleG1 sets points[i] to scalars[i].points[i]
+func scaleG1(points []curve.G1Affine, scalars []fr.Element) {
+	utils.Parallelize(len(points), func(start, end int) {
+		var s big.Int
+		for i := start; i < end; i++ {
+			scalars[i].ToBigIntRegular(&s)
+			points[i].ScalarMultiplication(&points[i], &s)
+		}
+	})
+}
+
+// scaleG2 sets points[i] to scalars[i].points[i]
+func scaleG2(points []curve.G2Affine, scalars []fr.Element) {
+	utils.Parallelize(len(points), func(start, end int) {
+		var s big.Int
+		for i := start; i < end; i++ {
+			scalars[i].ToBigIntRegular(&s)
+			points[i].ScalarMultiplication(&points[i], &s)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package plonk
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gurvy/bls377/fr"
+
+	curve "github.com/consensys/gurvy/bls377"
+
+	kzg "github.com/consensys/gnark/crypto/kzg/bls377"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/backend/bls377/witness"
+)
+
+var (
+	errWrongClaimedQuotient   = errors.New("claimed quotient polynomial doesn't match the constraints")
+	errInvalidNbClaimedValues = errors.New("invalid number of claimed values in the batched opening proof")
+	errInvalidInputs          = errors.New("public inputs must be a map[string]interface{} or a *witness.Witness")
+)
+
+// Verify verifies a PLONK proof against the public inputs (a map[string]interface{} or a *witness.Witness)
+func Verify(proof *Proof, vk *VerifyingKey, inputs interface{}) error {
+
+	publicInputs, err := parsePublicWitness(vk.PublicInputs, inputs)
+	if err != nil {
+		return err
+	}
+
+	if len(proof.BatchedProof.ClaimedValues) != nbOpenedPolynomials {
+		return errInvalidNbClaimedValues
+	}
+
+	// derive γ, β, α, ζ
+	gamma := deriveRandomness("gamma", vk.digests(), publicInputs, proof.LRO[:])
+	beta := deriveRandomness("beta", &gamma)
+	alpha := deriveRandomness("alpha", &beta, &proof.Z)
+	zeta := deriveRandomness("zeta", &alpha, proof.T[:])
+
+	// Zₕ(ζ) = ζⁿ-1
+	var zetaN, zh, one fr.Element
+	one.SetOne()
+	zetaN.Exp(zeta, new(big.Int).SetUint64(vk.Size))
+	zh.Sub(&zetaN, &one)
+
+	// Lᵢ(ζ) = ωⁱ/n.(ζⁿ-1)/(ζ-ωⁱ) and PI(ζ) = -Σᵢ xᵢ.Lᵢ(ζ)
+	var l1, pi, lagrange, den, tmp, wi fr.Element
+	wi.SetOne()
+	for i := 0; i < len(publicInputs); i++ {
+		den.Sub(&zeta, &wi)
+		lagrange.Div(&zh, &den).Mul(&lagrange, &wi).Mul(&lagrange, &vk.SizeInv)
+		tmp.Mul(&lagrange, &publicInputs[i])
+		pi.Sub(&pi, &tmp)
+		wi.Mul(&wi, &vk.Generator)
+	}
+	den.Sub(&zeta, &one)
+	l1.Div(&zh, &den).Mul(&l1, &vk.SizeInv)
+
+	// claimed values of the polynomials at ζ
+	claimed := proof.BatchedProof.ClaimedValues
+	l, r, o, z := claimed[0], claimed[1], claimed[2], claimed[3]
+	ql, qr, qm, qo, qk := claimed[4], claimed[5], claimed[6], claimed[7], claimed[8]
+	s1, s2, s3 := claimed[9], claimed[10], claimed[11]
+	tLo, tMid, tHi := claimed[12], claimed[13], claimed[14]
+	zShifted := proof.ZShiftedOpening.ClaimedValue
+
+	// gate constraint
+	var gate fr.Element
+	gate.Mul(&ql, &l)
+	tmp.Mul(&qr, &r)
+	gate.Add(&gate, &tmp)
+	tmp.Mul(&qm, &l).Mul(&tmp, &r)
+	gate.Add(&gate, &tmp)
+	tmp.Mul(&qo, &o)
+	gate.Add(&gate, &tmp).Add(&gate, &qk).Add(&gate, &pi)
+
+	// permutation constraint
+	var num, perm fr.Element
+	num.Mul(&beta, &zeta).Add(&num, &l).Add(&num, &gamma)
+	tmp.Mul(&beta, &zeta).Mul(&tmp, &vk.Shifter[0]).Add(&tmp, &r).Add(&tmp, &gamma)
+	num.Mul(&num, &tmp)
+	tmp.Mul(&beta, &zeta).Mul(&tmp, &vk.Shifter[1]).Add(&tmp, &o).Add(&tmp, &gamma)
+	num.Mul(&num, &tmp).Mul(&num, &z)
+
+	den.Mul(&beta, &s1).Add(&den, &l).Add(&den, &gamma)
+	tmp.Mul(&beta, &s2).Add(&tmp, &r).Add(&tmp, &gamma)
+	den.Mul(&den, &tmp)
+	tmp.Mul(&beta, &s3).Add(&tmp, &o).Add(&tmp, &gamma)
+	den.Mul(&den, &tmp).Mul(&den, &zShifted)
+
+	perm.Sub(&num, &den).Mul(&perm, &alpha)
+
+	// z starts at 1
+	var startsAtOne fr.Element
+	startsAtOne.Sub(&z, &one).Mul(&startsAtOne, &l1).Mul(&startsAtOne, &alpha).Mul(&startsAtOne, &alpha)
+
+	// t(ζ) = t_lo(ζ) + ζⁿ⁺².t_mid(ζ) + ζ²⁽ⁿ⁺²⁾.t_hi(ζ)
+	var zetaNPlusTwo, t fr.Element
+	zetaNPlusTwo.Square(&zeta).Mul(&zetaNPlusTwo, &zetaN)
+	t.Mul(&tHi, &zetaNPlusTwo).Add(&t, &tMid).Mul(&t, &zetaNPlusTwo).Add(&t, &tLo)
+
+	// check that the constraints hold at ζ
+	var lhs, rhs fr.Element
+	lhs.Add(&gate, &perm).Add(&lhs, &startsAtOne)
+	rhs.Mul(&t, &zh)
+	if !lhs.Equal(&rhs) {
+		return errWrongClaimedQuotient
+	}
+
+	// check the openings at ζ and ζω
+	foldedProof, foldedDigest, err := kzg.FoldProof(openedDigests(proof, vk), &proof.BatchedProof, &zeta)
+	if err != nil {
+		return err
+	}
+	var zetaShifted fr.Element
+	zetaShifted.Mul(&zeta, &vk.Generator)
+
+	return kzg.BatchVerifyMultiPoints(
+		[]kzg.Digest{foldedDigest, proof.Z},
+		[]kzg.OpeningProof{foldedProof, proof.ZShiftedOpening},
+		[]fr.Element{zeta, zetaShifted},
+		vk.SRS,
+	)
+}
+
+// ParsePublicInput return the ordered public input values
+// in Montgomery form
+func ParsePublicInput(expectedNames []string, input map[string]interface{}) ([]fr.Element, error) {
+	toReturn := make([]fr.Element, len(expectedNames))
+
+	for i := 0; i < len(expectedNames); i++ {
+		if expectedNames[i] == backend.OneWire {
+			// ONE_WIRE is a reserved name, it should not be set by the user
+			toReturn[i].SetOne()
+		} else {
+			if val, ok := input[expectedNames[i]]; ok {
+				toReturn[i].SetInterface(val)
+			} else {
+				return nil, backend.ErrInputNotSet
+			}
+		}
+	}
+
+	return toReturn, nil
+}
+
+// parsePublicWitness returns the ordered public input values in Montgomery form, as ParsePublicInput,
+// from a map[string]interface{} or a *witness.Witness (the secret inputs of which are ignored)
+func parsePublicWitness(expectedNames []string, inputs interface{}) ([]fr.Element, error) {
+	switch _inputs := inputs.(type) {
+	case map[string]interface{}:
+		return ParsePublicInput(expectedNames, _inputs)
+	case *witness.Witness:
+		return _inputs.PublicInputs(expectedNames)
+	default:
+		return nil, fmt.Errorf("%T: %w", inputs, errInvalidInputs)
+	}
+}
+
+// digests returns the commitments to the selectors and to the permutation
+func (vk *VerifyingKey) digests() []kzg.Digest {
+	return []kzg.Digest{vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk, vk.S[0], vk.S[1], vk.S[2]}
+}
+
+// openedDigests returns the commitments to the polynomials opened at ζ in proof.BatchedProof
+func openedDigests(proof *Proof, vk *VerifyingKey) []kzg.Digest {
+	return []kzg.Digest{
+		proof.LRO[0], proof.LRO[1], proof.LRO[2],
+		proof.Z,
+		vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk,
+		vk.S[0], vk.S[1], vk.S[2],
+		proof.T[0], proof.T[1], proof.T[2],
+	}
+}
+
+// deriveRandomness derives a challenge from a label and the elements of the transcript
+// (Fiat Shamir). elements are *fr.Element, []fr.Element, *curve.G1Affine or []curve.G1Affine
+func deriveRandomness(label string, elements ...interface{}) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(label))
+	for _, e := range elements {
+		switch v := e.(type) {
+		case *fr.Element:
+			b := v.Bytes()
+			h.Write(b[:])
+		case []fr.Element:
+			for i := 0; i < len(v); i++ {
+				b := v[i].Bytes()
+				h.Write(b[:])
+			}
+		case *curve.G1Affine:
+			h.Write(v.Marshal())
+		case []curve.G1Affine:
+			for i := 0; i < len(v); i++ {
+				h.Write(v[i].Marshal())
+			}
+		default:
+			panic("deriveRandomness: unsupported type")
+		}
+	}
+
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
@@ -0,0 +1,757 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package groth16
+
+import (
+	"github.com/consensys/gurvy/bls381/fr"
+
+	curve "github.com/consensys/gurvy/bls381"
+
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+)
+
+var (
+	errAggregateSize        = errors.New("the number of aggregated proofs must be a power of 2, greater than 1")
+	errAggregationSRSSize   = errors.New("the aggregation SRS is too small for this number of proofs")
+	errAggregateCheckFailed = errors.New("aggregate proof doesn't verify")
+)
+
+// AggregationSRS is the structured reference string used to aggregate proofs.
+//
+// It holds the powers of two independent secrets a and b in both groups. In production,
+// it should be derived from two independent powers of τ ceremonies.
+type AggregationSRS struct {
+	// [aⁱ]1, [bⁱ]1, i < 2n
+	G1 struct {
+		A, B []curve.G1Affine
+	}
+
+	// [aⁱ]2, [bⁱ]2, i < n
+	G2 struct {
+		A, B []curve.G2Affine
+	}
+}
+
+// AggregateProof is a logarithmic size proof that n Groth16 proofs verify against
+// the same VerifyingKey (SnarkPack, https://eprint.iacr.org/2021/529.pdf).
+//
+// Notations: Aᵢ, Bᵢ, Cᵢ are the points of the aggregated proofs, r is a random challenge,
+// the commitment keys are v = ([aⁱ]2, [bⁱ]2) and w = ([aⁿ⁺ⁱ]1, [bⁿ⁺ⁱ]1), i < n.
+type AggregateProof struct {
+	// commitments to the proofs, under both keys
+	// ComAB = ∏ e(Aᵢ, vᵢ).e(wᵢ, Bᵢ), ComC = ∏ e(Cᵢ, vᵢ)
+	ComAB, ComC [2]curve.GT
+
+	// ZAB = ∏ e(Aᵢ, Bᵢ)^(rⁱ), ZC = Σ rⁱ.Cᵢ
+	ZAB curve.GT
+	ZC  curve.G1Affine
+
+	// cross terms of the inner product arguments, one entry per halving round
+	Rounds []AggregationRound
+
+	// final values of the inner product arguments: folded proof points and commitment keys
+	A, C curve.G1Affine
+	B    curve.G2Affine
+	V    [2]curve.G2Affine
+	W    [2]curve.G1Affine
+
+	// KZG openings proving the folded commitment keys are well formed
+	OpeningV [2]curve.G2Affine
+	OpeningW [2]curve.G1Affine
+}
+
+// AggregationRound holds the cross terms sent by the prover in a round of the
+// inner product arguments, when the vectors are split in halves L and R
+type AggregationRound struct {
+	// commitments to (A_R, B_L) and (A_L, B_R) and their pairing products
+	ComABL, ComABR [2]curve.GT
+	ZABL, ZABR     curve.GT
+
+	// commitments to C_R and C_L and their inner products with the folded scalars
+	ComCL, ComCR [2]curve.GT
+	ZCL, ZCR     curve.G1Affine
+}
+
+// AggregationSetup samples random secrets and fills an AggregationSRS that allows the aggregation
+// of up to maxProofs proofs. The secrets are not kept, but the SRS is only as trustworthy as
+// the machine that generated it.
+func AggregationSetup(maxProofs int, srs *AggregationSRS) error {
+	if maxProofs < 2 || bits.OnesCount(uint(maxProofs)) != 1 {
+		return errAggregateSize
+	}
+
+	var a, b fr.Element
+	if _, err := a.SetRandom(); err != nil {
+		return err
+	}
+	if _, err := b.SetRandom(); err != nil {
+		return err
+	}
+
+	_, _, g1, g2 := curve.Generators()
+	srs.G1.A = powersG1(g1, a, 2*maxProofs)
+	srs.G1.B = powersG1(g1, b, 2*maxProofs)
+	srs.G2.A = powersG2(g2, a, maxProofs)
+	srs.G2.B = powersG2(g2, b, maxProofs)
+
+	return nil
+}
+
+// Aggregate aggregates proofs verifying against vk with given public inputs (each a
+// map[string]interface{} or a *witness.Witness)
+// len(proofs) must be a power of 2
+func Aggregate(proofs []*Proof, vk *VerifyingKey, srs *AggregationSRS, inputs []interface{}) (*AggregateProof, error) {
+	n := len(proofs)
+	if n < 2 || bits.OnesCount(uint(n)) != 1 {
+		return nil, errAggregateSize
+	}
+	if len(inputs) != n {
+		return nil, errBatchSize
+	}
+	if len(srs.G1.A) < 2*n || len(srs.G1.B) < 2*n || len(srs.G2.A) < n || len(srs.G2.B) < n {
+		return nil, errAggregationSRSSize
+	}
+	kInputs, err := parseAggregateInputs(vk, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	A := make([]curve.G1Affine, n)
+	B := make([]curve.G2Affine, n)
+	C := make([]curve.G1Affine, n)
+	for i := 0; i < n; i++ {
+		A[i] = proofs[i].Ar
+		B[i] = proofs[i].Bs
+		C[i] = proofs[i].Krs
+	}
+	var v [2][]curve.G2Affine
+	var w [2][]curve.G1Affine
+	v[0] = append([]curve.G2Affine{}, srs.G2.A[:n]...)
+	v[1] = append([]curve.G2Affine{}, srs.G2.B[:n]...)
+	w[0] = append([]curve.G1Affine{}, srs.G1.A[n:2*n]...)
+	w[1] = append([]curve.G1Affine{}, srs.G1.B[n:2*n]...)
+
+	// commit to the proofs
+	proof := &AggregateProof{Rounds: make([]AggregationRound, 0, bits.Len(uint(n))-1)}
+	for k := 0; k < 2; k++ {
+		if proof.ComAB[k], err = commitAB(A, B, v[k], w[k]); err != nil {
+			return nil, err
+		}
+		if proof.ComC[k], err = pairingProduct(C, v[k]); err != nil {
+			return nil, err
+		}
+	}
+
+	t := newAggregationTranscript(n, kInputs)
+	t.appendGT(&proof.ComAB[0], &proof.ComAB[1])
+	t.appendGT(&proof.ComC[0], &proof.ComC[1])
+	r := t.challenge()
+
+	// the inner product arguments are run on Aᵢ' = rⁱ.Aᵢ, Cᵢ' = rⁱ.Cᵢ with the key vᵢ' = r⁻ⁱ.vᵢ,
+	// so that the commitments are unchanged: e(Aᵢ', vᵢ') = e(Aᵢ, vᵢ)
+	var rInv fr.Element
+	rInv.Inverse(&r)
+	rPowers := powers(r, n)
+	rInvPowers := powers(rInv, n)
+	var e big.Int
+	for i := 0; i < n; i++ {
+		rPowers[i].ToBigIntRegular(&e)
+		A[i].ScalarMultiplication(&A[i], &e)
+		C[i].ScalarMultiplication(&C[i], &e)
+		rInvPowers[i].ToBigIntRegular(&e)
+		v[0][i].ScalarMultiplication(&v[0][i], &e)
+		v[1][i].ScalarMultiplication(&v[1][i], &e)
+	}
+
+	// ZC is the inner product of C' with a vector of ones, folded along with the other vectors
+	s := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		s[i].SetOne()
+	}
+	if proof.ZAB, err = pairingProduct(A, B); err != nil {
+		return nil, err
+	}
+	proof.ZC = innerProduct(C, s)
+
+	t.appendGT(&proof.ZAB)
+	t.appendG1(&proof.ZC)
+
+	// inner product arguments (GIPA): at each round, the vectors are split in halves, the prover
+	// sends the cross terms and folds the vectors with a challenge x
+	// A' = A_L + x.A_R, C' = C_L + x.C_R, B' = B_L + x⁻¹.B_R, v' = v_L + x⁻¹.v_R, w' = w_L + x.w_R
+	challenges := make([]fr.Element, 0, cap(proof.Rounds))
+	for m := n; m > 1; m /= 2 {
+		h := m / 2
+		var round AggregationRound
+		for k := 0; k < 2; k++ {
+			if round.ComABL[k], err = commitAB(A[h:m], B[:h], v[k][:h], w[k][h:m]); err != nil {
+				return nil, err
+			}
+			if round.ComABR[k], err = commitAB(A[:h], B[h:m], v[k][h:m], w[k][:h]); err != nil {
+				return nil, err
+			}
+			if round.ComCL[k], err = pairingProduct(C[h:m], v[k][:h]); err != nil {
+				return nil, err
+			}
+			if round.ComCR[k], err = pairingProduct(C[:h], v[k][h:m]); err != nil {
+				return nil, err
+			}
+		}
+		if round.ZABL, err = pairingProduct(A[h:m], B[:h]); err != nil {
+			return nil, err
+		}
+		if round.ZABR, err = pairingProduct(A[:h], B[h:m]); err != nil {
+			return nil, err
+		}
+		round.ZCL = innerProduct(C[h:m], s[:h])
+		round.ZCR = innerProduct(C[:h], s[h:m])
+
+		t.appendRound(&round)
+		x := t.challenge()
+		var xInv fr.Element
+		xInv.Inverse(&x)
+
+		foldG1(A[:m], x)
+		foldG1(C[:m], x)
+		foldG2(B[:m], xInv)
+		foldG2(v[0][:m], xInv)
+		foldG2(v[1][:m], xInv)
+		foldG1(w[0][:m], x)
+		foldG1(w[1][:m], x)
+		var tmp fr.Element
+		for i := 0; i < h; i++ {
+			tmp.Mul(&s[h+i], &xInv)
+			s[i].Add(&s[i], &tmp)
+		}
+
+		proof.Rounds = append(proof.Rounds, round)
+		challenges = append(challenges, x)
+	}
+
+	proof.A, proof.B, proof.C = A[0], B[0], C[0]
+	proof.V = [2]curve.G2Affine{v[0][0], v[1][0]}
+	proof.W = [2]curve.G1Affine{w[0][0], w[1][0]}
+
+	// KZG openings of the folded keys at a random point z:
+	// v = [fv(a)]2, [fv(b)]2 and w = [fw(a)]1, [fw(b)]1
+	t.appendFinal(proof)
+	z := t.challenge()
+
+	fv, fw := foldingPolynomials(n, rInv, challenges)
+	qv := divideByLinear(fv, z)
+	qw := divideByLinear(fw, z)
+	proof.OpeningV[0].MultiExp(srs.G2.A[:len(qv)], qv)
+	proof.OpeningV[1].MultiExp(srs.G2.B[:len(qv)], qv)
+	proof.OpeningW[0].MultiExp(srs.G1.A[:len(qw)], qw)
+	proof.OpeningW[1].MultiExp(srs.G1.B[:len(qw)], qw)
+
+	return proof, nil
+}
+
+// VerifyAggregate verifies an aggregate proof against vk and the public inputs of the aggregated proofs
+func VerifyAggregate(proof *AggregateProof, vk *VerifyingKey, srs *AggregationSRS, inputs []interface{}) error {
+	n := len(inputs)
+	if n < 2 || bits.OnesCount(uint(n)) != 1 || len(proof.Rounds) != bits.Len(uint(n))-1 {
+		return errAggregateSize
+	}
+	if len(srs.G1.A) < 2 || len(srs.G1.B) < 2 || len(srs.G2.A) < 2 || len(srs.G2.B) < 2 {
+		return errAggregationSRSSize
+	}
+	if !proof.isValid() {
+		return errCorrectSubgroupCheckFailed
+	}
+	kInputs, err := parseAggregateInputs(vk, inputs)
+	if err != nil {
+		return err
+	}
+
+	// replay the transcript
+	t := newAggregationTranscript(n, kInputs)
+	t.appendGT(&proof.ComAB[0], &proof.ComAB[1])
+	t.appendGT(&proof.ComC[0], &proof.ComC[1])
+	r := t.challenge()
+	t.appendGT(&proof.ZAB)
+	t.appendG1(&proof.ZC)
+	challenges := make([]fr.Element, len(proof.Rounds))
+	for i := 0; i < len(proof.Rounds); i++ {
+		t.appendRound(&proof.Rounds[i])
+		challenges[i] = t.challenge()
+	}
+	t.appendFinal(proof)
+	z := t.challenge()
+
+	// Groth16 equation, aggregated with the powers of r
+	// ZAB . e(Σ rⁱ.Sᵢ, -[γ]2) . e(ZC, -[δ]2) == e(α, β)^(Σ rⁱ)
+	// where Sᵢ = Σ xᵢⱼ.[Kvkⱼ(t)]1
+	rPowers := powers(r, n)
+	var rSum fr.Element
+	kScalars := make([]fr.Element, len(vk.G1.K))
+	var tmp fr.Element
+	for i := 0; i < n; i++ {
+		rSum.Add(&rSum, &rPowers[i])
+		for j := 0; j < len(kScalars); j++ {
+			tmp.Mul(&rPowers[i], &kInputs[i][j])
+			kScalars[j].Add(&kScalars[j], &tmp)
+		}
+	}
+	for j := 0; j < len(kScalars); j++ {
+		kScalars[j].FromMont()
+	}
+	var kSum curve.G1Affine
+	kSum.MultiExp(vk.G1.K, kScalars)
+
+	right, err := pairingProduct([]curve.G1Affine{kSum, proof.ZC}, []curve.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg})
+	if err != nil {
+		return err
+	}
+	right.Mul(&right, &proof.ZAB)
+	var e big.Int
+	var left curve.GT
+	rSum.ToBigIntRegular(&e)
+	left.Exp(&vk.E, e)
+	if !left.Equal(&right) {
+		return errAggregateCheckFailed
+	}
+
+	// fold the commitments and inner products with the challenges: T' = T_L^x . T . T_R^(x⁻¹)
+	comAB, comC, zAB, zC := proof.ComAB, proof.ComC, proof.ZAB, proof.ZC
+	var zCJac curve.G1Jac
+	zCJac.FromAffine(&zC)
+	sFinal := fr.One()
+	for i := 0; i < len(proof.Rounds); i++ {
+		round := &proof.Rounds[i]
+		var x, xInv big.Int
+		var _xInv fr.Element
+		_xInv.Inverse(&challenges[i])
+		challenges[i].ToBigIntRegular(&x)
+		_xInv.ToBigIntRegular(&xInv)
+
+		for k := 0; k < 2; k++ {
+			foldGT(&comAB[k], &round.ComABL[k], &round.ComABR[k], &x, &xInv)
+			foldGT(&comC[k], &round.ComCL[k], &round.ComCR[k], &x, &xInv)
+		}
+		foldGT(&zAB, &round.ZABL, &round.ZABR, &x, &xInv)
+
+		var p curve.G1Affine
+		p.ScalarMultiplication(&round.ZCL, &x)
+		zCJac.AddMixed(&p)
+		p.ScalarMultiplication(&round.ZCR, &xInv)
+		zCJac.AddMixed(&p)
+
+		var one fr.Element
+		one.SetOne()
+		_xInv.Add(&_xInv, &one)
+		sFinal.Mul(&sFinal, &_xInv)
+	}
+	zC.FromJacobian(&zCJac)
+
+	// final checks of the inner product arguments
+	// ZAB == e(A, B), ComAB == e(A, v).e(w, B), ComC == e(C, v), ZC == s.C
+	if check, err := pairingProduct([]curve.G1Affine{proof.A}, []curve.G2Affine{proof.B}); err != nil {
+		return err
+	} else if !check.Equal(&zAB) {
+		return errAggregateCheckFailed
+	}
+	for k := 0; k < 2; k++ {
+		check, err := pairingProduct([]curve.G1Affine{proof.A, proof.W[k]}, []curve.G2Affine{proof.V[k], proof.B})
+		if err != nil {
+			return err
+		}
+		if !check.Equal(&comAB[k]) {
+			return errAggregateCheckFailed
+		}
+		if check, err = pairingProduct([]curve.G1Affine{proof.C}, []curve.G2Affine{proof.V[k]}); err != nil {
+			return err
+		}
+		if !check.Equal(&comC[k]) {
+			return errAggregateCheckFailed
+		}
+	}
+	var sC curve.G1Affine
+	sFinal.ToBigIntRegular(&e)
+	sC.ScalarMultiplication(&proof.C, &e)
+	if !sC.Equal(&zC) {
+		return errAggregateCheckFailed
+	}
+
+	// the folded keys are the commitments to the folding polynomials
+	var rInv fr.Element
+	rInv.Inverse(&r)
+	fvz, fwz := evalFoldingPolynomials(n, rInv, challenges, z)
+	g1 := []curve.G1Affine{srs.G1.A[0], srs.G1.A[1], srs.G1.B[1]}
+	g2 := []curve.G2Affine{srs.G2.A[0], srs.G2.A[1], srs.G2.B[1]}
+	for k := 0; k < 2; k++ {
+		// e(g, v - [fv(z)]2) == e([a - z]1, opening)
+		ok, err := kzgCheckG2(g1[0], g1[1+k], g2[0], proof.V[k], proof.OpeningV[k], z, fvz)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errAggregateCheckFailed
+		}
+		// e(w - [fw(z)]1, h) == e(opening, [a - z]2)
+		if ok, err = kzgCheckG1(g2[0], g2[1+k], g1[0], proof.W[k], proof.OpeningW[k], z, fwz); err != nil {
+			return err
+		}
+		if !ok {
+			return errAggregateCheckFailed
+		}
+	}
+
+	return nil
+}
+
+// isValid ensures the points of the aggregate proof are in the correct subgroup
+func (proof *AggregateProof) isValid() bool {
+	g1 := []*curve.G1Affine{&proof.ZC, &proof.A, &proof.C, &proof.W[0], &proof.W[1], &proof.OpeningW[0], &proof.OpeningW[1]}
+	for i := 0; i < len(proof.Rounds); i++ {
+		g1 = append(g1, &proof.Rounds[i].ZCL, &proof.Rounds[i].ZCR)
+	}
+	for _, p := range g1 {
+		if !p.IsInSubGroup() {
+			return false
+		}
+	}
+	for _, p := range []*curve.G2Affine{&proof.B, &proof.V[0], &proof.V[1], &proof.OpeningV[0], &proof.OpeningV[1]} {
+		if !p.IsInSubGroup() {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAggregateInputs returns the public inputs of the aggregated proofs, in Montgomery form
+func parseAggregateInputs(vk *VerifyingKey, inputs []interface{}) ([][]fr.Element, error) {
+	kInputs := make([][]fr.Element, len(inputs))
+	for i := 0; i < len(inputs); i++ {
+		var err error
+		if kInputs[i], err = parsePublicWitness(vk.PublicInputs, inputs[i]); err != nil {
+			return nil, err
+		}
+		for j := 0; j < len(kInputs[i]); j++ {
+			kInputs[i][j].ToMont()
+		}
+	}
+	return kInputs, nil
+}
+
+// foldingPolynomials returns the coefficients of the polynomials fv and fw such that
+// the folded keys are v = [fv(a)]2 and w = [fw(a)]1. With hⱼ = n/2ʲ⁺¹, the half size at round j,
+//
+//	fv(X) = ∏ (1 + xⱼ⁻¹.(X/r)^hⱼ)
+//	fw(X) = Xⁿ.∏ (1 + xⱼ.X^hⱼ)
+//
+// the coefficients are returned in regular form
+func foldingPolynomials(n int, rInv fr.Element, challenges []fr.Element) (fv, fw []fr.Element) {
+	fv = powers(rInv, n)
+	fw = make([]fr.Element, 2*n)
+	for i := 0; i < n; i++ {
+		fw[n+i].SetOne()
+	}
+	for j := 0; j < len(challenges); j++ {
+		h := n >> (j + 1)
+		var xInv fr.Element
+		xInv.Inverse(&challenges[j])
+		for i := 0; i < n; i++ {
+			if i&h != 0 {
+				fv[i].Mul(&fv[i], &xInv)
+				fw[n+i].Mul(&fw[n+i], &challenges[j])
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		fv[i].FromMont()
+		fw[n+i].FromMont()
+	}
+	return
+}
+
+// evalFoldingPolynomials returns fv(z) and fw(z), see foldingPolynomials
+func evalFoldingPolynomials(n int, rInv fr.Element, challenges []fr.Element, z fr.Element) (fvz, fwz fr.Element) {
+	var zr, one fr.Element
+	zr.Mul(&z, &rInv)
+	one.SetOne()
+	fvz.SetOne()
+	fwz.Exp(z, new(big.Int).SetUint64(uint64(n)))
+	for j := 0; j < len(challenges); j++ {
+		var h big.Int
+		h.SetUint64(uint64(n >> (j + 1)))
+
+		var term, xInv fr.Element
+		xInv.Inverse(&challenges[j])
+		term.Exp(zr, &h).Mul(&term, &xInv).Add(&term, &one)
+		fvz.Mul(&fvz, &term)
+
+		term.Exp(z, &h).Mul(&term, &challenges[j]).Add(&term, &one)
+		fwz.Mul(&fwz, &term)
+	}
+	return
+}
+
+// divideByLinear returns the quotient of p by (X - z), p and the result are in regular form
+func divideByLinear(p []fr.Element, z fr.Element) []fr.Element {
+	q := make([]fr.Element, len(p)-1)
+	var c fr.Element
+	for i := len(p) - 1; i > 0; i-- {
+		pi := p[i]
+		pi.ToMont()
+		c.Mul(&c, &z).Add(&c, &pi)
+		q[i-1] = c
+		q[i-1].FromMont()
+	}
+	return q
+}
+
+// kzgCheckG2 checks that commitment = [f(s)]2 given f(z) = eval, with
+// e(g1, commitment - eval.g2) == e(s.g1 - z.g1, opening)
+func kzgCheckG2(g1, sG1 curve.G1Affine, g2, commitment, opening curve.G2Affine, z, eval fr.Element) (bool, error) {
+	var b big.Int
+	var leftJac curve.G2Jac
+	var rightJac, sG1Jac curve.G1Jac
+
+	// commitment - eval.g2
+	eval.ToBigIntRegular(&b)
+	leftJac.FromAffine(&g2)
+	leftJac.ScalarMultiplication(&leftJac, &b)
+	leftJac.Neg(&leftJac)
+	leftJac.AddMixed(&commitment)
+
+	// z.g1 - s.g1
+	z.ToBigIntRegular(&b)
+	rightJac.FromAffine(&g1)
+	rightJac.ScalarMultiplication(&rightJac, &b)
+	sG1Jac.FromAffine(&sG1)
+	rightJac.SubAssign(&sG1Jac)
+
+	var left curve.G2Affine
+	var right curve.G1Affine
+	left.FromJacobian(&leftJac)
+	right.FromJacobian(&rightJac)
+	return pairingCheck([]curve.G1Affine{g1, right}, []curve.G2Affine{left, opening})
+}
+
+// kzgCheckG1 checks that commitment = [f(s)]1 given f(z) = eval, with
+// e(commitment - eval.g1, g2) == e(opening, s.g2 - z.g2)
+func kzgCheckG1(g2, sG2 curve.G2Affine, g1, commitment, opening curve.G1Affine, z, eval fr.Element) (bool, error) {
+	var b big.Int
+	var leftJac curve.G1Jac
+	var rightJac, sG2Jac curve.G2Jac
+
+	// commitment - eval.g1
+	eval.ToBigIntRegular(&b)
+	leftJac.FromAffine(&g1)
+	leftJac.ScalarMultiplication(&leftJac, &b)
+	leftJac.Neg(&leftJac)
+	leftJac.AddMixed(&commitment)
+
+	// z.g2 - s.g2
+	z.ToBigIntRegular(&b)
+	rightJac.FromAffine(&g2)
+	rightJac.ScalarMultiplication(&rightJac, &b)
+	sG2Jac.FromAffine(&sG2)
+	rightJac.SubAssign(&sG2Jac)
+
+	var left curve.G1Affine
+	var right curve.G2Affine
+	left.FromJacobian(&leftJac)
+	right.FromJacobian(&rightJac)
+	return pairingCheck([]curve.G1Affine{left, opening}, []curve.G2Affine{g2, right})
+}
+
+// commitAB returns ∏ e(A[i], v[i]).e(w[i], B[i])
+func commitAB(A []curve.G1Affine, B []curve.G2Affine, v []curve.G2Affine, w []curve.G1Affine) (curve.GT, error) {
+	P := make([]curve.G1Affine, 0, 2*len(A))
+	Q := make([]curve.G2Affine, 0, 2*len(A))
+	P = append(append(P, A...), w...)
+	Q = append(append(Q, v...), B...)
+	return pairingProduct(P, Q)
+}
+
+// pairingProduct returns ∏ e(P[i], Q[i])
+func pairingProduct(P []curve.G1Affine, Q []curve.G2Affine) (curve.GT, error) {
+	ml, err := curve.MillerLoop(P, Q)
+	if err != nil {
+		return curve.GT{}, err
+	}
+	return curve.FinalExponentiation(&ml), nil
+}
+
+// pairingCheck returns true if ∏ e(P[i], Q[i]) == 1
+func pairingCheck(P []curve.G1Affine, Q []curve.G2Affine) (bool, error) {
+	res, err := pairingProduct(P, Q)
+	if err != nil {
+		return false, err
+	}
+	var one curve.GT
+	one.SetOne()
+	return res.Equal(&one), nil
+}
+
+// innerProduct returns Σ s[i].P[i], s in Montgomery form
+func innerProduct(P []curve.G1Affine, s []fr.Element) curve.G1Affine {
+	scalars := make([]fr.Element, len(s))
+	for i := 0; i < len(s); i++ {
+		scalars[i] = s[i]
+		scalars[i].FromMont()
+	}
+	var res curve.G1Affine
+	res.MultiExp(P, scalars)
+	return res
+}
+
+// foldG1 sets P[i] = P[i] + x.P[i+len(P)/2] for the first half of P
+func foldG1(P []curve.G1Affine, x fr.Element) {
+	var b big.Int
+	x.ToBigIntRegular(&b)
+	h := len(P) / 2
+	for i := 0; i < h; i++ {
+		var p curve.G1Jac
+		p.FromAffine(&P[h+i])
+		p.ScalarMultiplication(&p, &b)
+		p.AddMixed(&P[i])
+		P[i].FromJacobian(&p)
+	}
+}
+
+// foldG2 sets Q[i] = Q[i] + x.Q[i+len(Q)/2] for the first half of Q
+func foldG2(Q []curve.G2Affine, x fr.Element) {
+	var b big.Int
+	x.ToBigIntRegular(&b)
+	h := len(Q) / 2
+	for i := 0; i < h; i++ {
+		var q curve.G2Jac
+		q.FromAffine(&Q[h+i])
+		q.ScalarMultiplication(&q, &b)
+		q.AddMixed(&Q[i])
+		Q[i].FromJacobian(&q)
+	}
+}
+
+// foldGT sets z = l^x . z . r^xInv
+func foldGT(z, l, r *curve.GT, x, xInv *big.Int) {
+	var tmp curve.GT
+	tmp.Exp(l, *x)
+	z.Mul(z, &tmp)
+	tmp.Exp(r, *xInv)
+	z.Mul(z, &tmp)
+}
+
+// powers returns [1, x, x², ..., xⁿ⁻¹] in Montgomery form
+func powers(x fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &x)
+	}
+	return res
+}
+
+// powersG1 returns [xⁱ]g, i < n
+func powersG1(g curve.G1Affine, x fr.Element, n int) []curve.G1Affine {
+	scalars := powers(x, n)
+	res := make([]curve.G1Affine, n)
+	var b big.Int
+	for i := 0; i < n; i++ {
+		scalars[i].ToBigIntRegular(&b)
+		res[i].ScalarMultiplication(&g, &b)
+	}
+	return res
+}
+
+// powersG2 returns [xⁱ]g, i < n
+func powersG2(g curve.G2Affine, x fr.Element, n int) []curve.G2Affine {
+	scalars := powers(x, n)
+	res := make([]curve.G2Affine, n)
+	var b big.Int
+	for i := 0; i < n; i++ {
+		scalars[i].ToBigIntRegular(&b)
+		res[i].ScalarMultiplication(&g, &b)
+	}
+	return res
+}
+
+// aggregationTranscript derives the Fiat-Shamir challenges of the aggregation.
+// Each challenge is the hash of the previous one and of the messages sent since.
+type aggregationTranscript struct {
+	h hash.Hash
+}
+
+func newAggregationTranscript(n int, inputs [][]fr.Element) *aggregationTranscript {
+	t := &aggregationTranscript{h: sha256.New()}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	t.h.Write(buf[:])
+	for i := 0; i < len(inputs); i++ {
+		for j := 0; j < len(inputs[i]); j++ {
+			b := inputs[i][j].Bytes()
+			t.h.Write(b[:])
+		}
+	}
+	return t
+}
+
+func (t *aggregationTranscript) appendGT(values ...*curve.GT) {
+	for _, v := range values {
+		b := v.Bytes()
+		t.h.Write(b[:])
+	}
+}
+
+func (t *aggregationTranscript) appendG1(points ...*curve.G1Affine) {
+	for _, p := range points {
+		b := p.Bytes()
+		t.h.Write(b[:])
+	}
+}
+
+func (t *aggregationTranscript) appendG2(points ...*curve.G2Affine) {
+	for _, p := range points {
+		b := p.Bytes()
+		t.h.Write(b[:])
+	}
+}
+
+func (t *aggregationTranscript) appendRound(round *AggregationRound) {
+	t.appendGT(&round.ComABL[0], &round.ComABL[1])
+	t.appendGT(&round.ComABR[0], &round.ComABR[1])
+	t.appendGT(&round.ZABL, &round.ZABR)
+	t.appendGT(&round.ComCL[0], &round.ComCL[1])
+	t.appendGT(&round.ComCR[0], &round.ComCR[1])
+	t.appendG1(&round.ZCL, &round.ZCR)
+}
+
+func (t *aggregationTranscript) appendFinal(proof *AggregateProof) {
+	t.appendG1(&proof.A, &proof.C, &proof.W[0], &proof.W[1])
+	t.appendG2(&proof.B, &proof.V[0], &proof.V[1])
+}
+
+// challenge returns a non zero challenge, in Montgomery form
+func (t *aggregationTranscript) challenge() fr.Element {
+	var res fr.Element
+	for res.IsZero() {
+		digest := t.h.Sum(nil)
+		t.h.Reset()
+		t.h.Write(digest)
+		res.SetBytes(digest)
+	}
+	return res
+}
@@ -20,10 +20,18 @@ import (
 	curve "github.com/consensys/gurvy/bls381"
 
 	"encoding/binary"
+	"errors"
 	"github.com/fxamacker/cbor/v2"
 	"io"
 )
 
+// vkFormatV1 is written first by VerifyingKey.WriteTo, followed by the version 0 encoding and
+// [α]1 | [β]2. The version 0 encoding starts with the length of the public input names, whose
+// top bit is never set, so that the two versions can't be mistaken for one another.
+const vkFormatV1 = uint64(1)<<63 | 1
+
+var errVKFormat = errors.New("unknown verifying key encoding version")
+
 // WriteTo writes binary encoding of the Proof elements to writer
 // points are stored in compressed form Ar | Krs | Bs
 // use WriteRawTo(...) to encode the proof without point compression
@@ -81,6 +89,10 @@ func (proof *Proof) ReadFrom(r io.Reader) (n int64, err error) {
 // WriteTo writes binary encoding of the key elements to writer
 // points are compressed
 // use WriteRawTo(...) to encode the key without point compression
+//
+// the encoding is versioned: version 1 is vkFormatV1 | version 0 | [α]1 | [β]2, where
+// version 0 (the encoding of the keys without [α]1 and [β]2) is
+// len(public inputs) | public inputs | e(α, β) | -[γ]2 | -[δ]2 | [Kvk]1
 func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 	return vk.writeTo(w, false)
 }
@@ -95,6 +107,12 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (n int64, err error) {
 	var written int
 
+	err = binary.Write(w, binary.BigEndian, vkFormatV1)
+	if err != nil {
+		return
+	}
+	n += 8
+
 	// encode public input names
 	var pBytes []byte
 	pBytes, err = cbor.Marshal(vk.PublicInputs)
@@ -141,11 +159,26 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (n int64, err error) {
 
 	err = enc.Encode(vk.G1.K)
 	n += enc.BytesWritten()
+	if err != nil {
+		return
+	}
+
+	// version 1
+	err = enc.Encode(&vk.G1.Alpha)
+	n += enc.BytesWritten()
+	if err != nil {
+		return
+	}
+
+	err = enc.Encode(&vk.G2.Beta)
+	n += enc.BytesWritten()
 	return
 }
 
 // ReadFrom attempts to decode a VerifyingKey from reader
 // VerifyingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
+// keys encoded without [α]1 and [β]2 (version 0, see WriteTo) are decoded with [α]1 and [β]2 set
+// to the infinity: they can verify proofs, but not be exported as contracts
 // note that we don't check that the points are on the curve or in the correct subgroup at this point
 // TODO while Proof points correctness is checkd in the Verifier, here may be a good place to check key
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (n int64, err error) {
@@ -160,6 +193,21 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (n int64, err error) {
 	}
 	lPublicInputs := binary.BigEndian.Uint64(buf[:8])
 
+	// version 0 starts with the length of the public input names
+	v1 := lPublicInputs == vkFormatV1
+	if v1 {
+		read, err = io.ReadFull(r, buf[:8])
+		n += int64(read)
+		if err != nil {
+			return
+		}
+		lPublicInputs = binary.BigEndian.Uint64(buf[:8])
+	}
+	if lPublicInputs>>63 != 0 {
+		err = errVKFormat
+		return
+	}
+
 	bPublicInputs := make([]byte, lPublicInputs)
 	read, err = io.ReadFull(r, bPublicInputs)
 	n += int64(read)
@@ -199,7 +247,24 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (n int64, err error) {
 
 	err = dec.Decode(&vk.G1.K)
 	n += dec.BytesRead()
+	if err != nil {
+		return
+	}
+
+	if !v1 {
+		vk.G1.Alpha = curve.G1Affine{}
+		vk.G2.Beta = curve.G2Affine{}
+		return
+	}
+
+	err = dec.Decode(&vk.G1.Alpha)
+	n += dec.BytesRead()
+	if err != nil {
+		return
+	}
 
+	err = dec.Decode(&vk.G2.Beta)
+	n += dec.BytesRead()
 	return
 }
 
@@ -287,3 +352,119 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 
 	return n + dec.BytesRead(), nil
 }
+
+// WriteTo writes binary encoding of the aggregate proof to writer
+// the number of rounds is followed by the GT elements, then the compressed points
+func (proof *AggregateProof) WriteTo(w io.Writer) (n int64, err error) {
+	err = binary.Write(w, binary.BigEndian, uint64(len(proof.Rounds)))
+	if err != nil {
+		return
+	}
+	n += 8
+
+	for _, e := range proof.gtElements() {
+		buf := e.Bytes()
+		var written int
+		written, err = w.Write(buf[:])
+		n += int64(written)
+		if err != nil {
+			return
+		}
+	}
+
+	enc := curve.NewEncoder(w)
+	for _, v := range proof.points() {
+		if err = enc.Encode(v); err != nil {
+			n += enc.BytesWritten()
+			return
+		}
+	}
+	n += enc.BytesWritten()
+	return
+}
+
+// ReadFrom attempts to decode an aggregate proof from reader
+// AggregateProof must be encoded through WriteTo
+func (proof *AggregateProof) ReadFrom(r io.Reader) (n int64, err error) {
+	var buf [curve.SizeOfGT]byte
+
+	var read int
+	read, err = io.ReadFull(r, buf[:8])
+	n += int64(read)
+	if err != nil {
+		return
+	}
+	proof.Rounds = make([]AggregationRound, binary.BigEndian.Uint64(buf[:8]))
+
+	for _, e := range proof.gtElements() {
+		read, err = io.ReadFull(r, buf[:])
+		n += int64(read)
+		if err != nil {
+			return
+		}
+		if err = e.SetBytes(buf[:]); err != nil {
+			return
+		}
+	}
+
+	dec := curve.NewDecoder(r)
+	for _, v := range proof.points() {
+		if err = dec.Decode(v); err != nil {
+			n += dec.BytesRead()
+			return
+		}
+	}
+	n += dec.BytesRead()
+	return
+}
+
+// gtElements returns the GT elements of the proof, in serialization order
+func (proof *AggregateProof) gtElements() []*curve.GT {
+	res := []*curve.GT{&proof.ComAB[0], &proof.ComAB[1], &proof.ComC[0], &proof.ComC[1], &proof.ZAB}
+	for i := 0; i < len(proof.Rounds); i++ {
+		round := &proof.Rounds[i]
+		res = append(res,
+			&round.ComABL[0], &round.ComABL[1], &round.ComABR[0], &round.ComABR[1],
+			&round.ZABL, &round.ZABR,
+			&round.ComCL[0], &round.ComCL[1], &round.ComCR[0], &round.ComCR[1],
+		)
+	}
+	return res
+}
+
+// points returns the points of the proof, in serialization order
+func (proof *AggregateProof) points() []interface{} {
+	res := []interface{}{
+		&proof.ZC,
+		&proof.A, &proof.B, &proof.C,
+		&proof.V[0], &proof.V[1], &proof.W[0], &proof.W[1],
+		&proof.OpeningV[0], &proof.OpeningV[1], &proof.OpeningW[0], &proof.OpeningW[1],
+	}
+	for i := 0; i < len(proof.Rounds); i++ {
+		res = append(res, &proof.Rounds[i].ZCL, &proof.Rounds[i].ZCR)
+	}
+	return res
+}
+
+// WriteTo writes binary encoding of the aggregation SRS to writer
+// points are stored in compressed form
+func (srs *AggregationSRS) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w)
+	for _, v := range []interface{}{srs.G1.A, srs.G1.B, srs.G2.A, srs.G2.B} {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom attempts to decode an aggregation SRS from reader
+func (srs *AggregationSRS) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+	for _, v := range []interface{}{&srs.G1.A, &srs.G1.B, &srs.G2.A, &srs.G2.B} {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
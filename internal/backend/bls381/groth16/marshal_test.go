@@ -101,6 +101,8 @@ func TestVerifyingKeySerialization(t *testing.T) {
 			nbWires := 6
 
 			vk.E.SetRandom()
+			vk.G1.Alpha = p1
+			vk.G2.Beta = p2
 			vk.G2.GammaNeg = p2
 			vk.G2.DeltaNeg = p2
 
@@ -160,6 +162,46 @@ func TestVerifyingKeySerialization(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestVerifyingKeyFormat(t *testing.T) {
+	_, _, p1, p2 := curve.Generators()
+
+	var vk VerifyingKey
+	vk.E.SetRandom()
+	vk.G1.Alpha = p1
+	vk.G2.Beta = p2
+	vk.G2.GammaNeg = p2
+	vk.G2.DeltaNeg = p2
+	vk.G1.K = []curve.G1Affine{p1, p1}
+	vk.PublicInputs = []string{"ONE_WIRE", "Y"}
+
+	var buf bytes.Buffer
+	if _, err := vk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	v1 := buf.Bytes()
+
+	// version 0 has no version number, and ends with [Kvk]1
+	v0 := v1[8 : len(v1)-curve.SizeOfG1AffineCompressed-curve.SizeOfG2AffineCompressed]
+	var vk0 VerifyingKey
+	if _, err := vk0.ReadFrom(bytes.NewReader(v0)); err != nil {
+		t.Fatal(err)
+	}
+	if !vk0.G1.Alpha.IsInfinity() || !vk0.G2.Beta.IsInfinity() {
+		t.Fatal("[α]1 and [β]2 should be the infinity in a version 0 key")
+	}
+	vk0.G1.Alpha, vk0.G2.Beta = vk.G1.Alpha, vk.G2.Beta
+	if !reflect.DeepEqual(&vk, &vk0) {
+		t.Fatal("the version 0 key doesn't match")
+	}
+
+	// unknown version
+	v2 := append([]byte{}, v1...)
+	v2[7] = 2
+	if _, err := new(VerifyingKey).ReadFrom(bytes.NewReader(v2)); err != errVKFormat {
+		t.Fatalf("expected errVKFormat, got %v", err)
+	}
+}
+
 func TestProvingKeySerialization(t *testing.T) {
 	parameters := gopter.DefaultTestParameters()
 	parameters.MinSuccessfulTests = 10
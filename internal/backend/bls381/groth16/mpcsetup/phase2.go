@@ -0,0 +1,324 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package mpcsetup
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gurvy/bls381/fr"
+
+	curve "github.com/consensys/gurvy/bls381"
+
+	bls381backend "github.com/consensys/gnark/internal/backend/bls381"
+
+	"github.com/consensys/gnark/internal/backend/bls381/fft"
+
+	"github.com/consensys/gnark/internal/backend/bls381/groth16"
+
+	"github.com/consensys/gurvy"
+)
+
+var (
+	errPhase1TooSmall       = errors.New("the powers of τ ceremony is too small for the number of constraints of the circuit")
+	errInvalidPhase2Size    = errors.New("the contributions don't have the same size")
+	errPhase2KnowledgeProof = errors.New("couldn't verify the proof of knowledge of δ")
+	errPhase2Update         = errors.New("couldn't verify that [δ]₁ and [δ]₂ were updated with the contributed δ")
+	errPhase2Division       = errors.New("couldn't verify that L and Z were divided by the contributed δ")
+	errPhase2Subgroup       = errors.New("the parameters are not in the correct subgroups")
+	errPhase2Mismatch       = errors.New("the evaluations don't match the phase 2 parameters")
+)
+
+// Phase2 is the state of the circuit specific part of the ceremony (phase 2 of the ceremony)
+//
+// Each contribution multiplies δ by a random δ' and divides L and Z by δ'
+type Phase2 struct {
+	Parameters struct {
+		G1 struct {
+			Delta curve.G1Affine
+			L     []curve.G1Affine // {[(βAᵢ(τ)+αBᵢ(τ)+Cᵢ(τ))/δ]₁}, i private wire
+			Z     []curve.G1Affine // {[τⁱ(τⁿ-1)/δ]₁}, i < n, in natural order
+		}
+		G2 struct {
+			Delta curve.G2Affine
+		}
+	}
+
+	// proof of knowledge of the last contribution
+	PublicKey PublicKey
+
+	// Hash of the contribution, used as challenge by the next contributor
+	Hash [sha256.Size]byte
+}
+
+// Phase2Evaluations holds the parts of the keys that don't depend on δ,
+// they are computed once from the phase 1 by InitPhase2
+type Phase2Evaluations struct {
+	G1 struct {
+		Alpha, Beta curve.G1Affine
+		A, B        []curve.G1Affine // {[Aᵢ(τ)]₁}, {[Bᵢ(τ)]₁}, i wire
+		VKK         []curve.G1Affine // {[βAᵢ(τ)+αBᵢ(τ)+Cᵢ(τ)]₁}, i public wire
+	}
+	G2 struct {
+		Beta curve.G2Affine
+		B    []curve.G2Affine // {[Bᵢ(τ)]₂}, i wire
+	}
+}
+
+// InitPhase2 initializes the circuit specific part of the ceremony from the result of the powers of τ ceremony
+// the parameters are set with δ = 1
+func InitPhase2(r1cs *bls381backend.R1CS, phase1 *Phase1) (*Phase2, *Phase2Evaluations, error) {
+	domain := fft.NewDomain(r1cs.NbConstraints)
+	n := int(domain.Cardinality)
+	if n > len(phase1.Parameters.G2.Tau) {
+		return nil, nil, errPhase1TooSmall
+	}
+
+	// evaluations of the Lagrange polynomials at τ
+	tauL1 := lagrangeCoeffsG1(phase1.Parameters.G1.Tau[:n], domain)
+	tauL2 := lagrangeCoeffsG2(phase1.Parameters.G2.Tau[:n], domain)
+	alphaTauL1 := lagrangeCoeffsG1(phase1.Parameters.G1.AlphaTau[:n], domain)
+	betaTauL1 := lagrangeCoeffsG1(phase1.Parameters.G1.BetaTau[:n], domain)
+
+	// constraint i uses the Lagrange polynomial Lᵢ, as in groth16.Setup
+	nbWires := int(r1cs.NbWires)
+	A := make([]curve.G1Jac, nbWires)
+	B1 := make([]curve.G1Jac, nbWires)
+	B2 := make([]curve.G2Jac, nbWires)
+	K := make([]curve.G1Jac, nbWires)
+
+	var c big.Int
+	var tmp1 curve.G1Affine
+	var tmp2 curve.G2Affine
+	for i, constraint := range r1cs.Constraints {
+		for _, t := range constraint.L {
+			r1cs.Coefficients[t.CoeffID()].ToBigIntRegular(&c)
+			tmp1.ScalarMultiplication(&tauL1[i], &c)
+			A[t.VariableID()].AddMixed(&tmp1)
+			tmp1.ScalarMultiplication(&betaTauL1[i], &c)
+			K[t.VariableID()].AddMixed(&tmp1)
+		}
+		for _, t := range constraint.R {
+			r1cs.Coefficients[t.CoeffID()].ToBigIntRegular(&c)
+			tmp1.ScalarMultiplication(&tauL1[i], &c)
+			B1[t.VariableID()].AddMixed(&tmp1)
+			tmp2.ScalarMultiplication(&tauL2[i], &c)
+			B2[t.VariableID()].AddMixed(&tmp2)
+			tmp1.ScalarMultiplication(&alphaTauL1[i], &c)
+			K[t.VariableID()].AddMixed(&tmp1)
+		}
+		for _, t := range constraint.O {
+			r1cs.Coefficients[t.CoeffID()].ToBigIntRegular(&c)
+			tmp1.ScalarMultiplication(&tauL1[i], &c)
+			K[t.VariableID()].AddMixed(&tmp1)
+		}
+	}
+
+	evals := &Phase2Evaluations{}
+	evals.G1.Alpha = phase1.Parameters.G1.AlphaTau[0]
+	evals.G1.Beta = phase1.Parameters.G1.BetaTau[0]
+	evals.G2.Beta = phase1.Parameters.G2.Beta
+	evals.G1.A = batchFromJacobianG1(A)
+	evals.G1.B = batchFromJacobianG1(B1)
+	evals.G2.B = make([]curve.G2Affine, nbWires)
+	for i := 0; i < nbWires; i++ {
+		evals.G2.B[i].FromJacobian(&B2[i])
+	}
+
+	// wires are ordered [private | public], γ = 1
+	nbPrivateWires := nbWires - int(r1cs.NbPublicWires)
+	k := batchFromJacobianG1(K)
+	evals.G1.VKK = k[nbPrivateWires:]
+
+	phase2 := &Phase2{}
+	_, _, g1, g2 := curve.Generators()
+	phase2.Parameters.G1.Delta = g1
+	phase2.Parameters.G2.Delta = g2
+	phase2.Parameters.G1.L = k[:nbPrivateWires:nbPrivateWires]
+
+	// Z[i] = [τⁱ⁺ⁿ]₁ - [τⁱ]₁, the last one is not available in the powers of τ and is not used by the prover
+	var z curve.G1Jac
+	phase2.Parameters.G1.Z = make([]curve.G1Affine, n)
+	tau := phase1.Parameters.G1.Tau
+	for i := 0; i < n && i+n < len(tau); i++ {
+		z.FromAffine(&tau[i+n])
+		tmp1.Neg(&tau[i])
+		z.AddMixed(&tmp1)
+		phase2.Parameters.G1.Z[i].FromJacobian(&z)
+	}
+
+	phase2.Hash = phase2.computeHash()
+
+	return phase2, evals, nil
+}
+
+// Contribute multiplies δ by a random δ' sampled from randomness, divides L and Z by δ'
+// and sets the proof of knowledge of δ'.
+// If randomness is nil, crypto/rand is used.
+func (phase2 *Phase2) Contribute(randomness io.Reader) error {
+	var delta, deltaInv fr.Element
+	if err := randomScalar(randomness, &delta); err != nil {
+		return err
+	}
+	deltaInv.Inverse(&delta)
+
+	var err error
+	if phase2.PublicKey, err = newPublicKey(delta, phase2.Hash[:], dstDelta, randomness); err != nil {
+		return err
+	}
+
+	var bDelta big.Int
+	delta.ToBigIntRegular(&bDelta)
+	phase2.Parameters.G1.Delta.ScalarMultiplication(&phase2.Parameters.G1.Delta, &bDelta)
+	phase2.Parameters.G2.Delta.ScalarMultiplication(&phase2.Parameters.G2.Delta, &bDelta)
+
+	deltaInvs := make([]fr.Element, len(phase2.Parameters.G1.Z))
+	for i := 0; i < len(deltaInvs); i++ {
+		deltaInvs[i] = deltaInv
+	}
+	scaleG1(phase2.Parameters.G1.L, deltaInvs[:len(phase2.Parameters.G1.L)])
+	scaleG1(phase2.Parameters.G1.Z, deltaInvs)
+
+	phase2.Hash = phase2.computeHash()
+
+	return nil
+}
+
+// VerifyPhase2 checks that next is a valid contribution on top of prev
+func VerifyPhase2(prev, next *Phase2) error {
+	if len(next.Parameters.G1.L) != len(prev.Parameters.G1.L) ||
+		len(next.Parameters.G1.Z) != len(prev.Parameters.G1.Z) {
+		return errInvalidPhase2Size
+	}
+
+	if next.Hash != next.computeHash() {
+		return errInvalidHash
+	}
+
+	// the points must be in the correct subgroups
+	if !next.isValid() {
+		return errPhase2Subgroup
+	}
+
+	// the proof of knowledge of δ' is binded to the previous contribution
+	r, ok := next.PublicKey.verify(prev.Hash[:], dstDelta)
+	if !ok {
+		return errPhase2KnowledgeProof
+	}
+
+	// [δ]₁ and [δ]₂ are updated with the contributed δ'
+	if !sameRatio(next.Parameters.G1.Delta, prev.Parameters.G1.Delta, next.PublicKey.XR, r) ||
+		!sameRatio(next.PublicKey.SXG, next.PublicKey.SG, next.Parameters.G2.Delta, prev.Parameters.G2.Delta) {
+		return errPhase2Update
+	}
+
+	// L and Z are divided by δ'
+	prevLZ := append(append([]curve.G1Affine{}, prev.Parameters.G1.L...), prev.Parameters.G1.Z...)
+	nextLZ := append(append([]curve.G1Affine{}, next.Parameters.G1.L...), next.Parameters.G1.Z...)
+	l1, l2 := merge(prevLZ, nextLZ)
+	if !sameRatio(l1, l2, next.Parameters.G2.Delta, prev.Parameters.G2.Delta) {
+		return errPhase2Division
+	}
+
+	return nil
+}
+
+// ExtractKeys builds the Groth16 keys of the circuit from the result of the ceremony
+func ExtractKeys(r1cs *bls381backend.R1CS, phase2 *Phase2, evals *Phase2Evaluations, pk *groth16.ProvingKey, vk *groth16.VerifyingKey) error {
+	domain := fft.NewDomain(r1cs.NbConstraints)
+	nbWires := int(r1cs.NbWires)
+	nbPrivateWires := nbWires - int(r1cs.NbPublicWires)
+	if len(evals.G1.A) != nbWires ||
+		len(phase2.Parameters.G1.L) != nbPrivateWires ||
+		len(phase2.Parameters.G1.Z) != int(domain.Cardinality) {
+		return errPhase2Mismatch
+	}
+
+	// proving key
+	pk.Domain = *domain
+	pk.G1.Alpha = evals.G1.Alpha
+	pk.G1.Beta = evals.G1.Beta
+	pk.G1.Delta = phase2.Parameters.G1.Delta
+	pk.G1.A = evals.G1.A
+	pk.G1.B = evals.G1.B
+	pk.G1.K = phase2.Parameters.G1.L
+	pk.G1.Z = make([]curve.G1Affine, len(phase2.Parameters.G1.Z))
+	copy(pk.G1.Z, phase2.Parameters.G1.Z)
+	bitReverseG1(pk.G1.Z)
+	pk.G2.Beta = evals.G2.Beta
+	pk.G2.Delta = phase2.Parameters.G2.Delta
+	pk.G2.B = evals.G2.B
+
+	// verifying key
+	_, _, _, g2 := curve.Generators()
+	vk.PublicInputs = r1cs.PublicWires
+	vk.G1.Alpha = evals.G1.Alpha
+	vk.G1.K = evals.G1.VKK
+	vk.G2.Beta = evals.G2.Beta
+	vk.G2.GammaNeg.Neg(&g2)
+	vk.G2.DeltaNeg.Neg(&phase2.Parameters.G2.Delta)
+
+	var err error
+	vk.E, err = curve.Pair([]curve.G1Affine{evals.G1.Alpha}, []curve.G2Affine{evals.G2.Beta})
+	return err
+}
+
+// GetCurveID returns the curveID
+func (phase2 *Phase2) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+// GetCurveID returns the curveID
+func (evals *Phase2Evaluations) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+// isValid returns true if the parameters are in the correct subgroups
+func (phase2 *Phase2) isValid() bool {
+	if !phase2.Parameters.G1.Delta.IsInSubGroup() || !phase2.Parameters.G2.Delta.IsInSubGroup() {
+		return false
+	}
+	for _, points := range [][]curve.G1Affine{phase2.Parameters.G1.L, phase2.Parameters.G1.Z} {
+		for i := 0; i < len(points); i++ {
+			if !points[i].IsInSubGroup() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// computeHash returns the sha256 hash of the serialized contribution (parameters and public key)
+func (phase2 *Phase2) computeHash() [sha256.Size]byte {
+	h := sha256.New()
+	if _, err := phase2.writeTo(h); err != nil {
+		panic(err) // writing to a hash doesn't fail
+	}
+	var res [sha256.Size]byte
+	copy(res[:], h.Sum(nil))
+	return res
+}
+
+func batchFromJacobianG1(points []curve.G1Jac) []curve.G1Affine {
+	res := make([]curve.G1Affine, len(points))
+	for i := 0; i < len(points); i++ {
+		res[i].FromJacobian(&points[i])
+	}
+	return res
+}
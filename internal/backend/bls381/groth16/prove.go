@@ -49,10 +49,10 @@ func (proof *Proof) GetCurveID() gurvy.ID {
 	return curve.ID
 }
 
-// Prove generates the proof of knoweldge of a r1cs with solution.
+// Prove generates the proof of knoweldge of a r1cs with solution (a map[string]interface{} or a *witness.Witness).
 // if force flag is set, Prove ignores R1CS solving error (ie invalid solution) and executes
 // the FFTs and MultiExponentiations to compute an (invalid) Proof object
-func Prove(r1cs *bls381backend.R1CS, pk *ProvingKey, solution map[string]interface{}, force bool) (*Proof, error) {
+func Prove(r1cs *bls381backend.R1CS, pk *ProvingKey, solution interface{}, force bool) (*Proof, error) {
 	nbPrivateWires := r1cs.NbWires - r1cs.NbPublicWires
 
 	// solve the R1CS and compute the a, b, c vectors
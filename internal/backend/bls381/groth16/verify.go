@@ -22,16 +22,22 @@ import (
 	curve "github.com/consensys/gurvy/bls381"
 
 	"errors"
+	"fmt"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/internal/backend/bls381/witness"
+	"io"
+	"math/big"
 )
 
 var (
 	errPairingCheckFailed         = errors.New("pairing doesn't match")
 	errCorrectSubgroupCheckFailed = errors.New("points in the proof are not in the correct subgroup")
+	errBatchSize                  = errors.New("the number of proofs and public inputs don't match")
+	errInvalidInputs              = errors.New("public inputs must be a map[string]interface{} or a *witness.Witness")
 )
 
-// Verify verifies a proof
-func Verify(proof *Proof, vk *VerifyingKey, inputs map[string]interface{}) error {
+// Verify verifies a proof against the public inputs (a map[string]interface{} or a *witness.Witness)
+func Verify(proof *Proof, vk *VerifyingKey, inputs interface{}) error {
 
 	// check that the points in the proof are in the correct subgroup
 	if !proof.isValid() {
@@ -53,7 +59,7 @@ func Verify(proof *Proof, vk *VerifyingKey, inputs map[string]interface{}) error
 
 	// compute e(Σx.[Kvk(t)]1, -[γ]2)
 	var kSum curve.G1Affine
-	kInputs, err := ParsePublicInput(vk.PublicInputs, inputs)
+	kInputs, err := parsePublicWitness(vk.PublicInputs, inputs)
 	if err != nil {
 		return err
 	}
@@ -77,6 +83,175 @@ func Verify(proof *Proof, vk *VerifyingKey, inputs map[string]interface{}) error
 	return nil
 }
 
+// BatchVerify verifies a batch of proofs against the same verifying key.
+//
+// The verification equations are combined with random coefficients rᵢ, so that the batch costs
+// one multi Miller loop and one final exponentiation:
+//
+//	∏ e(rᵢ.Arᵢ, Bsᵢ) . e(Σ rᵢ.Krsᵢ, -[δ]2) . e(Σ rᵢ.Σxᵢⱼ.[Kvkⱼ(t)]1, -[γ]2) == e(α, β)^(Σ rᵢ)
+//
+// If findInvalid is set and the batch doesn't verify, the invalid proofs are looked for by bisection
+// and a *backend.BatchVerificationError listing them is returned.
+//
+// inputs[i] holds the public inputs of proofs[i] (a map[string]interface{} or a *witness.Witness).
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, inputs []interface{}, findInvalid bool) error {
+	if len(proofs) != len(inputs) {
+		return errBatchSize
+	}
+
+	kInputs := make([][]fr.Element, len(proofs))
+	indexes := make([]int, 0, len(proofs))
+	var invalid []int
+	for i := 0; i < len(proofs); i++ {
+		var err error
+		if kInputs[i], err = parsePublicWitness(vk.PublicInputs, inputs[i]); err != nil {
+			return err
+		}
+		for j := 0; j < len(kInputs[i]); j++ {
+			kInputs[i][j].ToMont()
+		}
+
+		// check that the points in the proof are in the correct subgroup
+		if !proofs[i].isValid() {
+			if !findInvalid {
+				return errCorrectSubgroupCheckFailed
+			}
+			invalid = append(invalid, i)
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+
+	if len(indexes) == 0 {
+		if len(invalid) != 0 {
+			return &backend.BatchVerificationError{InvalidProofs: invalid}
+		}
+		return nil
+	}
+
+	ok, err := batchCheck(proofs, vk, kInputs, indexes)
+	if err != nil {
+		return err
+	}
+	if ok && len(invalid) == 0 {
+		return nil
+	}
+	if !findInvalid {
+		return errPairingCheckFailed
+	}
+
+	if !ok {
+		invalidPairings, err := bisect(proofs, vk, kInputs, indexes)
+		if err != nil {
+			return err
+		}
+		invalid = mergeSorted(invalid, invalidPairings)
+	}
+	return &backend.BatchVerificationError{InvalidProofs: invalid}
+}
+
+// bisect returns the indexes of the proofs that don't verify, splitting the batch in halves
+func bisect(proofs []*Proof, vk *VerifyingKey, kInputs [][]fr.Element, indexes []int) ([]int, error) {
+	if len(indexes) == 1 {
+		return indexes, nil
+	}
+	var res []int
+	for _, half := range [][]int{indexes[:len(indexes)/2], indexes[len(indexes)/2:]} {
+		ok, err := batchCheck(proofs, vk, kInputs, half)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			continue
+		}
+		invalid, err := bisect(proofs, vk, kInputs, half)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, invalid...)
+	}
+	return res, nil
+}
+
+// batchCheck returns true if the random linear combination of the verification equations
+// of proofs[indexes] holds. kInputs are in Montgomery form.
+func batchCheck(proofs []*Proof, vk *VerifyingKey, kInputs [][]fr.Element, indexes []int) (bool, error) {
+	n := len(indexes)
+
+	// random coefficients
+	r := make([]fr.Element, n)
+	var rSum fr.Element
+	for i := 0; i < n; i++ {
+		if _, err := r[i].SetRandom(); err != nil {
+			return false, err
+		}
+		rSum.Add(&rSum, &r[i])
+	}
+
+	// Σ rᵢ.xᵢⱼ, combined public inputs
+	kScalars := make([]fr.Element, len(vk.G1.K))
+	var tmp fr.Element
+	for i, idx := range indexes {
+		for j := 0; j < len(kScalars); j++ {
+			tmp.Mul(&r[i], &kInputs[idx][j])
+			kScalars[j].Add(&kScalars[j], &tmp)
+		}
+	}
+	for j := 0; j < len(kScalars); j++ {
+		kScalars[j].FromMont()
+	}
+	var kSum curve.G1Affine
+	kSum.MultiExp(vk.G1.K, kScalars)
+
+	// rᵢ.Arᵢ and Σ rᵢ.Krsᵢ
+	P := make([]curve.G1Affine, n, n+2)
+	Q := make([]curve.G2Affine, n, n+2)
+	krs := make([]curve.G1Affine, n)
+	var bRSum big.Int
+	for i, idx := range indexes {
+		var b big.Int
+		r[i].ToBigIntRegular(&b)
+		P[i].ScalarMultiplication(&proofs[idx].Ar, &b)
+		Q[i] = proofs[idx].Bs
+		krs[i] = proofs[idx].Krs
+		r[i].FromMont()
+	}
+	var krsSum curve.G1Affine
+	krsSum.MultiExp(krs, r)
+
+	P = append(P, krsSum, kSum)
+	Q = append(Q, vk.G2.DeltaNeg, vk.G2.GammaNeg)
+	ml, err := curve.MillerLoop(P, Q)
+	if err != nil {
+		return false, err
+	}
+	left := curve.FinalExponentiation(&ml)
+
+	// e(α, β)^(Σ rᵢ)
+	var right curve.GT
+	rSum.ToBigIntRegular(&bRSum)
+	right.Exp(&vk.E, bRSum)
+
+	return left.Equal(&right), nil
+}
+
+// mergeSorted merges two sorted slices of indexes
+func mergeSorted(a, b []int) []int {
+	res := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] < b[j] {
+			res = append(res, a[i])
+			i++
+		} else {
+			res = append(res, b[j])
+			j++
+		}
+	}
+	res = append(res, a[i:]...)
+	return append(res, b[j:]...)
+}
+
 // ParsePublicInput return the ordered public input values
 // in regular form (used as scalars for multi exponentiation).
 // The function is public because it's needed for the recursive snark.
@@ -100,3 +275,28 @@ func ParsePublicInput(expectedNames []string, input map[string]interface{}) ([]f
 
 	return toReturn, nil
 }
+
+// parsePublicWitness returns the ordered public input values in regular form, as ParsePublicInput,
+// from a map[string]interface{} or a *witness.Witness (the secret inputs of which are ignored)
+func parsePublicWitness(expectedNames []string, inputs interface{}) ([]fr.Element, error) {
+	switch _inputs := inputs.(type) {
+	case map[string]interface{}:
+		return ParsePublicInput(expectedNames, _inputs)
+	case *witness.Witness:
+		toReturn, err := _inputs.PublicInputs(expectedNames)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(toReturn); i++ {
+			toReturn[i].FromMont()
+		}
+		return toReturn, nil
+	default:
+		return nil, fmt.Errorf("%T: %w", inputs, errInvalidInputs)
+	}
+}
+
+// ExportSolidity is not implemented for BLS381
+func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
+	return errors.New("not implemented")
+}
@@ -0,0 +1,321 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package mpcsetup
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gurvy/bn256/fr"
+
+	curve "github.com/consensys/gurvy/bn256"
+
+	"github.com/consensys/gnark/internal/backend/bn256/fft"
+
+	"github.com/consensys/gurvy/bn256/fp"
+)
+
+// PublicKey is a proof of knowledge of a contributed scalar x
+// [s]₁, x[s]₁ and x[r]₂, where s is random and r is derived from [s]₁, x[s]₁ and the challenge
+type PublicKey struct {
+	SG  curve.G1Affine
+	SXG curve.G1Affine
+	XR  curve.G2Affine
+}
+
+// newPublicKey returns a proof of knowledge of x, binded to the challenge
+func newPublicKey(x fr.Element, challenge []byte, dst byte, randomness io.Reader) (PublicKey, error) {
+	var pk PublicKey
+	_, _, g1, _ := curve.Generators()
+
+	var s fr.Element
+	if err := randomScalar(randomness, &s); err != nil {
+		return pk, err
+	}
+	var bs, bx big.Int
+	s.ToBigIntRegular(&bs)
+	x.ToBigIntRegular(&bx)
+
+	// [s]₁, x[s]₁
+	pk.SG.ScalarMultiplication(&g1, &bs)
+	pk.SXG.ScalarMultiplication(&pk.SG, &bx)
+
+	// x[r]₂
+	r := genR(pk.SG, pk.SXG, challenge, dst)
+	pk.XR.ScalarMultiplication(&r, &bx)
+
+	return pk, nil
+}
+
+// verify checks the proof of knowledge and returns the point [r]₂ it is built on
+func (pk *PublicKey) verify(challenge []byte, dst byte) (curve.G2Affine, bool) {
+	if !pk.SG.IsInSubGroup() || !pk.SXG.IsInSubGroup() || !pk.XR.IsInSubGroup() {
+		return curve.G2Affine{}, false
+	}
+	r := genR(pk.SG, pk.SXG, challenge, dst)
+	return r, sameRatio(pk.SG, pk.SXG, r, pk.XR)
+}
+
+// genR derives a point of G₂ from [s]₁, x[s]₁ and the challenge, with unknown discrete logarithm
+//
+// it uses a try-and-increment hash to curve followed by a cofactor clearing
+// (curve.HashToCurveG2Svdw doesn't map to the curve for all the supported curves)
+func genR(sG1, sxG1 curve.G1Affine, challenge []byte, dst byte) curve.G2Affine {
+	_, _, _, g2 := curve.Generators()
+
+	// b coefficient of the twist, b = y² - x³
+	b := g2.Y
+	x3 := g2.X
+	b.Square(&b)
+	x3.Square(&x3).Mul(&x3, &g2.X)
+	b.Sub(&b, &x3)
+
+	seed := sha256.New()
+	seed.Write([]byte{dst})
+	seed.Write(sG1.Marshal())
+	seed.Write(sxG1.Marshal())
+	seed.Write(challenge)
+	digest := seed.Sum(nil)
+
+	var r curve.G2Affine
+	for counter := uint32(0); ; counter++ {
+		hashToField(&r.X.A0, digest, counter, 0)
+		hashToField(&r.X.A1, digest, counter, 1)
+
+		// y² = x³ + b
+		y2 := r.X
+		y2.Square(&y2).Mul(&y2, &r.X).Add(&y2, &b)
+		if y2.Legendre() != 1 {
+			continue
+		}
+		r.Y.Sqrt(&y2)
+
+		r.ClearCofactor(&r)
+		if !r.IsInfinity() {
+			return r
+		}
+	}
+}
+
+// hashToField sets e to sha256(digest ‖ counter ‖ i) ‖ sha256(digest ‖ counter ‖ i+1) mod p
+func hashToField(e *fp.Element, digest []byte, counter uint32, i byte) {
+	var buf [2 * sha256.Size]byte
+	for j := byte(0); j < 2; j++ {
+		h := sha256.New()
+		h.Write(digest)
+		binary.Write(h, binary.BigEndian, counter)
+		h.Write([]byte{2*i + j})
+		copy(buf[int(j)*sha256.Size:], h.Sum(nil))
+	}
+	e.SetBigInt(new(big.Int).SetBytes(buf[:]))
+}
+
+// sameRatio returns true if e(a₁, b₂) == e(b₁, a₂), that is, if a₁ and b₁ have the same discrete log ratio as a₂ and b₂
+func sameRatio(a1, b1 curve.G1Affine, a2, b2 curve.G2Affine) bool {
+	if a1.IsInfinity() || b1.IsInfinity() || a2.IsInfinity() || b2.IsInfinity() {
+		return false
+	}
+	var na1 curve.G1Affine
+	na1.Neg(&a1)
+	ok, err := pairingCheck([]curve.G1Affine{na1, b1}, []curve.G2Affine{b2, a2})
+	return err == nil && ok
+}
+
+// pairingCheck returns true if e(P[0], Q[0]).e(P[1], Q[1])... == 1
+func pairingCheck(P []curve.G1Affine, Q []curve.G2Affine) (bool, error) {
+	return curve.PairingCheck(P, Q)
+}
+
+// linearCombinationG1 returns Σ rᵢ.A[i] and Σ rᵢ.A[i+1] for random rᵢ
+// if A contains successive powers of τ, the second sum is τ times the first one
+func linearCombinationG1(A []curve.G1Affine) (L1, L2 curve.G1Affine) {
+	r := randomScalars(len(A) - 1)
+	L1.MultiExp(A[:len(A)-1], r)
+	L2.MultiExp(A[1:], r)
+	return
+}
+
+// linearCombinationG2 returns Σ rᵢ.A[i] and Σ rᵢ.A[i+1] for random rᵢ
+// if A contains successive powers of τ, the second sum is τ times the first one
+func linearCombinationG2(A []curve.G2Affine) (L1, L2 curve.G2Affine) {
+	r := randomScalars(len(A) - 1)
+	L1.MultiExp(A[:len(A)-1], r)
+	L2.MultiExp(A[1:], r)
+	return
+}
+
+// merge returns Σ rᵢ.A[i] and Σ rᵢ.B[i] for the same random rᵢ
+// if B[i] = x.A[i] for all i, the second sum is x times the first one
+func merge(A, B []curve.G1Affine) (L1, L2 curve.G1Affine) {
+	r := randomScalars(len(A))
+	L1.MultiExp(A, r)
+	L2.MultiExp(B, r)
+	return
+}
+
+// randomScalars returns n random scalars in regular form, used by the batch verifications
+func randomScalars(n int) []fr.Element {
+	r := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		if err := randomScalar(nil, &r[i]); err != nil {
+			panic(err) // crypto/rand doesn't fail
+		}
+		r[i].FromMont()
+	}
+	return r
+}
+
+// randomScalar sets x to a non zero scalar sampled from randomness
+// if randomness is nil, crypto/rand is used
+func randomScalar(randomness io.Reader, x *fr.Element) error {
+	if randomness == nil {
+		randomness = rand.Reader
+	}
+	// extra bytes make the modular reduction bias negligible
+	var buf [fr.Bytes + 16]byte
+	x.SetZero()
+	for x.IsZero() {
+		if _, err := io.ReadFull(randomness, buf[:]); err != nil {
+			return err
+		}
+		x.SetBigInt(new(big.Int).SetBytes(buf[:]))
+	}
+	return nil
+}
+
+// lagrangeCoeffsG1 returns {[Lᵢ(τ)]₁} from {[τⁱ]₁}, i < domain.Cardinality, where Lᵢ
+// is the i-th Lagrange polynomial of the domain. This is an inverse FFT in the group.
+func lagrangeCoeffsG1(powers []curve.G1Affine, domain *fft.Domain) []curve.G1Affine {
+	n := int(domain.Cardinality)
+	twiddles := inverseTwiddles(domain)
+
+	a := make([]curve.G1Jac, n)
+	for i := 0; i < n; i++ {
+		a[i].FromAffine(&powers[i])
+	}
+	bitReverseG1Jac(a)
+
+	var t curve.G1Jac
+	for m := 2; m <= n; m <<= 1 {
+		stride := n / m
+		for k := 0; k < n; k += m {
+			for j := 0; j < m/2; j++ {
+				t.ScalarMultiplication(&a[k+j+m/2], &twiddles[j*stride])
+				a[k+j+m/2].Set(&a[k+j]).SubAssign(&t)
+				a[k+j].AddAssign(&t)
+			}
+		}
+	}
+
+	var nInv big.Int
+	domain.CardinalityInv.ToBigIntRegular(&nInv)
+	res := make([]curve.G1Affine, n)
+	for i := 0; i < n; i++ {
+		a[i].ScalarMultiplication(&a[i], &nInv)
+		res[i].FromJacobian(&a[i])
+	}
+	return res
+}
+
+// lagrangeCoeffsG2 returns {[Lᵢ(τ)]₂} from {[τⁱ]₂}, i < domain.Cardinality, where Lᵢ
+// is the i-th Lagrange polynomial of the domain. This is an inverse FFT in the group.
+func lagrangeCoeffsG2(powers []curve.G2Affine, domain *fft.Domain) []curve.G2Affine {
+	n := int(domain.Cardinality)
+	twiddles := inverseTwiddles(domain)
+
+	a := make([]curve.G2Jac, n)
+	for i := 0; i < n; i++ {
+		a[i].FromAffine(&powers[i])
+	}
+	bitReverseG2Jac(a)
+
+	var t curve.G2Jac
+	for m := 2; m <= n; m <<= 1 {
+		stride := n / m
+		for k := 0; k < n; k += m {
+			for j := 0; j < m/2; j++ {
+				t.ScalarMultiplication(&a[k+j+m/2], &twiddles[j*stride])
+				a[k+j+m/2].Set(&a[k+j]).SubAssign(&t)
+				a[k+j].AddAssign(&t)
+			}
+		}
+	}
+
+	var nInv big.Int
+	domain.CardinalityInv.ToBigIntRegular(&nInv)
+	res := make([]curve.G2Affine, n)
+	for i := 0; i < n; i++ {
+		a[i].ScalarMultiplication(&a[i], &nInv)
+		res[i].FromJacobian(&a[i])
+	}
+	return res
+}
+
+// inverseTwiddles returns {ω⁻ⁱ}, i < domain.Cardinality/2, in regular form
+func inverseTwiddles(domain *fft.Domain) []big.Int {
+	twiddles := make([]big.Int, domain.Cardinality/2)
+	var w fr.Element
+	w.SetOne()
+	for i := 0; i < len(twiddles); i++ {
+		w.ToBigIntRegular(&twiddles[i])
+		w.Mul(&w, &domain.GeneratorInv)
+	}
+	return twiddles
+}
+
+// bitReverseG1 permutation as in fft.BitReverse, but with []curve.G1Affine
+func bitReverseG1(a []curve.G1Affine) {
+	n := uint(len(a))
+	nn := uint(bits.UintSize - bits.TrailingZeros(n))
+
+	for i := uint(0); i < n; i++ {
+		irev := bits.Reverse(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
+
+// bitReverseG1Jac permutation as in fft.BitReverse, but with []curve.G1Jac
+func bitReverseG1Jac(a []curve.G1Jac) {
+	n := uint(len(a))
+	nn := uint(bits.UintSize - bits.TrailingZeros(n))
+
+	for i := uint(0); i < n; i++ {
+		irev := bits.Reverse(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
+
+// bitReverseG2Jac permutation as in fft.BitReverse, but with []curve.G2Jac
+func bitReverseG2Jac(a []curve.G2Jac) {
+	n := uint(len(a))
+	nn := uint(bits.UintSize - bits.TrailingZeros(n))
+
+	for i := uint(0); i < n; i++ {
+		irev := bits.Reverse(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
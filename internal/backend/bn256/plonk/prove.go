@@ -0,0 +1,402 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package plonk
+
+import (
+	"math/big"
+
+	"github.com/consensys/gurvy/bn256/fr"
+
+	curve "github.com/consensys/gurvy/bn256"
+
+	bn256backend "github.com/consensys/gnark/internal/backend/bn256"
+
+	"github.com/consensys/gnark/internal/backend/bn256/fft"
+
+	kzg "github.com/consensys/gnark/crypto/kzg/bn256"
+
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gurvy"
+)
+
+// Proof represents a PLONK proof, that can be verified with a VerifyingKey and
+// the public inputs of the statement.
+//
+// l, r, o are the solution vectors (left, right, output wires of the gates), z is the
+// permutation polynomial and t = t_lo + Xⁿ⁺².t_mid + X²⁽ⁿ⁺²⁾.t_hi is the quotient polynomial
+//
+// Notation follows the PLONK paper https://eprint.iacr.org/2019/953.pdf
+type Proof struct {
+	// Commitments to the solution vectors l, r, o
+	LRO [3]kzg.Digest
+
+	// Commitment to z, the permutation polynomial
+	Z kzg.Digest
+
+	// Commitments to t_lo, t_mid, t_hi
+	T [3]kzg.Digest
+
+	// Batched opening proof at ζ of l, r, o, z, ql, qr, qm, qo, qk, s1, s2, s3, t_lo, t_mid, t_hi
+	BatchedProof kzg.BatchOpeningProof
+
+	// Opening proof of z at ζω
+	ZShiftedOpening kzg.OpeningProof
+}
+
+// nbOpenedPolynomials number of polynomials opened at ζ in BatchedProof
+const nbOpenedPolynomials = 15
+
+// GetCurveID returns the curveID
+func (proof *Proof) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+// Prove generates a PLONK proof of knowledge of a solution (a map[string]interface{} or a *witness.Witness) of the r1cs.
+// if force flag is set, Prove ignores R1CS solving error (ie invalid solution) and executes
+// the FFTs and the commitments to compute an (invalid) Proof object
+func Prove(r1cs *bn256backend.R1CS, pk *ProvingKey, solution interface{}, force bool) (*Proof, error) {
+
+	// solve the R1CS and compute the intermediate wires of the gates
+	sol, err := computeSolution(r1cs, pk, solution)
+	if err != nil && !force {
+		return nil, err
+	}
+
+	n := pk.DomainH.Cardinality
+	proof := &Proof{}
+
+	// l, r, o in Lagrange basis
+	var lro [3][]fr.Element
+	for j := 0; j < 3; j++ {
+		lro[j] = make([]fr.Element, n)
+		for i := uint64(0); i < n; i++ {
+			lro[j][i] = sol[pk.Wires[j][i]]
+		}
+	}
+
+	// the first gates constrain the public inputs
+	publicInputs := make([]fr.Element, len(pk.Vk.PublicInputs))
+	copy(publicInputs, lro[0])
+
+	// l, r, o in canonical basis, blinded, and their commitments
+	var lroCanonical [3][]fr.Element
+	for j := 0; j < 3; j++ {
+		if lroCanonical[j], err = blind(toCanonical(lro[j], &pk.DomainH), n, 1); err != nil {
+			return nil, err
+		}
+		if proof.LRO[j], err = kzg.Commit(lroCanonical[j], pk.SRS); err != nil {
+			return nil, err
+		}
+	}
+
+	// derive γ, β
+	gamma := deriveRandomness("gamma", pk.Vk.digests(), publicInputs, proof.LRO[:])
+	beta := deriveRandomness("beta", &gamma)
+
+	// permutation polynomial z
+	z, err := blind(toCanonical(computeZ(lro, pk, beta, gamma), &pk.DomainH), n, 2)
+	if err != nil {
+		return nil, err
+	}
+	if proof.Z, err = kzg.Commit(z, pk.SRS); err != nil {
+		return nil, err
+	}
+
+	// derive α
+	alpha := deriveRandomness("alpha", &beta, &proof.Z)
+
+	// quotient polynomial t, split in t_lo, t_mid, t_hi
+	t := computeQuotient(pk, lroCanonical, z, publicInputs, alpha, beta, gamma)
+	var tSplit [3][]fr.Element
+	for j := uint64(0); j < 3; j++ {
+		tSplit[j] = t[j*(n+2) : (j+1)*(n+2)]
+		if proof.T[j], err = kzg.Commit(tSplit[j], pk.SRS); err != nil {
+			return nil, err
+		}
+	}
+
+	// derive ζ
+	zeta := deriveRandomness("zeta", &alpha, proof.T[:])
+
+	// open the polynomials at ζ, and z at ζω
+	polynomials := [][]fr.Element{
+		lroCanonical[0], lroCanonical[1], lroCanonical[2],
+		z,
+		pk.Ql, pk.Qr, pk.Qm, pk.Qo, pk.Qk,
+		pk.S[0], pk.S[1], pk.S[2],
+		tSplit[0], tSplit[1], tSplit[2],
+	}
+	if proof.BatchedProof, err = kzg.BatchOpenSinglePoint(polynomials, openedDigests(proof, pk.Vk), &zeta, pk.SRS); err != nil {
+		return nil, err
+	}
+
+	var zetaShifted fr.Element
+	zetaShifted.Mul(&zeta, &pk.Vk.Generator)
+	if proof.ZShiftedOpening, err = kzg.Open(z, &zetaShifted, pk.SRS); err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// computeSolution solves the R1CS and returns the solution vector of the gates
+// [R1CS wires | intermediate wires], in Montgomery form
+func computeSolution(r1cs *bn256backend.R1CS, pk *ProvingKey, solution interface{}) ([]fr.Element, error) {
+	a := make([]fr.Element, r1cs.NbConstraints)
+	b := make([]fr.Element, r1cs.NbConstraints)
+	c := make([]fr.Element, r1cs.NbConstraints)
+	sol := make([]fr.Element, pk.NbWires)
+
+	err := r1cs.Solve(solution, a, b, c, sol[:r1cs.NbWires])
+
+	// the intermediate wires are outputs of addition gates (qO == -1), and are
+	// numbered in the order of the gates
+	next := r1cs.NbWires
+	for i := 0; i < len(pk.Wires[2]) && next < pk.NbWires; i++ {
+		if pk.Wires[2][i] != next {
+			continue
+		}
+		var left, right fr.Element
+		left.Mul(&pk.LQl[i], &sol[pk.Wires[0][i]])
+		right.Mul(&pk.LQr[i], &sol[pk.Wires[1][i]])
+		sol[next].Add(&left, &right)
+		next++
+	}
+
+	return sol, err
+}
+
+// blind adds a random multiple of Zₕ = Xⁿ-1 of degree bDegree to p, that is
+// returns p + (b₀ + b₁.X + .. + b_bDegree.X^bDegree).Zₕ
+func blind(p []fr.Element, n uint64, bDegree int) ([]fr.Element, error) {
+	res := make([]fr.Element, n+uint64(bDegree)+1)
+	copy(res, p)
+	for i := 0; i <= bDegree; i++ {
+		var b fr.Element
+		if _, err := b.SetRandom(); err != nil {
+			return nil, err
+		}
+		res[i].Sub(&res[i], &b)
+		res[n+uint64(i)].Add(&res[n+uint64(i)], &b)
+	}
+	return res, nil
+}
+
+// computeZ computes the permutation polynomial z in Lagrange basis:
+// z(1) = 1 and z(ωⁱ⁺¹) = z(ωⁱ).Πⱼ(fⱼ(ωⁱ)+β.kⱼ.ωⁱ+γ) / Πⱼ(fⱼ(ωⁱ)+β.Sⱼ(ωⁱ)+γ)
+// where f₀, f₁, f₂ = l, r, o and k₀, k₁, k₂ = 1, shifter[0], shifter[1]
+func computeZ(lro [3][]fr.Element, pk *ProvingKey, beta, gamma fr.Element) []fr.Element {
+	n := pk.DomainH.Cardinality
+
+	num := make([]fr.Element, n)
+	den := make([]fr.Element, n)
+
+	var x fr.Element
+	var u [3]fr.Element
+	x.SetOne()
+	for i := uint64(0); i < n; i++ {
+		u[0].Set(&x)
+		u[1].Mul(&x, &pk.Vk.Shifter[0])
+		u[2].Mul(&x, &pk.Vk.Shifter[1])
+		num[i].SetOne()
+		den[i].SetOne()
+		for j := 0; j < 3; j++ {
+			var tmp fr.Element
+			tmp.Mul(&beta, &u[j]).Add(&tmp, &lro[j][i]).Add(&tmp, &gamma)
+			num[i].Mul(&num[i], &tmp)
+			tmp.Mul(&beta, &pk.LS[j][i]).Add(&tmp, &lro[j][i]).Add(&tmp, &gamma)
+			den[i].Mul(&den[i], &tmp)
+		}
+		x.Mul(&x, &pk.DomainH.Generator)
+	}
+	den = batchInvert(den)
+
+	z := make([]fr.Element, n)
+	z[0].SetOne()
+	for i := uint64(1); i < n; i++ {
+		z[i].Mul(&z[i-1], &num[i-1]).Mul(&z[i], &den[i-1])
+	}
+
+	return z
+}
+
+// computeQuotient computes the quotient polynomial t in canonical basis, where
+//
+// t.Zₕ = ql.l + qr.r + qm.l.r + qo.o + qk + PI
+//   - α.(Πⱼ(fⱼ+β.kⱼ.X+γ).z - Πⱼ(fⱼ+β.Sⱼ+γ).z(ωX))
+//   - α².(z-1).L₁
+//
+// the numerator is evaluated on a coset of DomainNum, where Zₕ doesn't vanish
+func computeQuotient(pk *ProvingKey, lro [3][]fr.Element, z, publicInputs []fr.Element, alpha, beta, gamma fr.Element) []fr.Element {
+
+	domain := &pk.DomainNum
+	N := domain.Cardinality
+	n := pk.DomainH.Cardinality
+
+	// PI, such that PI(ωⁱ) = -xᵢ for the public inputs xᵢ
+	pi := make([]fr.Element, n)
+	for i := 0; i < len(publicInputs); i++ {
+		pi[i].Neg(&publicInputs[i])
+	}
+	pi = toCanonical(pi, &pk.DomainH)
+
+	// z(ωX)
+	zShifted := make([]fr.Element, len(z))
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(z); i++ {
+		zShifted[i].Mul(&z[i], &acc)
+		acc.Mul(&acc, &pk.DomainH.Generator)
+	}
+
+	// evaluations on the coset
+	l := evaluateCoset(lro[0], domain)
+	r := evaluateCoset(lro[1], domain)
+	o := evaluateCoset(lro[2], domain)
+	ze := evaluateCoset(z, domain)
+	zse := evaluateCoset(zShifted, domain)
+	ql := evaluateCoset(pk.Ql, domain)
+	qr := evaluateCoset(pk.Qr, domain)
+	qm := evaluateCoset(pk.Qm, domain)
+	qo := evaluateCoset(pk.Qo, domain)
+	qk := evaluateCoset(pk.Qk, domain)
+	var s [3][]fr.Element
+	for j := 0; j < 3; j++ {
+		s[j] = evaluateCoset(pk.S[j], domain)
+	}
+	pie := evaluateCoset(pi, domain)
+
+	// x = g.ω_Nʲ, with g = domain.GeneratorSqRt and ω_N = domain.Generator
+	// Zₕ(x) = xⁿ-1 = gⁿ.(ω_Nⁿ)ʲ-1
+	x := make([]fr.Element, N)
+	zh := make([]fr.Element, N)
+	xMinusOne := make([]fr.Element, N)
+	var gn, wn, xn, one fr.Element
+	bn := new(big.Int).SetUint64(n)
+	gn.Exp(domain.GeneratorSqRt, bn)
+	wn.Exp(domain.Generator, bn)
+	one.SetOne()
+	x[0].Set(&domain.GeneratorSqRt)
+	xn.Set(&gn)
+	for j := uint64(0); j < N; j++ {
+		if j > 0 {
+			x[j].Mul(&x[j-1], &domain.Generator)
+			xn.Mul(&xn, &wn)
+		}
+		zh[j].Sub(&xn, &one)
+		xMinusOne[j].Sub(&x[j], &one)
+	}
+	zhInv := batchInvert(zh)
+	xMinusOneInv := batchInvert(xMinusOne)
+
+	var alphaSquare, nInv fr.Element
+	alphaSquare.Square(&alpha)
+	nInv.Set(&pk.Vk.SizeInv)
+
+	t := make([]fr.Element, N)
+	utils.Parallelize(int(N), func(start, end int) {
+		var gate, perm, num, den, startsAtOne, tmp fr.Element
+		for j := start; j < end; j++ {
+
+			// gate constraint
+			gate.Mul(&ql[j], &l[j])
+			tmp.Mul(&qr[j], &r[j])
+			gate.Add(&gate, &tmp)
+			tmp.Mul(&qm[j], &l[j]).Mul(&tmp, &r[j])
+			gate.Add(&gate, &tmp)
+			tmp.Mul(&qo[j], &o[j])
+			gate.Add(&gate, &tmp).Add(&gate, &qk[j]).Add(&gate, &pie[j])
+
+			// permutation constraint
+			num.Mul(&beta, &x[j]).Add(&num, &l[j]).Add(&num, &gamma)
+			tmp.Mul(&beta, &x[j]).Mul(&tmp, &pk.Vk.Shifter[0]).Add(&tmp, &r[j]).Add(&tmp, &gamma)
+			num.Mul(&num, &tmp)
+			tmp.Mul(&beta, &x[j]).Mul(&tmp, &pk.Vk.Shifter[1]).Add(&tmp, &o[j]).Add(&tmp, &gamma)
+			num.Mul(&num, &tmp).Mul(&num, &ze[j])
+
+			den.Mul(&beta, &s[0][j]).Add(&den, &l[j]).Add(&den, &gamma)
+			tmp.Mul(&beta, &s[1][j]).Add(&tmp, &r[j]).Add(&tmp, &gamma)
+			den.Mul(&den, &tmp)
+			tmp.Mul(&beta, &s[2][j]).Add(&tmp, &o[j]).Add(&tmp, &gamma)
+			den.Mul(&den, &tmp).Mul(&den, &zse[j])
+
+			perm.Sub(&num, &den).Mul(&perm, &alpha)
+
+			// z starts at 1: (z-1).L₁, with L₁(x) = (xⁿ-1)/(n.(x-1))
+			startsAtOne.Sub(&ze[j], &one).
+				Mul(&startsAtOne, &zh[j]).
+				Mul(&startsAtOne, &xMinusOneInv[j]).
+				Mul(&startsAtOne, &nInv).
+				Mul(&startsAtOne, &alphaSquare)
+
+			t[j].Add(&gate, &perm).Add(&t[j], &startsAtOne).Mul(&t[j], &zhInv[j])
+		}
+	})
+
+	return interpolateCoset(t, domain)
+}
+
+// evaluateCoset returns the evaluations of p on the coset g.<ω_N> of domain, in natural order,
+// with g = domain.GeneratorSqRt. p is given in canonical basis and len(p) <= domain.Cardinality
+func evaluateCoset(p []fr.Element, domain *fft.Domain) []fr.Element {
+	res := make([]fr.Element, domain.Cardinality)
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(p); i++ {
+		res[i].Mul(&p[i], &acc)
+		acc.Mul(&acc, &domain.GeneratorSqRt)
+	}
+	domain.FFT(res, fft.DIF)
+	fft.BitReverse(res)
+	return res
+}
+
+// interpolateCoset returns the polynomial, in canonical basis, whose evaluations
+// on the coset g.<ω_N> of domain are evals (in natural order). evals is modified.
+func interpolateCoset(evals []fr.Element, domain *fft.Domain) []fr.Element {
+	domain.FFTInverse(evals, fft.DIF)
+	fft.BitReverse(evals)
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(evals); i++ {
+		evals[i].Mul(&evals[i], &acc)
+		acc.Mul(&acc, &domain.GeneratorSqRtInv)
+	}
+	return evals
+}
+
+// batchInvert returns the inverses of the entries of a, using one inversion
+func batchInvert(a []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(a); i++ {
+		res[i].Set(&acc)
+		acc.Mul(&acc, &a[i])
+	}
+	acc.Inverse(&acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		res[i].Mul(&res[i], &acc)
+		acc.Mul(&acc, &a[i])
+	}
+
+	return res
+}
@@ -25,7 +25,9 @@ import (
 	"github.com/fxamacker/cbor/v2"
 
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gnark/internal/backend/bn256/witness"
 	"github.com/consensys/gnark/internal/backend/ioutils"
 
 	"github.com/consensys/gurvy"
@@ -33,6 +35,13 @@ import (
 	"github.com/consensys/gurvy/bn256/fr"
 )
 
+var (
+	errInvalidAssignment = errors.New("assignment must be a map[string]interface{} or a *witness.Witness")
+	errUnexpectedInput   = errors.New("input is not part of the circuit inputs")
+	errHintNotRegistered = errors.New("hint function is not registered, see hint.Register")
+	errHintInputNotSet   = errors.New("hint input is not instantiated")
+)
+
 // R1CS decsribes a set of R1CS constraint
 type R1CS struct {
 	// Wires
@@ -49,6 +58,7 @@ type R1CS struct {
 	NbCOConstraints uint64 // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []r1c.R1C
 	Coefficients    []fr.Element // R1C coefficients indexes point here
+	Hints           []r1c.Hint   // wires computed by hint functions, see backend/hint
 }
 
 // GetNbConstraints returns the total number of constraints
@@ -91,7 +101,7 @@ func (r1cs *R1CS) ReadFrom(r io.Reader) (int64, error) {
 
 // IsSolved returns nil if given assignment solves the R1CS and error otherwise
 // this method wraps r1cs.Solve() and allocates r1cs.Solve() inputs
-func (r1cs *R1CS) IsSolved(assignment map[string]interface{}) error {
+func (r1cs *R1CS) IsSolved(assignment interface{}) error {
 	a := make([]fr.Element, r1cs.NbConstraints)
 	b := make([]fr.Element, r1cs.NbConstraints)
 	c := make([]fr.Element, r1cs.NbConstraints)
@@ -101,10 +111,10 @@ func (r1cs *R1CS) IsSolved(assignment map[string]interface{}) error {
 
 // Solve sets all the wires and returns the a, b, c vectors.
 // the r1cs system should have been compiled before. The entries in a, b, c are in Montgomery form.
-// assignment: map[string]value: contains the input variables
+// assignment: map[string]value or *witness.Witness: contains the input variables
 // a, b, c vectors: ab-c = hz
 // wireValues =  [intermediateVariables | privateInputs | publicInputs]
-func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues []fr.Element) error {
+func (r1cs *R1CS) Solve(assignment interface{}, a, b, c, wireValues []fr.Element) error {
 	// compute the wires and the a, b, c polynomials
 	if len(a) != int(r1cs.NbConstraints) || len(b) != int(r1cs.NbConstraints) || len(c) != int(r1cs.NbConstraints) || len(wireValues) != int(r1cs.NbWires) {
 		return errors.New("invalid input size: len(a, b, c) == r1cs.NbConstraints and len(wireValues) == r1cs.NbWires")
@@ -114,38 +124,72 @@ func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues [
 	wireInstantiated := make([]bool, r1cs.NbWires)
 
 	// instantiate the public/ private inputs
-	// note that currently, there is a convertion from interface{} to fr.Element for each entry in the
-	// assignment map. It can cost a SetBigInt() which converts from Regular ton Montgomery rep (1 mul)
-	// while it's unlikely to be noticeable compared to the FFT and the MultiExp compute times,
-	// there should be a faster (statically typed) path
-	instantiateInputs := func(offset int, inputNames []string) error {
-		for i := 0; i < len(inputNames); i++ {
-			name := inputNames[i]
-			if name == backend.OneWire {
-				wireValues[i+offset].SetOne()
-				wireInstantiated[i+offset] = true
-			} else {
-				if val, ok := assignment[name]; ok {
-					wireValues[i+offset].SetInterface(val)
+	var instantiateInputs func(offset int, inputNames []string, visibility backend.Visibility) error
+	switch _assignment := assignment.(type) {
+	case map[string]interface{}:
+		// note that currently, there is a convertion from interface{} to fr.Element for each entry in the
+		// assignment map. It can cost a SetBigInt() which converts from Regular ton Montgomery rep (1 mul)
+		// while it's unlikely to be noticeable compared to the FFT and the MultiExp compute times,
+		// the typed witness is the faster (statically typed) path
+		instantiateInputs = func(offset int, inputNames []string, visibility backend.Visibility) error {
+			for i := 0; i < len(inputNames); i++ {
+				name := inputNames[i]
+				if name == backend.OneWire {
+					wireValues[i+offset].SetOne()
 					wireInstantiated[i+offset] = true
 				} else {
-					return fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+					if val, ok := _assignment[name]; ok {
+						wireValues[i+offset].SetInterface(val)
+						wireInstantiated[i+offset] = true
+					} else {
+						return fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+					}
 				}
 			}
+			return nil
 		}
-		return nil
+	case *witness.Witness:
+		// the witness values are ordered as the inputs of the R1CS, ONE_WIRE excepted
+		instantiateInputs = func(offset int, inputNames []string, visibility backend.Visibility) error {
+			names, values := _assignment.SecretNames, _assignment.SecretValues
+			if visibility == backend.Public {
+				names, values = _assignment.PublicNames, _assignment.PublicValues
+			}
+			j := 0
+			for i := 0; i < len(inputNames); i++ {
+				if inputNames[i] == backend.OneWire {
+					wireValues[i+offset].SetOne()
+					wireInstantiated[i+offset] = true
+					continue
+				}
+				if j >= len(names) || names[j] != inputNames[i] {
+					return fmt.Errorf("%q: %w", inputNames[i], backend.ErrInputNotSet)
+				}
+				wireValues[i+offset] = values[j]
+				wireInstantiated[i+offset] = true
+				j++
+			}
+			if j != len(names) {
+				return fmt.Errorf("%q: %w", names[j], errUnexpectedInput)
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("%T: %w", assignment, errInvalidAssignment)
 	}
+
 	// instantiate private inputs
-	if r1cs.NbSecretWires != 0 {
+	// (called even when there is none, so that unexpected inputs in a typed witness are reported)
+	{
 		offset := int(r1cs.NbWires - r1cs.NbPublicWires - r1cs.NbSecretWires) // private input start index
-		if err := instantiateInputs(offset, r1cs.SecretWires); err != nil {
+		if err := instantiateInputs(offset, r1cs.SecretWires, backend.Secret); err != nil {
 			return err
 		}
 	}
 	// instantiate public inputs
 	{
 		offset := int(r1cs.NbWires - r1cs.NbPublicWires) // public input start index
-		if err := instantiateInputs(offset, r1cs.PublicWires); err != nil {
+		if err := instantiateInputs(offset, r1cs.PublicWires, backend.Public); err != nil {
 			return err
 		}
 	}
@@ -154,12 +198,25 @@ func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues [
 	// (or sooner, if a constraint is not satisfied)
 	defer r1cs.printLogs(wireValues, wireInstantiated)
 
+	// the hints are solved the first time their wire is needed, their inputs being instantiated then
+	hints := hintSolver{r1cs: r1cs, wireInstantiated: wireInstantiated, wireValues: wireValues}
+	if len(r1cs.Hints) != 0 {
+		hints.wireToHint = make(map[int]int, len(r1cs.Hints))
+		for i := 0; i < len(r1cs.Hints); i++ {
+			hints.wireToHint[r1cs.Hints[i].WireID] = i
+		}
+	}
+
 	// check if there is an inconsistant constraint
 	var check fr.Element
 
 	// Loop through computational constraints (the one wwe need to solve and compute a wire in)
 	for i := 0; i < int(r1cs.NbCOConstraints); i++ {
 
+		if err := hints.solveR1C(&r1cs.Constraints[i]); err != nil {
+			return err
+		}
+
 		// solve the constraint, this will compute the missing wire of the gate
 		r1cs.solveR1C(&r1cs.Constraints[i], wireInstantiated, wireValues)
 
@@ -173,6 +230,13 @@ func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues [
 		}
 	}
 
+	// the remaining hints are only used in assertions
+	for i := 0; i < len(r1cs.Hints); i++ {
+		if err := hints.solve(r1cs.Hints[i].WireID); err != nil {
+			return err
+		}
+	}
+
 	// Loop through the assertions -- here all wireValues should be instantiated
 	// if a[i] * b[i] != c[i]; it means the constraint is not satisfied
 	for i := int(r1cs.NbCOConstraints); i < len(r1cs.Constraints); i++ {
@@ -193,6 +257,71 @@ func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues [
 	return nil
 }
 
+// hintSolver computes the wires of the hints of a R1CS
+type hintSolver struct {
+	r1cs             *R1CS
+	wireToHint       map[int]int // wire id -> index of the hint computing it
+	wireInstantiated []bool
+	wireValues       []fr.Element
+}
+
+// solveR1C solves the hints whose wires appear in r and are not instantiated yet
+func (s *hintSolver) solveR1C(r *r1c.R1C) error {
+	if len(s.wireToHint) == 0 {
+		return nil
+	}
+	for _, l := range [3]r1c.LinearExpression{r.L, r.R, r.O} {
+		for _, t := range l {
+			if err := s.solve(t.VariableID()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// solve computes wireID if it is the wire of a hint that was not solved yet.
+// The inputs of the hint which are themselves hint wires are solved first
+func (s *hintSolver) solve(wireID int) error {
+	if s.wireInstantiated[wireID] {
+		return nil
+	}
+	i, ok := s.wireToHint[wireID]
+	if !ok {
+		return nil
+	}
+	h := &s.r1cs.Hints[i]
+
+	f, ok := hint.Lookup(h.ID)
+	if !ok {
+		return fmt.Errorf("hint %d: %w", h.ID, errHintNotRegistered)
+	}
+
+	inputs := make([]*big.Int, len(h.Inputs))
+	for j := 0; j < len(h.Inputs); j++ {
+		var v fr.Element
+		for _, t := range h.Inputs[j] {
+			if err := s.solve(t.VariableID()); err != nil {
+				return err
+			}
+			if !s.wireInstantiated[t.VariableID()] {
+				return fmt.Errorf("hint %d: %w", h.ID, errHintInputNotSet)
+			}
+			s.r1cs.AddTerm(&v, t, s.wireValues[t.VariableID()])
+		}
+		inputs[j] = new(big.Int)
+		v.ToBigIntRegular(inputs[j])
+	}
+
+	var result big.Int
+	if err := f(gurvy.BN256, inputs, &result); err != nil {
+		return fmt.Errorf("hint %d: %w", h.ID, err)
+	}
+	s.wireValues[wireID].SetBigInt(&result)
+	s.wireInstantiated[wireID] = true
+	return nil
+}
+
 func (r1cs *R1CS) logValue(entry backend.LogEntry, wireValues []fr.Element, wireInstantiated []bool) string {
 	var toResolve []interface{}
 	for j := 0; j < len(entry.ToResolve); j++ {
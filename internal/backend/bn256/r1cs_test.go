@@ -20,6 +20,7 @@ import (
 	bn256backend "github.com/consensys/gnark/internal/backend/bn256"
 
 	"bytes"
+	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/internal/backend/circuits"
 	"github.com/consensys/gurvy"
 	"reflect"
@@ -56,6 +57,15 @@ func TestSerialization(t *testing.T) {
 			if !reflect.DeepEqual(r1cs, &reconstructed) {
 				t.Fatal("round trip serialization failed")
 			}
+
+			// the reconstructed R1CS can be solved (the hints it references are registered)
+			good, err := frontend.ParseWitness(circuit.Good)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reconstructed.IsSolved(good); err != nil {
+				t.Fatal(err)
+			}
 		})
 	}
 }
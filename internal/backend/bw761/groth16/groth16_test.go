@@ -32,6 +32,7 @@ import (
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/internal/backend/circuits"
 	"github.com/consensys/gurvy"
@@ -84,6 +85,168 @@ func TestParsePublicInput(t *testing.T) {
 
 }
 
+func TestBatchVerify(t *testing.T) {
+	const nbProofs = 5
+	circuit := circuits.Circuits["reference_small"]
+	_r1cs := circuit.R1CS.ToR1CS(curve.ID).(*bw761backend.R1CS)
+
+	good, err := frontend.ParseWitness(circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := frontend.ParseWitness(circuit.Bad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := frontend.ParseWitness(circuit.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goodWitness, err := witness.FromCircuit(curve.ID, circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness := goodWitness.Public()
+
+	var pk bw761groth16.ProvingKey
+	var vk bw761groth16.VerifyingKey
+	if err := bw761groth16.Setup(_r1cs, &pk, &vk); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs := make([]*bw761groth16.Proof, nbProofs)
+	inputs := make([]interface{}, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		if proofs[i], err = bw761groth16.Prove(_r1cs, &pk, good, false); err != nil {
+			t.Fatal(err)
+		}
+		// the public inputs are given as a map or as a typed witness
+		if i%2 == 0 {
+			inputs[i] = public
+		} else {
+			inputs[i] = publicWitness
+		}
+	}
+	if err := bw761groth16.BatchVerify(proofs, &vk, inputs, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// forged proofs at indexes 1 and 3
+	for _, i := range []int{1, 3} {
+		if proofs[i], err = bw761groth16.Prove(_r1cs, &pk, bad, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bw761groth16.BatchVerify(proofs, &vk, inputs, false); err == nil {
+		t.Fatal("batch verification with invalid proofs should fail")
+	}
+	err = bw761groth16.BatchVerify(proofs, &vk, inputs, true)
+	batchErr, ok := err.(*backend.BatchVerificationError)
+	if !ok {
+		t.Fatal("expected a *backend.BatchVerificationError, got", err)
+	}
+	if len(batchErr.InvalidProofs) != 2 || batchErr.InvalidProofs[0] != 1 || batchErr.InvalidProofs[1] != 3 {
+		t.Fatal("wrong invalid proofs", batchErr.InvalidProofs)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	const nbProofs = 4
+	circuit := circuits.Circuits["reference_small"]
+	_r1cs := circuit.R1CS.ToR1CS(curve.ID).(*bw761backend.R1CS)
+
+	good, err := frontend.ParseWitness(circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err := frontend.ParseWitness(circuit.Bad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := frontend.ParseWitness(circuit.Public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goodWitness, err := witness.FromCircuit(curve.ID, circuit.Good)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness := goodWitness.Public()
+
+	var pk bw761groth16.ProvingKey
+	var vk bw761groth16.VerifyingKey
+	if err := bw761groth16.Setup(_r1cs, &pk, &vk); err != nil {
+		t.Fatal(err)
+	}
+	var srs bw761groth16.AggregationSRS
+	if err := bw761groth16.AggregationSetup(nbProofs, &srs); err != nil {
+		t.Fatal(err)
+	}
+
+	proofs := make([]*bw761groth16.Proof, nbProofs)
+	inputs := make([]interface{}, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		if proofs[i], err = bw761groth16.Prove(_r1cs, &pk, good, false); err != nil {
+			t.Fatal(err)
+		}
+		// the public inputs are given as a map or as a typed witness
+		if i%2 == 0 {
+			inputs[i] = public
+		} else {
+			inputs[i] = publicWitness
+		}
+	}
+
+	aggregate, err := bw761groth16.Aggregate(proofs, &vk, &srs, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bw761groth16.VerifyAggregate(aggregate, &vk, &srs, inputs); err != nil {
+		t.Fatal(err)
+	}
+
+	// serialization round trip
+	var buf bytes.Buffer
+	written, err := aggregate.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _aggregate bw761groth16.AggregateProof
+	read, err := _aggregate.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatal("number of bytes read and written don't match")
+	}
+	if err := bw761groth16.VerifyAggregate(&_aggregate, &vk, &srs, inputs); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong public inputs
+	wrongInputs := append([]interface{}{}, inputs...)
+	wrongInputs[2] = map[string]interface{}{"Y": 42}
+	if err := bw761groth16.VerifyAggregate(aggregate, &vk, &srs, wrongInputs); err == nil {
+		t.Fatal("verifying an aggregate proof with wrong public inputs should fail")
+	}
+
+	// forged proof
+	if proofs[1], err = bw761groth16.Prove(_r1cs, &pk, bad, true); err != nil {
+		t.Fatal(err)
+	}
+	if aggregate, err = bw761groth16.Aggregate(proofs, &vk, &srs, inputs); err != nil {
+		t.Fatal(err)
+	}
+	if err := bw761groth16.VerifyAggregate(aggregate, &vk, &srs, inputs); err == nil {
+		t.Fatal("verifying an aggregate of an invalid proof should fail")
+	}
+
+	// the SRS is too small
+	if _, err := bw761groth16.Aggregate(append(proofs, proofs...), &vk, &srs, append(inputs, inputs...)); err == nil {
+		t.Fatal("aggregating more proofs than the SRS allows should fail")
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -60,16 +60,19 @@ type VerifyingKey struct {
 	// e(α, β)
 	E curve.GT
 
-	// -[γ]2, -[δ]2
+	// [β]2, -[γ]2, -[δ]2
 	// note: storing GammaNeg and DeltaNeg instead of Gamma and Delta
 	// see proof.Verify() for more details
+	// [β]2 is not needed by Verify, which uses e(α, β), but by verifiers which can't use E
 	G2 struct {
+		Beta               curve.G2Affine
 		GammaNeg, DeltaNeg curve.G2Affine
 	}
 
-	// [Kvk]1
+	// [α]1, [Kvk]1
 	G1 struct {
-		K []curve.G1Affine // The indexes correspond to the public wires
+		Alpha curve.G1Affine
+		K     []curve.G1Affine // The indexes correspond to the public wires
 	}
 }
 
@@ -223,7 +226,8 @@ func Setup(r1cs *bw761backend.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	pk.G2.Beta = g2PointsAff[nbWires+0]
 	pk.G2.Delta = g2PointsAff[nbWires+1]
 
-	// sets vk: -[δ]2, -[γ]2
+	// sets vk: [β]2, -[δ]2, -[γ]2
+	vk.G2.Beta = pk.G2.Beta
 	vk.G2.DeltaNeg = g2PointsAff[nbWires+1]
 	vk.G2.GammaNeg = g2PointsAff[nbWires+2]
 	vk.G2.DeltaNeg.Neg(&vk.G2.DeltaNeg)
@@ -231,6 +235,7 @@ func Setup(r1cs *bw761backend.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 
 	// ---------------------------------------------------------------------------------------------
 	// Pairing: vk.E
+	vk.G1.Alpha = pk.G1.Alpha
 	vk.E, err = curve.Pair([]curve.G1Affine{pk.G1.Alpha}, []curve.G2Affine{pk.G2.Beta})
 	if err != nil {
 		return err
@@ -0,0 +1,422 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gnark DO NOT EDIT
+
+package plonk
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gurvy/bw761/fr"
+
+	curve "github.com/consensys/gurvy/bw761"
+
+	bw761backend "github.com/consensys/gnark/internal/backend/bw761"
+
+	"github.com/consensys/gnark/internal/backend/bw761/fft"
+
+	kzg "github.com/consensys/gnark/crypto/kzg/bw761"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gurvy"
+)
+
+var errSRSTooSmall = errors.New("the SRS is too small for this circuit")
+
+// ProvingKey stores the data needed to generate a proof:
+// * the commitment scheme
+// * the gates of the circuit (wires and selectors)
+// * the permutation
+//
+// Notation follows the PLONK paper https://eprint.iacr.org/2019/953.pdf
+type ProvingKey struct {
+	// Verifying Key is embedded into the proving key (needed by Prove)
+	Vk *VerifyingKey
+
+	// SRS used to commit to the polynomials of the prover
+	SRS *kzg.SRS
+
+	// NbWires is the size of the solution vector [R1CS wires | intermediate wires]
+	NbWires uint64
+
+	// Wires[0][i], Wires[1][i] and Wires[2][i] are the indexes in the solution vector of
+	// the left, right and output wires of the i-th gate
+	Wires [3][]uint64
+
+	// Selectors, in Lagrange basis (evaluations on DomainH)
+	LQl, LQr, LQm, LQo, LQk []fr.Element
+
+	// LS[j] permutation polynomials, in Lagrange basis
+	LS [3][]fr.Element
+
+	// the following slices are not serialized and are (re)computed through pk.computeCanonical()
+
+	// Selectors, in canonical basis
+	Ql, Qr, Qm, Qo, Qk []fr.Element
+
+	// S[j] permutation polynomials, in canonical basis
+	S [3][]fr.Element
+
+	// DomainH is the domain on which the gates are interpolated, DomainNum is used to compute
+	// the quotient polynomial
+	DomainH, DomainNum fft.Domain
+}
+
+// VerifyingKey stores the data needed to verify a proof:
+// * the commitment scheme
+// * the commitments of the selectors and of the permutation
+type VerifyingKey struct {
+	// ordered public input names
+	PublicInputs []string
+
+	// Size circuit, that is the cardinality of DomainH
+	Size    uint64
+	SizeInv fr.Element
+
+	// Generator of DomainH
+	Generator fr.Element
+
+	// Shifter[0], Shifter[1] are the shifters of the cosets used to encode the permutation
+	Shifter [2]fr.Element
+
+	// SRS of the commitment scheme (only the points needed by the verifier)
+	SRS *kzg.SRS
+
+	// Commitments to the selectors
+	Ql, Qr, Qm, Qo, Qk kzg.Digest
+
+	// Commitments to the permutation polynomials
+	S [3]kzg.Digest
+}
+
+// gates is the plonk representation of a R1CS, each gate being
+// qL.l + qR.r + qM.l.r + qO.o + qK == 0
+type gates struct {
+	wires                [3][]uint64
+	ql, qr, qm, qo, qk   []fr.Element
+	nbWires, nbR1CSWires uint64
+	oneWire              uint64
+}
+
+// Setup fills the proving and verifying keys from a R1CS and a SRS.
+//
+// The R1CS is first converted in a list of gates of fan-in 2: each linear expression
+// of the R1CS is split in additions, introducing intermediate wires.
+// The first gates of the circuit constrain the public inputs.
+func Setup(r1cs *bw761backend.R1CS, srs *kzg.SRS, pk *ProvingKey, vk *VerifyingKey) error {
+
+	g := buildGates(r1cs)
+	nbGates := uint64(len(g.ql))
+
+	// domains: DomainH interpolates the gates, DomainNum is large enough to
+	// compute the quotient polynomial (of degree 4n+5)
+	pk.DomainH = *fft.NewDomain(nbGates)
+	n := pk.DomainH.Cardinality
+	pk.DomainNum = *fft.NewDomain(4*n + 6)
+
+	if uint64(srs.Size()) < SizeSRS(r1cs) {
+		return errSRSTooSmall
+	}
+
+	// public part of the circuit
+	vk.PublicInputs = r1cs.PublicWires
+	vk.Size = n
+	vk.SizeInv.SetUint64(n).Inverse(&vk.SizeInv)
+	vk.Generator.Set(&pk.DomainH.Generator)
+	vk.Shifter = computeShifters(n)
+	vk.SRS = &kzg.SRS{G1: []curve.G1Affine{srs.G1[0]}, G2: srs.G2}
+
+	// gates, padded with empty gates up to the size of DomainH
+	pk.Vk = vk
+	pk.SRS = srs
+	pk.NbWires = g.nbWires
+	for j := 0; j < 3; j++ {
+		pk.Wires[j] = make([]uint64, n)
+		copy(pk.Wires[j], g.wires[j])
+		for i := nbGates; i < n; i++ {
+			pk.Wires[j][i] = g.oneWire
+		}
+	}
+	pad := func(q []fr.Element) []fr.Element {
+		res := make([]fr.Element, n)
+		copy(res, q)
+		return res
+	}
+	pk.LQl = pad(g.ql)
+	pk.LQr = pad(g.qr)
+	pk.LQm = pad(g.qm)
+	pk.LQo = pad(g.qo)
+	pk.LQk = pad(g.qk)
+
+	pk.LS = computePermutation(pk.Wires, pk.NbWires, &pk.DomainH, vk.Shifter)
+
+	pk.computeCanonical()
+
+	// commit to the selectors and the permutation
+	var err error
+	toCommit := []*[]fr.Element{&pk.Ql, &pk.Qr, &pk.Qm, &pk.Qo, &pk.Qk, &pk.S[0], &pk.S[1], &pk.S[2]}
+	digests := []*kzg.Digest{&vk.Ql, &vk.Qr, &vk.Qm, &vk.Qo, &vk.Qk, &vk.S[0], &vk.S[1], &vk.S[2]}
+	for i := 0; i < len(toCommit); i++ {
+		if *digests[i], err = kzg.Commit(*toCommit[i], srs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SizeSRS returns the minimal size of the SRS needed to run Setup on the R1CS
+//
+// the largest polynomial the prover commits to is the blinded permutation
+// polynomial, which has n+3 coefficients
+func SizeSRS(r1cs *bw761backend.R1CS) uint64 {
+	g := buildGates(r1cs)
+	n := nextPowerOfTwo(uint64(len(g.ql)))
+	return n + 3
+}
+
+// NewSRS returns a SRS of the correct size to run Setup on the R1CS,
+// sampling the secret at random.
+//
+// This is meant for test purposes only, as the party calling NewSRS knows the secret.
+func NewSRS(r1cs *bw761backend.R1CS) (*kzg.SRS, error) {
+	var alpha fr.Element
+	if _, err := alpha.SetRandom(); err != nil {
+		return nil, err
+	}
+	var bAlpha big.Int
+	alpha.ToBigIntRegular(&bAlpha)
+	return kzg.NewSRS(SizeSRS(r1cs), &bAlpha)
+}
+
+// buildGates converts a R1CS in a list of gates of fan-in 2
+//
+// the solution vector of the gates is [R1CS wires | intermediate wires], where the intermediate
+// wires are introduced to split the linear expressions of the R1CS in sums of two terms.
+func buildGates(r1cs *bw761backend.R1CS) gates {
+
+	var g gates
+	g.nbR1CSWires = r1cs.NbWires
+	g.nbWires = r1cs.NbWires
+
+	var zero, one, minusOne fr.Element
+	one.SetOne()
+	minusOne.Neg(&one)
+
+	addGate := func(l, r, o uint64, ql, qr, qm, qo, qk fr.Element) {
+		g.wires[0] = append(g.wires[0], l)
+		g.wires[1] = append(g.wires[1], r)
+		g.wires[2] = append(g.wires[2], o)
+		g.ql = append(g.ql, ql)
+		g.qr = append(g.qr, qr)
+		g.qm = append(g.qm, qm)
+		g.qo = append(g.qo, qo)
+		g.qk = append(g.qk, qk)
+	}
+
+	// public inputs, including the constant wire ONE_WIRE
+	offset := r1cs.NbWires - r1cs.NbPublicWires
+	for i := 0; i < len(r1cs.PublicWires); i++ {
+		wireID := offset + uint64(i)
+		if r1cs.PublicWires[i] == backend.OneWire {
+			g.oneWire = wireID
+		}
+	}
+	for i := 0; i < len(r1cs.PublicWires); i++ {
+		wireID := offset + uint64(i)
+		addGate(wireID, g.oneWire, g.oneWire, one, zero, zero, zero, zero)
+	}
+
+	// reduce returns (wire, coeff) such that coeff*wire == le, adding
+	// the intermediate gates needed to compute the sum
+	reduce := func(le r1c.LinearExpression) (uint64, fr.Element) {
+		switch len(le) {
+		case 0:
+			return g.oneWire, zero
+		case 1:
+			return uint64(le[0].VariableID()), r1cs.Coefficients[le[0].CoeffID()]
+		}
+		acc := uint64(le[0].VariableID())
+		accCoeff := r1cs.Coefficients[le[0].CoeffID()]
+		for i := 1; i < len(le); i++ {
+			res := g.nbWires
+			g.nbWires++
+			addGate(acc, uint64(le[i].VariableID()), res, accCoeff, r1cs.Coefficients[le[i].CoeffID()], zero, minusOne, zero)
+			acc = res
+			accCoeff = one
+		}
+		return acc, accCoeff
+	}
+
+	// l.cl * r.cr == o.co
+	for _, r1c := range r1cs.Constraints {
+		l, cl := reduce(r1c.L)
+		r, cr := reduce(r1c.R)
+		o, co := reduce(r1c.O)
+
+		var qm, qo fr.Element
+		qm.Mul(&cl, &cr)
+		qo.Neg(&co)
+
+		switch {
+		case r == g.oneWire:
+			// r is a constant, this is a linear gate
+			addGate(l, g.oneWire, o, qm, zero, zero, qo, zero)
+		case l == g.oneWire:
+			addGate(g.oneWire, r, o, zero, qm, zero, qo, zero)
+		default:
+			addGate(l, r, o, zero, zero, qm, qo, zero)
+		}
+	}
+
+	return g
+}
+
+// computeShifters returns two field elements k1, k2 such that H, k1.H, k2.H are
+// disjoint cosets, H being the subgroup of size n
+func computeShifters(n uint64) [2]fr.Element {
+	var res [2]fr.Element
+	bn := new(big.Int).SetUint64(n)
+
+	// x is in H iff x**n == 1
+	var one fr.Element
+	one.SetOne()
+	inH := func(x fr.Element) bool {
+		var xn fr.Element
+		xn.Exp(x, bn)
+		return xn.Equal(&one)
+	}
+
+	var candidate, ratio fr.Element
+	c := uint64(2)
+	for candidate.SetUint64(c); inH(candidate); candidate.SetUint64(c) {
+		c++
+	}
+	res[0] = candidate
+
+	for {
+		c++
+		candidate.SetUint64(c)
+		ratio.Div(&candidate, &res[0])
+		if !inH(candidate) && !inH(ratio) {
+			break
+		}
+	}
+	res[1] = candidate
+
+	return res
+}
+
+// computePermutation returns the permutation polynomials S1, S2, S3 in Lagrange basis
+//
+// the positions of the wires are numbered 0..3n-1 (left wires, right wires, output wires).
+// Positions sharing the same wire form a cycle of the permutation σ, and the
+// position j.n+i is encoded as shifter[j].ωⁱ
+func computePermutation(wires [3][]uint64, nbWires uint64, domain *fft.Domain, shifter [2]fr.Element) [3][]fr.Element {
+
+	n := domain.Cardinality
+
+	// σ: position -> next position using the same wire
+	sigma := make([]uint64, 3*n)
+	last := make([]int64, nbWires) // last position of each wire
+	first := make([]int64, nbWires)
+	for i := 0; i < len(last); i++ {
+		last[i] = -1
+		first[i] = -1
+	}
+	for j := uint64(0); j < 3; j++ {
+		for i := uint64(0); i < n; i++ {
+			pos := j*n + i
+			w := wires[j][i]
+			if last[w] == -1 {
+				first[w] = int64(pos)
+			} else {
+				sigma[last[w]] = pos
+			}
+			last[w] = int64(pos)
+		}
+	}
+	for w := 0; w < len(last); w++ {
+		if last[w] != -1 {
+			sigma[last[w]] = uint64(first[w])
+		}
+	}
+
+	// identity: position -> shifter.ωⁱ
+	id := make([]fr.Element, 3*n)
+	id[0].SetOne()
+	for i := uint64(1); i < n; i++ {
+		id[i].Mul(&id[i-1], &domain.Generator)
+	}
+	for i := uint64(0); i < n; i++ {
+		id[n+i].Mul(&id[i], &shifter[0])
+		id[2*n+i].Mul(&id[i], &shifter[1])
+	}
+
+	var res [3][]fr.Element
+	for j := uint64(0); j < 3; j++ {
+		res[j] = make([]fr.Element, n)
+		for i := uint64(0); i < n; i++ {
+			res[j][i] = id[sigma[j*n+i]]
+		}
+	}
+	return res
+}
+
+// computeCanonical computes the selectors and permutation polynomials in canonical basis
+// from their Lagrange basis representation
+func (pk *ProvingKey) computeCanonical() {
+	pk.Ql = toCanonical(pk.LQl, &pk.DomainH)
+	pk.Qr = toCanonical(pk.LQr, &pk.DomainH)
+	pk.Qm = toCanonical(pk.LQm, &pk.DomainH)
+	pk.Qo = toCanonical(pk.LQo, &pk.DomainH)
+	pk.Qk = toCanonical(pk.LQk, &pk.DomainH)
+	for j := 0; j < 3; j++ {
+		pk.S[j] = toCanonical(pk.LS[j], &pk.DomainH)
+	}
+}
+
+// toCanonical returns the coefficients of the polynomial whose evaluations on domain
+// are p (in natural order). len(p) == domain.Cardinality
+func toCanonical(p []fr.Element, domain *fft.Domain) []fr.Element {
+	res := make([]fr.Element, len(p))
+	copy(res, p)
+	domain.FFTInverse(res, fft.DIF)
+	fft.BitReverse(res)
+	return res
+}
+
+// GetCurveID returns the curveID
+func (pk *ProvingKey) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+// GetCurveID returns the curveID
+func (vk *VerifyingKey) GetCurveID() gurvy.ID {
+	return curve.ID
+}
+
+func nextPowerOfTwo(n uint64) uint64 {
+	p := uint64(1)
+	if (n & (n - 1)) == 0 {
+		return n
+	}
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,65 @@
+package circuits
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type hintCircuit struct {
+	X, Y frontend.Variable
+	R    frontend.Variable `gnark:",public"`
+}
+
+// Define declares R as the remainder of the euclidean division of X by Y
+func (circuit *hintCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	q := cs.NewHint(quotient, circuit.X, circuit.Y)
+	r := cs.NewHint(remainder, circuit.X, circuit.Y)
+
+	// X == q*Y + r, with r < Y
+	cs.AssertIsEqual(cs.Add(cs.Mul(q, circuit.Y), r), circuit.X)
+	cs.AssertIsLessOrEqual(r, cs.Sub(circuit.Y, 1))
+
+	cs.AssertIsEqual(r, circuit.R)
+	return nil
+}
+
+var errDivisionByZero = errors.New("division by zero")
+
+func quotient(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	if inputs[1].Sign() == 0 {
+		return errDivisionByZero
+	}
+	result.Quo(inputs[0], inputs[1])
+	return nil
+}
+
+func remainder(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	if inputs[1].Sign() == 0 {
+		return errDivisionByZero
+	}
+	result.Rem(inputs[0], inputs[1])
+	return nil
+}
+
+func init() {
+	var circuit, good, bad, public hintCircuit
+	r1cs, err := frontend.Compile(gurvy.UNKNOWN, &circuit)
+	if err != nil {
+		panic(err)
+	}
+
+	good.X.Assign(47)
+	good.Y.Assign(5)
+	good.R.Assign(2)
+
+	bad.X.Assign(47)
+	bad.Y.Assign(5)
+	bad.R.Assign(7)
+
+	public.R.Assign(2)
+
+	addEntry("hint", r1cs, &good, &bad, &public)
+}
@@ -0,0 +1,213 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package solidity writes Solidity contracts verifying proofs on the EVM, from the verifying keys
+// of the bn256 backends
+package solidity
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// G1 is a point of G1, with its coordinates in decimal
+type G1 struct {
+	X, Y string
+}
+
+// G2 is a point of G2, with its coordinates in decimal and in the order expected by the
+// precompiles (imaginary part first)
+type G2 struct {
+	X1, X0, Y1, Y0 string
+}
+
+// Input is a public input of the verifyProof function
+type Input struct {
+	Name  string
+	Index int // index in the input array
+	K     int // index of the corresponding point in Groth16Verifier.K
+}
+
+// Groth16Verifier holds the verifying key of a Groth16 verifier contract
+type Groth16Verifier struct {
+	FrModulus          string
+	AlphaNeg           G1
+	Beta               G2
+	GammaNeg, DeltaNeg G2
+	K                  []G1
+	OneWire            int // index of ONE_WIRE in K
+	Inputs             []Input
+}
+
+// WriteGroth16Verifier writes to w a contract verifying Groth16 proofs against v.
+//
+// The contract uses the bn256 precompiles (ecAdd at 0x06, ecMul at 0x07 and ecPairing at 0x08)
+// and exposes
+//
+//	function verifyProof(uint256[2] a, uint256[2][2] b, uint256[2] c, uint256[n] input) returns (bool)
+//
+// where n is len(v.Inputs).
+func WriteGroth16Verifier(w io.Writer, v *Groth16Verifier) error {
+	tmpl, err := template.New("verifier").Parse(groth16Template)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, v)
+}
+
+// Groth16MethodID returns the selector of the verifyProof function with nbInputs public inputs
+func Groth16MethodID(nbInputs int) []byte {
+	signature := "verifyProof(uint256[2],uint256[2][2],uint256[2]"
+	if nbInputs > 0 {
+		signature += fmt.Sprintf(",uint256[%d]", nbInputs)
+	}
+	signature += ")"
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	return h.Sum(nil)[:4]
+}
+
+const groth16Template = `// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by gnark DO NOT EDIT
+
+pragma solidity ^0.8.0;
+
+/// @title Groth16 verifier
+/// @notice verifies Groth16 proofs on the bn256 curve, using the EVM precompiles
+contract Verifier {
+    // scalar field modulus
+    uint256 constant R = {{.FrModulus}};
+
+    // -[α]1
+    uint256 constant ALPHA_NEG_X = {{.AlphaNeg.X}};
+    uint256 constant ALPHA_NEG_Y = {{.AlphaNeg.Y}};
+
+    // [β]2
+    uint256 constant BETA_X_1 = {{.Beta.X1}};
+    uint256 constant BETA_X_0 = {{.Beta.X0}};
+    uint256 constant BETA_Y_1 = {{.Beta.Y1}};
+    uint256 constant BETA_Y_0 = {{.Beta.Y0}};
+
+    // -[γ]2
+    uint256 constant GAMMA_NEG_X_1 = {{.GammaNeg.X1}};
+    uint256 constant GAMMA_NEG_X_0 = {{.GammaNeg.X0}};
+    uint256 constant GAMMA_NEG_Y_1 = {{.GammaNeg.Y1}};
+    uint256 constant GAMMA_NEG_Y_0 = {{.GammaNeg.Y0}};
+
+    // -[δ]2
+    uint256 constant DELTA_NEG_X_1 = {{.DeltaNeg.X1}};
+    uint256 constant DELTA_NEG_X_0 = {{.DeltaNeg.X0}};
+    uint256 constant DELTA_NEG_Y_1 = {{.DeltaNeg.Y1}};
+    uint256 constant DELTA_NEG_Y_0 = {{.DeltaNeg.Y0}};
+
+    // [Kvk]1
+{{- range $i, $k := .K}}
+    uint256 constant K{{$i}}_X = {{$k.X}};
+    uint256 constant K{{$i}}_Y = {{$k.Y}};
+{{- end}}
+
+    /// @notice verifies a proof (a, b, c) against the public inputs
+{{- range .Inputs}}
+    /// @dev input[{{.Index}}]: {{.Name}}
+{{- end}}
+    /// @return true if the proof is valid
+    function verifyProof(
+        uint256[2] calldata a,
+        uint256[2][2] calldata b,
+        uint256[2] calldata c
+{{- if .Inputs}},
+        uint256[{{len .Inputs}}] calldata input
+{{- end}}
+    ) public view returns (bool) {
+        // Σ input[i].[Kvk(i)]1
+        uint256[2] memory x = [K{{.OneWire}}_X, K{{.OneWire}}_Y];
+{{- range .Inputs}}
+        require(input[{{.Index}}] < R, "public input is not in the scalar field");
+        x = ecAdd(x, ecMul([K{{.K}}_X, K{{.K}}_Y], input[{{.Index}}]));
+{{- end}}
+
+        // e(a, b) * e(c, -[δ]2) * e(x, -[γ]2) * e(-[α]1, [β]2) == 1
+        uint256[24] memory pairingInput;
+        pairingInput[0] = a[0];
+        pairingInput[1] = a[1];
+        pairingInput[2] = b[0][0];
+        pairingInput[3] = b[0][1];
+        pairingInput[4] = b[1][0];
+        pairingInput[5] = b[1][1];
+        pairingInput[6] = c[0];
+        pairingInput[7] = c[1];
+        pairingInput[8] = DELTA_NEG_X_1;
+        pairingInput[9] = DELTA_NEG_X_0;
+        pairingInput[10] = DELTA_NEG_Y_1;
+        pairingInput[11] = DELTA_NEG_Y_0;
+        pairingInput[12] = x[0];
+        pairingInput[13] = x[1];
+        pairingInput[14] = GAMMA_NEG_X_1;
+        pairingInput[15] = GAMMA_NEG_X_0;
+        pairingInput[16] = GAMMA_NEG_Y_1;
+        pairingInput[17] = GAMMA_NEG_Y_0;
+        pairingInput[18] = ALPHA_NEG_X;
+        pairingInput[19] = ALPHA_NEG_Y;
+        pairingInput[20] = BETA_X_1;
+        pairingInput[21] = BETA_X_0;
+        pairingInput[22] = BETA_Y_1;
+        pairingInput[23] = BETA_Y_0;
+
+        return pairing(pairingInput);
+    }
+
+    /// @dev calls the ecAdd precompile (0x06)
+    function ecAdd(uint256[2] memory p1, uint256[2] memory p2) internal view returns (uint256[2] memory r) {
+        uint256[4] memory input;
+        input[0] = p1[0];
+        input[1] = p1[1];
+        input[2] = p2[0];
+        input[3] = p2[1];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 0x06, input, 0x80, r, 0x40)
+        }
+        require(success, "ecAdd failed");
+    }
+
+    /// @dev calls the ecMul precompile (0x07)
+    function ecMul(uint256[2] memory p, uint256 s) internal view returns (uint256[2] memory r) {
+        uint256[3] memory input;
+        input[0] = p[0];
+        input[1] = p[1];
+        input[2] = s;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 0x07, input, 0x60, r, 0x40)
+        }
+        require(success, "ecMul failed");
+    }
+
+    /// @dev calls the ecPairing precompile (0x08) on 4 pairs
+    function pairing(uint256[24] memory input) internal view returns (bool) {
+        uint256[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 0x08, input, 0x300, out, 0x20)
+        }
+        require(success, "ecPairing failed");
+        return out[0] == 1;
+    }
+}
+`
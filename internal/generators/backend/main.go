@@ -42,12 +42,18 @@ func main() {
 		wg.Add(1)
 		go func(d templateData) {
 			defer wg.Done()
-			if err := os.MkdirAll(d.RootPath+"groth16", 0700); err != nil {
-				panic(err)
+			for _, dir := range []string{"groth16", "plonk"} {
+				if err := os.MkdirAll(d.RootPath+dir, 0700); err != nil {
+					panic(err)
+				}
 			}
 
 			fftDir := filepath.Join(d.RootPath, "fft")
 			groth16Dir := filepath.Join(d.RootPath, "groth16")
+			mpcsetupDir := filepath.Join(groth16Dir, "mpcsetup")
+			kzgDir := filepath.Join("../../../crypto/kzg/", strings.ToLower(d.Curve))
+			plonkDir := filepath.Join(d.RootPath, "plonk")
+			witnessDir := filepath.Join(d.RootPath, "witness")
 			backendDir := d.RootPath
 			r1csDir := "../../../backend/r1cs/"
 
@@ -88,6 +94,7 @@ func main() {
 				{File: filepath.Join(groth16Dir, "prove.go"), TemplateF: []string{"groth16.prove.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "setup.go"), TemplateF: []string{"groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), TemplateF: []string{"groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "aggregate.go"), TemplateF: []string{"groth16.aggregate.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), TemplateF: []string{"tests/groth16.marshal.go.tmpl", importCurve}},
 			}
 
@@ -101,6 +108,85 @@ func main() {
 			}); err != nil {
 				panic(err)
 			}
+
+			if d.Curve == "BN256" {
+				// the verifying key is exported as a contract using the bn256 precompiles of the EVM
+				if err := bgen.GenerateF(d, "groth16", "./template/zkpschemes/", bavard.EntryF{
+					File:      filepath.Join(groth16Dir, "solidity.go"),
+					TemplateF: []string{"groth16.solidity.go.tmpl", importCurve},
+				}); err != nil {
+					panic(err)
+				}
+
+				if err := bgen.GenerateF(d, "groth16_test", "./template/zkpschemes/", bavard.EntryF{
+					File:      filepath.Join(groth16Dir, "solidity_test.go"),
+					TemplateF: []string{"tests/groth16.solidity.go.tmpl", importCurve},
+				}); err != nil {
+					panic(err)
+				}
+			}
+
+			if err := os.MkdirAll(mpcsetupDir, 0700); err != nil {
+				panic(err)
+			}
+
+			entries = []bavard.EntryF{
+				{File: filepath.Join(mpcsetupDir, "phase1.go"), TemplateF: []string{"phase1.go.tmpl", importCurve}},
+				{File: filepath.Join(mpcsetupDir, "phase2.go"), TemplateF: []string{"phase2.go.tmpl", importCurve}},
+				{File: filepath.Join(mpcsetupDir, "utils.go"), TemplateF: []string{"utils.go.tmpl", importCurve}},
+				{File: filepath.Join(mpcsetupDir, "marshal.go"), TemplateF: []string{"marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(mpcsetupDir, "mpcsetup_test.go"), TemplateF: []string{"tests/mpcsetup.go.tmpl", importCurve}},
+			}
+
+			if err := bgen.GenerateF(d, "mpcsetup", "./template/mpcsetup/", entries...); err != nil {
+				panic(err)
+			}
+
+			if err := os.MkdirAll(kzgDir, 0700); err != nil {
+				panic(err)
+			}
+
+			entries = []bavard.EntryF{
+				{File: filepath.Join(kzgDir, "kzg.go"), TemplateF: []string{"kzg.go.tmpl", importCurve}},
+				{File: filepath.Join(kzgDir, "marshal.go"), TemplateF: []string{"marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(kzgDir, "kzg_test.go"), TemplateF: []string{"tests/kzg.go.tmpl", importCurve}},
+				{File: filepath.Join(kzgDir, "marshal_test.go"), TemplateF: []string{"tests/marshal.go.tmpl", importCurve}},
+			}
+
+			if err := bgen.GenerateF(d, strings.ToLower(d.Curve), "./template/kzg/", entries...); err != nil {
+				panic(err)
+			}
+
+			if err := os.MkdirAll(witnessDir, 0700); err != nil {
+				panic(err)
+			}
+
+			entries = []bavard.EntryF{
+				{File: filepath.Join(witnessDir, "witness.go"), TemplateF: []string{"witness.go.tmpl", importCurve}},
+				{File: filepath.Join(witnessDir, "witness_test.go"), TemplateF: []string{"tests/witness.go.tmpl", importCurve}},
+			}
+
+			if err := bgen.GenerateF(d, "witness", "./template/witness/", entries...); err != nil {
+				panic(err)
+			}
+
+			entries = []bavard.EntryF{
+				{File: filepath.Join(plonkDir, "verify.go"), TemplateF: []string{"plonk.verify.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "prove.go"), TemplateF: []string{"plonk.prove.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "setup.go"), TemplateF: []string{"plonk.setup.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkDir, "marshal.go"), TemplateF: []string{"plonk.marshal.go.tmpl", importCurve}},
+			}
+
+			if err := bgen.GenerateF(d, "plonk", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
+			if err := bgen.GenerateF(d, "plonk_test", "./template/zkpschemes/", bavard.EntryF{
+				File:      filepath.Join(plonkDir, "plonk_test.go"),
+				TemplateF: []string{"tests/plonk.go.tmpl", importCurve},
+			}); err != nil {
+				panic(err)
+			}
 		}(d)
 
 	}
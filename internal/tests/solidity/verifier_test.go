@@ -0,0 +1,154 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package solidity compiles the contracts exported by the bn256 Groth16 backend with solc, and runs
+// them on the EVM of go-ethereum. It is a separate module, so that gnark doesn't depend on
+// go-ethereum.
+//
+// It needs Go 1.25 and solc (0.8.4) in the PATH; run it with
+//
+//	cd internal/tests/solidity && go test -v ./...
+//
+// The test is skipped if solc is missing, except in CI (CI set) where it fails.
+package solidity
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+	Z frontend.Variable `gnark:",public"`
+}
+
+// Define declares x**3 + x + 5 == y, and z == x + y
+func (circuit *cubicCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	x3 := cs.Mul(circuit.X, circuit.X, circuit.X)
+	cs.AssertIsEqual(circuit.Y, cs.Add(x3, circuit.X, 5))
+	cs.AssertIsEqual(circuit.Z, cs.Add(circuit.X, circuit.Y))
+	return nil
+}
+
+func TestExportSolidity(t *testing.T) {
+	solc, err := exec.LookPath("solc")
+	if err != nil {
+		// the contract must run on the EVM in CI
+		if os.Getenv("CI") != "" {
+			t.Fatal("solc is not installed")
+		}
+		t.Skip("solc is not installed")
+	}
+
+	var circuit cubicCircuit
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witness := map[string]interface{}{"X": 3, "Y": 35, "Z": 38}
+	publicWitness := map[string]interface{}{"Y": 35, "Z": 38}
+	proof, err := groth16.Prove(r1cs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compile and deploy the contract
+	dir, err := ioutil.TempDir("", "gnark-solidity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var contract bytes.Buffer
+	if err := vk.ExportSolidity(&contract); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "verifier.sol"), contract.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(solc, "--optimize", "--bin", "-o", dir, filepath.Join(dir, "verifier.sol")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("solc: %v\n%s", err, out)
+	}
+	bin, err := ioutil.ReadFile(filepath.Join(dir, "Verifier.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &runtime.Config{GasLimit: 10_000_000}
+	_, address, _, err := runtime.Create(common.FromHex(string(bin)), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifyProof returns a bool, or reverts
+	verify := func(calldata []byte) bool {
+		ret, _, err := runtime.Call(address, calldata, cfg)
+		if err != nil {
+			return false
+		}
+		return new(big.Int).SetBytes(ret).Cmp(big.NewInt(1)) == 0
+	}
+	calldata := func(proof groth16.Proof, publicWitness map[string]interface{}) []byte {
+		res, err := groth16.FormatCalldata(proof, vk, publicWitness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if !verify(calldata(proof, publicWitness)) {
+		t.Fatal("the contract rejects a valid proof")
+	}
+
+	// wrong public input
+	if verify(calldata(proof, map[string]interface{}{"Y": 35, "Z": 39})) {
+		t.Fatal("the contract accepts a proof with a wrong public input")
+	}
+
+	// the proof of another witness
+	other, err := groth16.Prove(r1cs, pk, map[string]interface{}{"X": 2, "Y": 15, "Z": 17})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verify(calldata(other, publicWitness)) {
+		t.Fatal("the contract accepts the proof of another witness")
+	}
+
+	// a public input which is not reduced modulo r
+	tampered := calldata(proof, publicWitness)
+	y := new(big.Int).SetBytes(tampered[len(tampered)-64 : len(tampered)-32])
+	y.Add(y, fr.Modulus())
+	copy(tampered[len(tampered)-64:len(tampered)-32], common.LeftPadBytes(y.Bytes(), 32))
+	if verify(tampered) {
+		t.Fatal("the contract accepts a public input which is not reduced")
+	}
+}
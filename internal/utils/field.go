@@ -0,0 +1,41 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"math/big"
+
+	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+// ScalarField returns the modulus of the scalar field of curveID, or nil if the curve is unknown
+func ScalarField(curveID gurvy.ID) *big.Int {
+	switch curveID {
+	case gurvy.BLS377:
+		return fr_bls377.Modulus()
+	case gurvy.BLS381:
+		return fr_bls381.Modulus()
+	case gurvy.BN256:
+		return fr_bn256.Modulus()
+	case gurvy.BW761:
+		return fr_bw761.Modulus()
+	default:
+		return nil
+	}
+}
@@ -11,7 +11,8 @@ import (
 	"github.com/consensys/gnark/backend"
 )
 
-// TODO this is deprecated, we might need a type Witness = map[string]interface{}
+// note: these map based witness helpers are kept for backward compatibility,
+// gnark/backend/witness provides curve-typed witnesses with binary and JSON encodings
 
 // WriteWitness serialize variable map[name]value into writer
 //
@@ -21,6 +22,8 @@ import (
 // the resulting format is human readable (JSON)
 //
 // big.Int are serialized in hexadecimal strings
+//
+// Deprecated: use witness.Witness (gnark/backend/witness) MarshalJSON or WriteTo instead
 func WriteWitness(writer io.Writer, from map[string]interface{}) error {
 	encoder := json.NewEncoder(writer)
 	encoder.SetIndent("", "    ")
@@ -46,6 +49,8 @@ func WriteWitness(writer io.Writer, from map[string]interface{}) error {
 // keys being variable names and interface{} being big.Int
 //
 // big.Int values in files can be in base10 or base16 strings
+//
+// Deprecated: use witness.Witness (gnark/backend/witness) UnmarshalJSON or ReadFrom instead
 func ReadWitness(reader io.Reader, into map[string]interface{}) error {
 	decoder := json.NewDecoder(reader)
 
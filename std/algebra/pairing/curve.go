@@ -0,0 +1,172 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pairing implements the optimal ate pairings of BN256 and BLS381 in a circuit whose
+// native field is not the base field of the curve, with emulated arithmetic (see std/math/emulated).
+//
+// Fp12 is not built as a tower of extensions, but directly as Fp[w]/(w^12 - 2d*w^6 + d^2 + 1),
+// the tower Fp2 = Fp[u]/(u^2 + 1), Fp6 = Fp2[v]/(v^3 - (d+u)), Fp12 = Fp6[w]/(w^2 - v) of gurvy
+// being mapped with u = w^6 - d: a product in Fp12 then costs 12 reductions modulo p.
+package pairing
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gurvy/utils"
+)
+
+// Curve stores the parameters of a pairing-friendly curve with embedding degree 12, whose sextic
+// twist is defined over Fp2 = Fp[u]/(u^2 + 1)
+type Curve struct {
+	G1 weierstrass.Curve
+
+	d     int64 // the non residue of the twist is d + u = w^6
+	mType bool  // the twist is y^2 = x^3 + b*(d+u) (M-type), or y^2 = x^3 + b/(d+u) (D-type)
+	bn    bool  // BN curve (the Miller loop ends with lines through the Frobenius of Q)
+
+	loop    []int8 // digits of the Miller loop (least significant first)
+	seed    []int8 // NAF of the absolute value of the seed x of the curve, for the final exponentiation
+	negSeed bool   // x < 0
+
+	// (d+u)^((p^k-1)/3) and (d+u)^((p^k-1)/2) for k = 1, 2: coordinates of π^k on the twist (BN only)
+	frobX, frobY [2][2]big.Int
+
+	// the twist is y^2 = x^3 + twistB, and φ(x, y) = (ω*x, y) is an endomorphism of G1 if ω is
+	// thirdRootOneG1, of the twist if ω is thirdRootOneG2 (cube roots of 1 in Fp, as in gurvy)
+	twistB                         [2]big.Int
+	thirdRootOneG1, thirdRootOneG2 big.Int
+}
+
+// BN256 returns the parameters of the optimal ate pairing of BN256
+func BN256() Curve {
+	curve := Curve{G1: weierstrass.BN256(), d: 9, bn: true}
+
+	// the Miller loop is on 6x+2
+	var x, loop big.Int
+	x.SetString("4965661367192848881", 10)
+	loop.Mul(&x, big.NewInt(6)).Add(&loop, big.NewInt(2))
+	curve.loop = naf(&loop)
+	curve.seed = naf(&x)
+
+	p := &curve.G1.Fp.Modulus
+	xi := [2]big.Int{*big.NewInt(curve.d), *big.NewInt(1)}
+	var pk, e big.Int
+	pk.Set(p)
+	for k := 0; k < 2; k++ {
+		e.Sub(&pk, big.NewInt(1)).Div(&e, big.NewInt(3))
+		curve.frobX[k] = fp2Exp(xi, &e, p)
+		e.Sub(&pk, big.NewInt(1)).Div(&e, big.NewInt(2))
+		curve.frobY[k] = fp2Exp(xi, &e, p)
+		pk.Mul(&pk, p)
+	}
+
+	curve.setTwist("2203960485148121921418603742825762020974279258880205651966")
+	return curve
+}
+
+// BLS381 returns the parameters of the optimal ate pairing of BLS381
+func BLS381() Curve {
+	curve := Curve{G1: weierstrass.BLS381(), d: 1, mType: true, negSeed: true}
+
+	// the Miller loop is on |x|, and the result is conjugated
+	var x big.Int
+	x.SetString("15132376222941642752", 10)
+	curve.loop = make([]int8, x.BitLen())
+	for i := range curve.loop {
+		curve.loop[i] = int8(x.Bit(i))
+	}
+	curve.seed = naf(&x)
+
+	curve.setTwist("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436")
+	return curve
+}
+
+// setTwist sets the coefficient b of the twist and the cube roots of 1 of the endomorphisms, from
+// the one of G1 (the one of the twist is its square)
+func (curve *Curve) setTwist(thirdRootOneG1 string) {
+	p := &curve.G1.Fp.Modulus
+	curve.thirdRootOneG1.SetString(thirdRootOneG1, 10)
+	curve.thirdRootOneG2.Mul(&curve.thirdRootOneG1, &curve.thirdRootOneG1).Mod(&curve.thirdRootOneG2, p)
+
+	// b*(d+u) (M-type), or b/(d+u) = b*(d-u)/(d^2+1) (D-type)
+	b, d := &curve.G1.B, big.NewInt(curve.d)
+	if curve.mType {
+		curve.twistB[0].Mul(b, d).Mod(&curve.twistB[0], p)
+		curve.twistB[1].Set(b)
+		return
+	}
+	var c big.Int
+	c.SetInt64(curve.d*curve.d+1).ModInverse(&c, p).Mul(&c, b)
+	curve.twistB[0].Mul(&c, d).Mod(&curve.twistB[0], p)
+	curve.twistB[1].Neg(&c).Mod(&curve.twistB[1], p)
+}
+
+// G1Placeholder returns a point of G1 whose coordinates are not assigned, to be used in the
+// definition of a circuit
+func (curve Curve) G1Placeholder() weierstrass.Point {
+	return curve.G1.Placeholder()
+}
+
+// G2Placeholder returns a point of G2 whose coordinates are not assigned, to be used in the
+// definition of a circuit
+func (curve Curve) G2Placeholder() G2Affine {
+	return G2Affine{X: curve.G1.Fp.ExtPlaceholder(2), Y: curve.G1.Fp.ExtPlaceholder(2)}
+}
+
+// GTPlaceholder returns an element of GT whose coordinates are not assigned, to be used in the
+// definition of a circuit
+func (curve Curve) GTPlaceholder() GT {
+	return GT(curve.G1.Fp.ExtPlaceholder(12))
+}
+
+// poly returns the polynomial of Fp12 = Fp[w]/(w^12 - 2d*w^6 + d^2 + 1) (see emulated.ExtField)
+func (curve Curve) poly() []int64 {
+	poly := make([]int64, 12)
+	poly[0] = -(curve.d*curve.d + 1)
+	poly[6] = 2 * curve.d
+	return poly
+}
+
+// -------------------------------------------------------------------------------------------------
+// native arithmetic, for the constants
+
+// naf returns the non adjacent form of x > 0 (least significant digit first)
+func naf(x *big.Int) []int8 {
+	digits := make([]int8, x.BitLen()+1)
+	n := utils.NafDecomposition(x, digits)
+	return digits[:n]
+}
+
+// fp2Exp returns a^e in Fp[u]/(u^2 + 1)
+func fp2Exp(a [2]big.Int, e, p *big.Int) [2]big.Int {
+	mul := func(x, y [2]big.Int) [2]big.Int {
+		var res [2]big.Int
+		var tmp big.Int
+		res[0].Mul(&x[0], &y[0]).Sub(&res[0], tmp.Mul(&x[1], &y[1])).Mod(&res[0], p)
+		res[1].Mul(&x[0], &y[1]).Add(&res[1], tmp.Mul(&x[1], &y[0])).Mod(&res[1], p)
+		return res
+	}
+	var res [2]big.Int
+	res[0].SetUint64(1)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = mul(res, res)
+		if e.Bit(i) == 1 {
+			res = mul(res, a)
+		}
+	}
+	return res
+}
@@ -0,0 +1,145 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pairing
+
+import (
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// The checks below use the affine formulas, whose constraints are not satisfiable if a point is
+// added to itself or to its opposite, or if a point of order 2 is doubled: the points which pass
+// them satisfy the equations of gurvy's IsInSubGroup. For the points of the subgroup of order r,
+// this never happens, the multiples involved being fixed and much smaller than r.
+
+// AssertIsInG1 asserts that P is in G1: its coordinates, which may be part of the witness, are
+// range checked, P is on the curve and, as in gurvy, x^2*φ(P) = -P on BLS381 (the cofactor of BN256
+// is 1).
+func (pr *Pairing) AssertIsInG1(P weierstrass.Point) {
+	P.MustBeOnCurve(pr.cs, pr.curve.G1)
+	if pr.curve.bn {
+		return
+	}
+
+	fp := pr.fp
+	phi := weierstrass.Point{X: fp.MulConstant(P.X, &pr.curve.thirdRootOneG1), Y: P.Y}
+	res := pr.g1MulBySeed(pr.g1MulBySeed(phi))
+	fp.AssertIsEqual(res.X, P.X)
+	fp.AssertIsEqual(res.Y, fp.Neg(P.Y))
+}
+
+// AssertIsInG2 asserts that Q is in G2: its coordinates, which may be part of the witness, are
+// range checked, Q is on the twist and, as in gurvy, [12x^2+4x]φ(Q) = [4x+2]Q on BN256,
+// x^2*φ(Q) = -Q on BLS381.
+func (pr *Pairing) AssertIsInG2(Q G2Affine) {
+	fp, fp2 := pr.fp, pr.fp2
+	fp2.RangeCheck(Q.X)
+	fp2.RangeCheck(Q.Y)
+
+	// y^2 = x^3 + b
+	b := fp2.Constant(&pr.curve.twistB[0], &pr.curve.twistB[1])
+	fp2.AssertIsEqual(fp2.Square(Q.Y), fp2.Add(fp2.Mul(fp2.Square(Q.X), Q.X), b))
+
+	omega := &pr.curve.thirdRootOneG2
+	phi := G2Affine{
+		X: emulated.ExtElement{Coeffs: []emulated.Element{
+			fp.MulConstant(Q.X.Coeffs[0], omega), fp.MulConstant(Q.X.Coeffs[1], omega),
+		}},
+		Y: Q.Y,
+	}
+	negQ := G2Affine{X: Q.X, Y: fp2.Neg(Q.Y)}
+	if !pr.curve.bn {
+		pr.g2AssertIsEqual(pr.g2MulBySeed(pr.g2MulBySeed(phi)), negQ)
+		return
+	}
+
+	// 2*([x](2*(3[x]φ(Q) + φ(Q) - Q)) - Q) = [12x^2+4x]φ(Q) - [4x+2]Q, and the twist has no point
+	// of order 2
+	xPhi := pr.g2MulBySeed(phi)
+	res := pr.g2Add(pr.g2Double(xPhi), xPhi)
+	res = pr.g2Add(pr.g2Add(res, phi), negQ)
+	res = pr.g2MulBySeed(pr.g2Double(res))
+	pr.g2AssertIsEqual(res, Q)
+}
+
+// RangeCheckGT range checks the coordinates of e, which may be part of the witness. An element
+// which is only compared with pairings (as e(α, β) in a groth16 verifying key) needs no subgroup
+// check: if it is not in GT, it is never equal to them.
+func (pr *Pairing) RangeCheckGT(e GT) {
+	pr.fp12.RangeCheck(emulated.ExtElement(e))
+}
+
+// g1MulBySeed returns [|x|]P, with the NAF of the seed
+func (pr *Pairing) g1MulBySeed(P weierstrass.Point) weierstrass.Point {
+	fp, curve := pr.fp, pr.curve.G1
+	var negP weierstrass.Point
+	negP.Neg(pr.cs, &P, curve)
+
+	res := P
+	for i := len(pr.curve.seed) - 2; i >= 0; i-- {
+		res.Double(pr.cs, &res, curve)
+		addend := &P
+		switch pr.curve.seed[i] {
+		case 0:
+			continue
+		case -1:
+			addend = &negP
+		}
+
+		// res is not ±addend (the difference of the abscissas is invertible), so that the slope
+		// computed by Add is constrained
+		fp.Inverse(fp.Sub(addend.X, res.X))
+		res.Add(pr.cs, &res, addend, curve)
+	}
+	return res
+}
+
+// g2MulBySeed returns [|x|]Q, with the NAF of the seed
+func (pr *Pairing) g2MulBySeed(Q G2Affine) G2Affine {
+	negQ := G2Affine{X: Q.X, Y: pr.fp2.Neg(Q.Y)}
+
+	res := Q
+	for i := len(pr.curve.seed) - 2; i >= 0; i-- {
+		res = pr.g2Double(res)
+		switch pr.curve.seed[i] {
+		case 1:
+			res = pr.g2Add(res, Q)
+		case -1:
+			res = pr.g2Add(res, negQ)
+		}
+	}
+	return res
+}
+
+// g2Double returns 2T
+func (pr *Pairing) g2Double(T G2Affine) G2Affine {
+	return pr.fromSlope(T, T.X, pr.tangent(T))
+}
+
+// g2Add returns T+Q: unlike add, which computes the slope with a division, the constraints are not
+// satisfiable if T = ±Q
+func (pr *Pairing) g2Add(T, Q G2Affine) G2Affine {
+	fp2 := pr.fp2
+	l := fp2.Mul(fp2.Sub(Q.Y, T.Y), fp2.Inverse(fp2.Sub(Q.X, T.X)))
+	return pr.fromSlope(T, Q.X, l)
+}
+
+// g2AssertIsEqual asserts that T = Q
+func (pr *Pairing) g2AssertIsEqual(T, Q G2Affine) {
+	pr.fp2.AssertIsEqual(T.X, Q.X)
+	pr.fp2.AssertIsEqual(T.Y, Q.Y)
+}
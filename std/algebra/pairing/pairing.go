@@ -0,0 +1,417 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pairing
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gurvy/bls381"
+	"github.com/consensys/gurvy/bn256"
+)
+
+// G2Affine is a point of the twist in affine coordinates, which are elements of Fp2 (the point at
+// infinity is not representable)
+type G2Affine struct {
+	X, Y emulated.ExtElement
+}
+
+// Assign assigns the coordinates of q to those of point (*bn256.G2Affine or *bls381.G2Affine)
+func (q *G2Affine) Assign(point interface{}, curve Curve) {
+	var coords []interface{}
+	switch p := point.(type) {
+	case *bn256.G2Affine:
+		coords = []interface{}{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1}
+	case *bls381.G2Affine:
+		coords = []interface{}{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1}
+	default:
+		panic("pairing: unsupported point type")
+	}
+	q.X.Assign(coords[:2], curve.G1.Fp)
+	q.Y.Assign(coords[2:], curve.G1.Fp)
+}
+
+// GT is an element of Fp12 = Fp[w]/(w^12 - 2d*w^6 + d^2 + 1), given by its coordinates in the
+// basis 1, w, ..., w^11
+type GT emulated.ExtElement
+
+// Assign assigns e to v (*bn256.GT or *bls381.GT), mapped from the tower of extensions of gurvy
+func (e *GT) Assign(v interface{}, curve Curve) {
+	// coordinates of the Fp2 coefficients of 1, w, ..., w^5 (in Fp6 = Fp2[v], v = w^2)
+	var tower []interface{}
+	switch x := v.(type) {
+	case *bn256.GT:
+		tower = []interface{}{
+			&x.C0.B0.A0, &x.C0.B0.A1, &x.C1.B0.A0, &x.C1.B0.A1, &x.C0.B1.A0, &x.C0.B1.A1,
+			&x.C1.B1.A0, &x.C1.B1.A1, &x.C0.B2.A0, &x.C0.B2.A1, &x.C1.B2.A0, &x.C1.B2.A1,
+		}
+	case *bls381.GT:
+		tower = []interface{}{
+			&x.C0.B0.A0, &x.C0.B0.A1, &x.C1.B0.A0, &x.C1.B0.A1, &x.C0.B1.A0, &x.C0.B1.A1,
+			&x.C1.B1.A0, &x.C1.B1.A1, &x.C0.B2.A0, &x.C0.B2.A1, &x.C1.B2.A0, &x.C1.B2.A1,
+		}
+	default:
+		panic("pairing: unsupported element type")
+	}
+
+	// (a0 + a1*u) * w^i = (a0 - d*a1) * w^i + a1 * w^(i+6)
+	coeffs := make([]interface{}, 12)
+	for i := 0; i < 6; i++ {
+		a0 := backend.FromInterface(tower[2*i])
+		a1 := backend.FromInterface(tower[2*i+1])
+		var c big.Int
+		c.Mul(&a1, big.NewInt(curve.d)).Sub(&a0, &c)
+		coeffs[i] = c
+		coeffs[i+6] = a1
+	}
+	(*emulated.ExtElement)(e).Assign(coeffs, curve.G1.Fp)
+}
+
+// Pairing computes pairings in a constraint system
+type Pairing struct {
+	cs    *frontend.ConstraintSystem
+	curve Curve
+	fp    *emulated.Field
+	fp2   *emulated.ExtField
+	fp12  *emulated.ExtField
+
+	lineSupport []int // nonzero coordinates of the lines
+}
+
+// NewPairing returns the pairing of curve in cs
+func NewPairing(cs *frontend.ConstraintSystem, curve Curve) *Pairing {
+	pr := &Pairing{
+		cs:    cs,
+		curve: curve,
+		fp2:   emulated.NewExtField(cs, curve.G1.Fp, []int64{-1, 0}),
+		fp12:  emulated.NewExtField(cs, curve.G1.Fp, curve.poly()),
+	}
+	pr.fp = pr.fp2.Base
+	if curve.mType {
+		pr.lineSupport = []int{1, 3, 4, 7, 9}
+	} else {
+		pr.lineSupport = []int{0, 1, 3, 7, 9}
+	}
+	return pr
+}
+
+// Pair returns the product of the pairings e(P[i], Q[i])
+func (pr *Pairing) Pair(P []weierstrass.Point, Q []G2Affine) GT {
+	return pr.FinalExponentiation(pr.MillerLoop(P, Q))
+}
+
+// MillerLoop returns the product of the Miller loops of the pairs (P[i], Q[i]), which share their
+// squarings. The result is defined up to a factor which the final exponentiation eliminates.
+func (pr *Pairing) MillerLoop(P []weierstrass.Point, Q []G2Affine) GT {
+	if len(P) == 0 || len(P) != len(Q) {
+		panic("pairing: the numbers of points of G1 and G2 differ")
+	}
+	fp2, fp12 := pr.fp2, pr.fp12
+
+	T := make([]G2Affine, len(Q))
+	negQ := make([]G2Affine, len(Q))
+	for k := range Q {
+		T[k] = Q[k]
+		negQ[k] = G2Affine{X: Q[k].X, Y: fp2.Neg(Q[k].Y)}
+	}
+
+	// f is the first line until then
+	var f emulated.ExtElement
+	started := false
+	mulLine := func(l emulated.ExtElement) {
+		if started {
+			f = fp12.MulSparse(f, l, pr.lineSupport)
+		} else {
+			f, started = l, true
+		}
+	}
+
+	loop := pr.curve.loop
+	for i := len(loop) - 2; i >= 0; i-- {
+		if started {
+			f = fp12.Square(f)
+		}
+		for k := range T {
+			var l emulated.ExtElement
+			T[k], l = pr.double(T[k], &P[k])
+			mulLine(l)
+		}
+		for k := range T {
+			var l emulated.ExtElement
+			switch loop[i] {
+			case 1:
+				T[k], l = pr.add(T[k], Q[k], &P[k])
+			case -1:
+				T[k], l = pr.add(T[k], negQ[k], &P[k])
+			default:
+				continue
+			}
+			mulLine(l)
+		}
+	}
+
+	if pr.curve.bn {
+		// lines through T and π(Q), then through T + π(Q) and -π²(Q)
+		for k := range T {
+			var l emulated.ExtElement
+			T[k], l = pr.add(T[k], pr.frobenius(Q[k], 1), &P[k])
+			mulLine(l)
+			q2 := pr.frobenius(Q[k], 2)
+			q2.Y = fp2.Neg(q2.Y)
+			_, l = pr.add(T[k], q2, &P[k])
+			mulLine(l)
+		}
+	}
+
+	if pr.curve.negSeed {
+		f = pr.conjugate(f)
+	}
+	return GT(f)
+}
+
+// FinalExponentiation returns f^((p^12-1)/r)
+func (pr *Pairing) FinalExponentiation(f GT) GT {
+	fp12 := pr.fp12
+	z := emulated.ExtElement(f)
+
+	// easy part: z^((p^6-1)(p^2+1))
+	z = fp12.Div(pr.conjugate(z), z)
+	z = fp12.Mul(fp12.Frobenius(z, 2), z)
+
+	// hard part, as in gurvy (z is now in the cyclotomic subgroup, where the inverse is the conjugate)
+	if pr.curve.bn {
+		return GT(pr.hardPartBN(z))
+	}
+	return GT(pr.hardPartBLS(z))
+}
+
+// AssertIsEqual asserts that a = b
+func (pr *Pairing) AssertIsEqual(a, b GT) {
+	pr.fp12.AssertIsEqual(emulated.ExtElement(a), emulated.ExtElement(b))
+}
+
+// hardPartBN returns z^((p^4-p^2+1)/r) (https://eprint.iacr.org/2008/490.pdf)
+func (pr *Pairing) hardPartBN(z emulated.ExtElement) emulated.ExtElement {
+	fp12 := pr.fp12
+	var mt [4]emulated.ExtElement // mt[i] = z^(x^i)
+	mt[0] = z
+	mt[1] = pr.expt(mt[0])
+	mt[2] = pr.expt(mt[1])
+	mt[3] = pr.expt(mt[2])
+
+	var y [7]emulated.ExtElement
+	y[1] = pr.conjugate(mt[0])
+	y[4] = mt[1]
+	y[5] = pr.conjugate(mt[2])
+	y[6] = mt[3]
+
+	for i := range mt {
+		mt[i] = fp12.Frobenius(mt[i], 1)
+	}
+	y[0] = mt[0]
+	y[3] = pr.conjugate(mt[1])
+	y[4] = pr.conjugate(fp12.Mul(y[4], mt[2]))
+	y[6] = pr.conjugate(fp12.Mul(y[6], mt[3]))
+
+	mt[0] = fp12.Frobenius(mt[0], 1)
+	mt[2] = fp12.Frobenius(mt[2], 1)
+	y[0] = fp12.Mul(y[0], mt[0])
+	y[2] = mt[2]
+	mt[0] = fp12.Frobenius(mt[0], 1)
+	y[0] = fp12.Mul(y[0], mt[0])
+
+	// addition chain
+	t0 := fp12.Mul(fp12.Mul(fp12.Square(y[6]), y[4]), y[5])
+	t1 := fp12.Mul(fp12.Mul(y[3], y[5]), t0)
+	t0 = fp12.Mul(t0, y[2])
+	t1 = fp12.Square(fp12.Mul(fp12.Square(t1), t0))
+	t0 = fp12.Square(fp12.Mul(t1, y[1]))
+	t1 = fp12.Mul(t1, y[0])
+	return fp12.Mul(t0, t1)
+}
+
+// hardPartBLS returns z^((p^4-p^2+1)/r), up to a power coprime to r (Alg.2 of
+// https://eprint.iacr.org/2016/130.pdf)
+func (pr *Pairing) hardPartBLS(z emulated.ExtElement) emulated.ExtElement {
+	fp12 := pr.fp12
+	var t [4]emulated.ExtElement
+	t[0] = fp12.Square(z)
+	t[1] = pr.expt(t[0])
+	t[2] = pr.exptHalf(t[1])
+	t[3] = pr.conjugate(z)
+	t[1] = pr.conjugate(fp12.Mul(t[1], t[3]))
+	t[1] = fp12.Mul(t[1], t[2])
+	t[2] = pr.expt(t[1])
+	t[3] = pr.expt(t[2])
+	t[1] = pr.conjugate(t[1])
+	t[3] = fp12.Mul(t[1], t[3])
+	t[1] = pr.conjugate(t[1])
+	t[1] = fp12.Frobenius(t[1], 3)
+	t[2] = fp12.Frobenius(t[2], 2)
+	t[1] = fp12.Mul(t[1], t[2])
+	t[2] = pr.expt(t[3])
+	t[2] = fp12.Mul(fp12.Mul(t[2], t[0]), z)
+	t[1] = fp12.Mul(t[1], t[2])
+	t[2] = fp12.Frobenius(t[3], 1)
+	return fp12.Mul(t[1], t[2])
+}
+
+// expt returns z^x, x being the seed of the curve (z must be in the cyclotomic subgroup)
+func (pr *Pairing) expt(z emulated.ExtElement) emulated.ExtElement {
+	return pr.cyclotomicExp(z, pr.curve.seed)
+}
+
+// exptHalf returns z^(x/2), x being the (even) seed of the curve (z must be in the cyclotomic
+// subgroup)
+func (pr *Pairing) exptHalf(z emulated.ExtElement) emulated.ExtElement {
+	return pr.cyclotomicExp(z, pr.curve.seed[1:])
+}
+
+// cyclotomicExp returns z^(±e), e being given by its NAF digits and the sign being that of the
+// seed (z must be in the cyclotomic subgroup)
+func (pr *Pairing) cyclotomicExp(z emulated.ExtElement, naf []int8) emulated.ExtElement {
+	fp12 := pr.fp12
+	zInv := pr.conjugate(z)
+	res := z
+	for i := len(naf) - 2; i >= 0; i-- {
+		res = fp12.Square(res)
+		switch naf[i] {
+		case 1:
+			res = fp12.Mul(res, z)
+		case -1:
+			res = fp12.Mul(res, zInv)
+		}
+	}
+	if pr.curve.negSeed {
+		res = pr.conjugate(res)
+	}
+	return res
+}
+
+// conjugate returns z^(p^6), which is w -> -w
+func (pr *Pairing) conjugate(z emulated.ExtElement) emulated.ExtElement {
+	res := emulated.ExtElement{Coeffs: make([]emulated.Element, len(z.Coeffs))}
+	for i := range z.Coeffs {
+		if i%2 == 1 {
+			res.Coeffs[i] = pr.fp.Neg(z.Coeffs[i])
+		} else {
+			res.Coeffs[i] = z.Coeffs[i]
+		}
+	}
+	return res
+}
+
+// frobenius returns π^k(Q) (k = 1, 2), mapped on the twist (D-type)
+func (pr *Pairing) frobenius(Q G2Affine, k int) G2Affine {
+	fp2 := pr.fp2
+	frobX := fp2.Constant(&pr.curve.frobX[k-1][0], &pr.curve.frobX[k-1][1])
+	frobY := fp2.Constant(&pr.curve.frobY[k-1][0], &pr.curve.frobY[k-1][1])
+	x, y := Q.X, Q.Y
+	if k == 1 {
+		x, y = pr.conjugateFp2(x), pr.conjugateFp2(y)
+	}
+	return G2Affine{X: fp2.Mul(x, frobX), Y: fp2.Mul(y, frobY)}
+}
+
+// conjugateFp2 returns a^p, a being in Fp2
+func (pr *Pairing) conjugateFp2(a emulated.ExtElement) emulated.ExtElement {
+	return emulated.ExtElement{Coeffs: []emulated.Element{a.Coeffs[0], pr.fp.Neg(a.Coeffs[1])}}
+}
+
+// double returns 2T and the line tangent at T, evaluated at P
+func (pr *Pairing) double(T G2Affine, P *weierstrass.Point) (G2Affine, emulated.ExtElement) {
+	l := pr.tangent(T)
+	return pr.fromSlope(T, T.X, l), pr.line(T, l, P)
+}
+
+// tangent returns the slope of the tangent at T (T must be on the twist)
+func (pr *Pairing) tangent(T G2Affine) emulated.ExtElement {
+	fp2 := pr.fp2
+
+	// l = 3x^2 / 2y
+	square := fp2.Square(T.X)
+	num := fp2.Add(fp2.Add(square, square), square)
+	den := fp2.Add(T.Y, T.Y)
+	return fp2.Div(num, den)
+}
+
+// add returns T+Q and the line through T and Q, evaluated at P (T must not be equal to Q or -Q)
+func (pr *Pairing) add(T, Q G2Affine, P *weierstrass.Point) (G2Affine, emulated.ExtElement) {
+	fp2 := pr.fp2
+
+	// l = (yQ - yT) / (xQ - xT)
+	l := fp2.Div(fp2.Sub(Q.Y, T.Y), fp2.Sub(Q.X, T.X))
+
+	return pr.fromSlope(T, Q.X, l), pr.line(T, l, P)
+}
+
+// fromSlope returns T + Q, l being the slope of the line through T and Q
+func (pr *Pairing) fromSlope(T G2Affine, xQ, l emulated.ExtElement) G2Affine {
+	fp2 := pr.fp2
+
+	// x = l^2 - xT - xQ, y = l(xT - x) - yT
+	x := fp2.Sub(fp2.Sub(fp2.Square(l), T.X), xQ)
+	y := fp2.Sub(fp2.Mul(l, fp2.Sub(T.X, x)), T.Y)
+	return G2Affine{X: x, Y: y}
+}
+
+// line returns the line through T of slope l (on the twist), evaluated at P, as a sparse element of
+// Fp12 whose nonzero coordinates are lineSupport.
+//
+// With the D-type twist, the points of the twist are mapped to the curve with (x, y) -> (x*w^2,
+// y*w^3): the line is yP - l*xP*w + (l*xT - yT)*w^3. With the M-type twist, they are mapped with
+// (x, y) -> (x/w^2, y/w^3): the line is multiplied by w^4, which is in Fp6, and is then
+// yP*w^4 - l*xP*w^3 + (l*xT - yT)*w.
+func (pr *Pairing) line(T G2Affine, l emulated.ExtElement, P *weierstrass.Point) emulated.ExtElement {
+	fp, fp2 := pr.fp, pr.fp2
+	a := fp2.MulByBase(l, P.X)
+	c := fp2.Sub(fp2.Mul(l, T.X), T.Y)
+
+	// (a0 + a1*u) * w^i = (a0 - d*a1) * w^i + a1 * w^(i+6)
+	negA0 := fp.Sub(pr.mulSmall(a.Coeffs[1], pr.curve.d), a.Coeffs[0])
+	negA1 := fp.Neg(a.Coeffs[1])
+	c0 := fp.Sub(c.Coeffs[0], pr.mulSmall(c.Coeffs[1], pr.curve.d))
+	c1 := c.Coeffs[1]
+
+	res := pr.fp12.Zero()
+	if pr.curve.mType {
+		res.Coeffs[4] = P.Y
+		res.Coeffs[3], res.Coeffs[9] = negA0, negA1
+		res.Coeffs[1], res.Coeffs[7] = c0, c1
+	} else {
+		res.Coeffs[0] = P.Y
+		res.Coeffs[1], res.Coeffs[7] = negA0, negA1
+		res.Coeffs[3], res.Coeffs[9] = c0, c1
+	}
+	return res
+}
+
+// mulSmall returns c*a (c > 0), with additions only
+func (pr *Pairing) mulSmall(a emulated.Element, c int64) emulated.Element {
+	res := a
+	for i := bits.Len64(uint64(c)) - 2; i >= 0; i-- {
+		res = pr.fp.Add(res, res)
+		if (c>>uint(i))&1 == 1 {
+			res = pr.fp.Add(res, a)
+		}
+	}
+	return res
+}
@@ -0,0 +1,366 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pairing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls381"
+	fp381 "github.com/consensys/gurvy/bls381/fp"
+	"github.com/consensys/gurvy/bn256"
+)
+
+type gtCircuit struct {
+	A, B                    GT
+	Prod, Frob, Frob2, Conj GT `gnark:",public"`
+	curve                   Curve
+}
+
+func (circuit *gtCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	pr := NewPairing(cs, circuit.curve)
+	a := GT(pr.fp12.Mul(ext(circuit.A), ext(circuit.B)))
+	pr.AssertIsEqual(a, circuit.Prod)
+	pr.AssertIsEqual(GT(pr.fp12.Frobenius(ext(circuit.A), 1)), circuit.Frob)
+	pr.AssertIsEqual(GT(pr.fp12.Frobenius(ext(circuit.A), 2)), circuit.Frob2)
+	pr.AssertIsEqual(GT(pr.conjugate(ext(circuit.A))), circuit.Conj)
+	return nil
+}
+
+func ext(e GT) emulated.ExtElement {
+	return emulated.ExtElement(e)
+}
+
+func newGTCircuit(curve Curve) gtCircuit {
+	return gtCircuit{
+		A:     curve.GTPlaceholder(),
+		B:     curve.GTPlaceholder(),
+		Prod:  curve.GTPlaceholder(),
+		Frob:  curve.GTPlaceholder(),
+		Frob2: curve.GTPlaceholder(),
+		Conj:  curve.GTPlaceholder(),
+		curve: curve,
+	}
+}
+
+// TestGTBN256 checks the mapping of the tower of extensions of gurvy to Fp12
+func TestGTBN256(t *testing.T) {
+	assert := test.NewAssert(t)
+	curve := BN256()
+
+	var a, b, prod, frob, frob2, conj bn256.GT
+	if _, err := a.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	prod.Mul(&a, &b)
+	frob.Frobenius(&a)
+	frob2.FrobeniusSquare(&a)
+	conj.Conjugate(&a)
+
+	newWitness := func(prod *bn256.GT) *gtCircuit {
+		witness := newGTCircuit(curve)
+		witness.A.Assign(&a, curve)
+		witness.B.Assign(&b, curve)
+		witness.Prod.Assign(prod, curve)
+		witness.Frob.Assign(&frob, curve)
+		witness.Frob2.Assign(&frob2, curve)
+		witness.Conj.Assign(&conj, curve)
+		return &witness
+	}
+
+	circuit := newGTCircuit(curve)
+	assert.SolvingSucceeded(&circuit, newWitness(&prod), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(&a), gurvy.BN256)
+}
+
+// TestGTBLS381 checks the mapping of the tower of extensions of gurvy to Fp12
+func TestGTBLS381(t *testing.T) {
+	assert := test.NewAssert(t)
+	curve := BLS381()
+
+	var a, b, prod, frob, frob2, conj bls381.GT
+	if _, err := a.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	prod.Mul(&a, &b)
+	frob.Frobenius(&a)
+	frob2.FrobeniusSquare(&a)
+	conj.Conjugate(&a)
+
+	newWitness := func(prod *bls381.GT) *gtCircuit {
+		witness := newGTCircuit(curve)
+		witness.A.Assign(&a, curve)
+		witness.B.Assign(&b, curve)
+		witness.Prod.Assign(prod, curve)
+		witness.Frob.Assign(&frob, curve)
+		witness.Frob2.Assign(&frob2, curve)
+		witness.Conj.Assign(&conj, curve)
+		return &witness
+	}
+
+	circuit := newGTCircuit(curve)
+	assert.SolvingSucceeded(&circuit, newWitness(&prod), gurvy.BLS381)
+	assert.SolvingFailed(&circuit, newWitness(&a), gurvy.BLS381)
+}
+
+type pairCircuit struct {
+	P      weierstrass.Point
+	Q      G2Affine
+	Result GT `gnark:",public"`
+	curve  Curve
+}
+
+func (circuit *pairCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	pr := NewPairing(cs, circuit.curve)
+	res := pr.Pair([]weierstrass.Point{circuit.P}, []G2Affine{circuit.Q})
+	pr.AssertIsEqual(res, circuit.Result)
+	return nil
+}
+
+func newPairCircuit(curve Curve) pairCircuit {
+	return pairCircuit{P: curve.G1Placeholder(), Q: curve.G2Placeholder(), Result: curve.GTPlaceholder(), curve: curve}
+}
+
+func TestPairBN256(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a pairing on BN256 has about 7 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := BN256()
+
+	_, _, g1, g2 := bn256.Generators()
+	var p bn256.G1Affine
+	var q bn256.G2Affine
+	p.ScalarMultiplication(&g1, big.NewInt(123456789))
+	q.ScalarMultiplication(&g2, big.NewInt(987654321))
+	res, err := bn256.Pair([]bn256.G1Affine{p}, []bn256.G2Affine{q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witness := newPairCircuit(curve)
+	witness.P.Assign(&p.X, &p.Y, curve.G1)
+	witness.Q.Assign(&q, curve)
+	witness.Result.Assign(&res, curve)
+
+	circuit := newPairCircuit(curve)
+	assert.SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+}
+
+func TestPairBLS381(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a pairing on BLS381 has about 15 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := BLS381()
+
+	_, _, g1, g2 := bls381.Generators()
+	var p bls381.G1Affine
+	var q bls381.G2Affine
+	p.ScalarMultiplication(&g1, big.NewInt(123456789))
+	q.ScalarMultiplication(&g2, big.NewInt(987654321))
+	res, err := bls381.Pair([]bls381.G1Affine{p}, []bls381.G2Affine{q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witness := newPairCircuit(curve)
+	witness.P.Assign(&p.X, &p.Y, curve.G1)
+	witness.Q.Assign(&q, curve)
+	witness.Result.Assign(&res, curve)
+
+	circuit := newPairCircuit(curve)
+	assert.SolvingSucceeded(&circuit, &witness, gurvy.BLS381)
+}
+
+type g1Circuit struct {
+	P     weierstrass.Point
+	curve Curve
+}
+
+func (circuit *g1Circuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	NewPairing(cs, circuit.curve).AssertIsInG1(circuit.P)
+	return nil
+}
+
+type g2Circuit struct {
+	Q     G2Affine
+	curve Curve
+}
+
+func (circuit *g2Circuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	NewPairing(cs, circuit.curve).AssertIsInG2(circuit.Q)
+	return nil
+}
+
+func TestG1BN256(t *testing.T) {
+	assert := test.NewAssert(t)
+	curve := BN256()
+
+	_, _, g1, _ := bn256.Generators()
+	var p bn256.G1Affine
+	p.ScalarMultiplication(&g1, big.NewInt(123456789))
+	newWitness := func(x, y interface{}) *g1Circuit {
+		witness := g1Circuit{P: curve.G1Placeholder(), curve: curve}
+		witness.P.Assign(x, y, curve.G1)
+		return &witness
+	}
+
+	circuit := g1Circuit{P: curve.G1Placeholder(), curve: curve}
+	assert.SolvingSucceeded(&circuit, newWitness(&p.X, &p.Y), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(&p.X, &p.X), gurvy.BN256)
+
+	// the same coordinates, with a limb out of range: low + 2^NbBits, then high - 1
+	var x, low, high, base big.Int
+	p.X.ToBigIntRegular(&x)
+	base.Lsh(big.NewInt(1), uint(curve.G1.Fp.NbBits))
+	high.DivMod(&x, &base, &low)
+	high.Mod(&high, &base)
+	witness := newWitness(&p.X, &p.Y)
+	witness.P.X.Limbs[0], witness.P.X.Limbs[1] = frontend.Variable{}, frontend.Variable{}
+	witness.P.X.Limbs[0].Assign(low.Add(&low, &base))
+	witness.P.X.Limbs[1].Assign(high.Sub(&high, big.NewInt(1)))
+	assert.SolvingFailed(&circuit, witness, gurvy.BN256)
+}
+
+func TestG1BLS381(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the subgroup check of G1 on BLS381 has about 900000 constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := BLS381()
+
+	_, _, g1, _ := bls381.Generators()
+	var p bls381.G1Affine
+	p.ScalarMultiplication(&g1, big.NewInt(123456789))
+	newWitness := func(p *bls381.G1Affine) *g1Circuit {
+		witness := g1Circuit{P: curve.G1Placeholder(), curve: curve}
+		witness.P.Assign(&p.X, &p.Y, curve.G1)
+		return &witness
+	}
+
+	// a point of the curve which is not in G1
+	var q bls381.G1Affine
+	var b fp381.Element
+	b.SetUint64(4)
+	for {
+		q.X.Add(&q.X, &g1.Y)
+		q.Y.Square(&q.X).Mul(&q.Y, &q.X).Add(&q.Y, &b)
+		if q.Y.Legendre() == 1 {
+			q.Y.Sqrt(&q.Y)
+			break
+		}
+	}
+	if !q.IsOnCurve() || q.IsInSubGroup() {
+		t.Fatal("the point should be on the curve, and not in G1")
+	}
+
+	circuit := g1Circuit{P: curve.G1Placeholder(), curve: curve}
+	assert.SolvingSucceeded(&circuit, newWitness(&p), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(&q), gurvy.BN256)
+}
+
+func TestG2BN256(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the subgroup check of G2 on BN256 has about 1.6 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := BN256()
+
+	_, _, _, g2 := bn256.Generators()
+	var p bn256.G2Affine
+	p.ScalarMultiplication(&g2, big.NewInt(123456789))
+	newWitness := func(p *bn256.G2Affine) *g2Circuit {
+		witness := g2Circuit{Q: curve.G2Placeholder(), curve: curve}
+		witness.Q.Assign(p, curve)
+		return &witness
+	}
+
+	// a point of the twist which is not in G2 (b.X is the coefficient of the twist)
+	var b, q bn256.G2Affine
+	b.Y.Square(&g2.X).Mul(&b.Y, &g2.X)
+	b.X.Square(&g2.Y).Sub(&b.X, &b.Y)
+	for {
+		q.X.Add(&q.X, &g2.Y)
+		q.Y.Square(&q.X).Mul(&q.Y, &q.X).Add(&q.Y, &b.X)
+		if q.Y.Legendre() == 1 {
+			q.Y.Sqrt(&q.Y)
+			break
+		}
+	}
+	if !q.IsOnCurve() || q.IsInSubGroup() {
+		t.Fatal("the point should be on the twist, and not in G2")
+	}
+	notOnTwist := p
+	notOnTwist.Y = p.X
+
+	circuit := g2Circuit{Q: curve.G2Placeholder(), curve: curve}
+	assert.SolvingSucceeded(&circuit, newWitness(&p), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(&q), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(&notOnTwist), gurvy.BN256)
+}
+
+func TestG2BLS381(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the subgroup check of G2 on BLS381 has about 1.8 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := BLS381()
+
+	_, _, _, g2 := bls381.Generators()
+	var p bls381.G2Affine
+	p.ScalarMultiplication(&g2, big.NewInt(123456789))
+	newWitness := func(p *bls381.G2Affine) *g2Circuit {
+		witness := g2Circuit{Q: curve.G2Placeholder(), curve: curve}
+		witness.Q.Assign(p, curve)
+		return &witness
+	}
+
+	// a point of the twist which is not in G2 (b.X is the coefficient of the twist)
+	var b, q bls381.G2Affine
+	b.Y.Square(&g2.X).Mul(&b.Y, &g2.X)
+	b.X.Square(&g2.Y).Sub(&b.X, &b.Y)
+	for {
+		q.X.Add(&q.X, &g2.Y)
+		q.Y.Square(&q.X).Mul(&q.Y, &q.X).Add(&q.Y, &b.X)
+		if q.Y.Legendre() == 1 {
+			q.Y.Sqrt(&q.Y)
+			break
+		}
+	}
+	if !q.IsOnCurve() || q.IsInSubGroup() {
+		t.Fatal("the point should be on the twist, and not in G2")
+	}
+	notOnTwist := p
+	notOnTwist.Y = p.X
+
+	circuit := g2Circuit{Q: curve.G2Placeholder(), curve: curve}
+	assert.SolvingSucceeded(&circuit, newWitness(&p), gurvy.BLS381)
+	assert.SolvingFailed(&circuit, newWitness(&q), gurvy.BLS381)
+	assert.SolvingFailed(&circuit, newWitness(&notOnTwist), gurvy.BLS381)
+}
@@ -19,6 +19,7 @@ package sw
 import (
 	"math/big"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy/bls377"
 	"github.com/consensys/gurvy/bls377/fp"
@@ -296,3 +297,48 @@ func (p *G1Affine) MustBeEqual(cs *frontend.ConstraintSystem, other G1Affine) {
 	cs.AssertIsEqual(p.X, other.X)
 	cs.AssertIsEqual(p.Y, other.Y)
 }
+
+// MustBeOnCurve constraint p to be on the curve y**2 = x**3 + 1 of bls377
+func (p *G1Affine) MustBeOnCurve(cs *frontend.ConstraintSystem) {
+	lhs := cs.Mul(p.Y, p.Y)
+	rhs := cs.Add(cs.Mul(p.X, p.X, p.X), 1)
+	cs.AssertIsEqual(lhs, rhs)
+}
+
+// MustBeInSubGroup constraint p, which must be on the curve (see MustBeOnCurve), to be in the
+// subgroup of order r. As in gurvy, it checks that p + x**2*phi(p) is the infinity, where
+// phi: (x,y)->(w*x,y), w being a cube root of 1 in Fp, and x the seed of bls377.
+func (p *G1Affine) MustBeInSubGroup(cs *frontend.ConstraintSystem) {
+
+	var res G1Affine
+	res.X = cs.Mul(p.X, "80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410945")
+	res.Y = p.Y
+	res.scalarMulConstant(cs, &res, "91893752504881257701523279626832445441") // x**2
+
+	// x**2*phi(p) = -p
+	var pNeg G1Affine
+	pNeg.Neg(cs, p)
+	res.MustBeEqual(cs, pNeg)
+}
+
+// scalarMulConstant computes s*p1 (s > 0 is a constant), assigns the result to p and return it
+// the double and add formulas are incomplete: for p1 in the subgroup of order r > s, no
+// intermediate point is the infinity or ±p1, and the constraints are not satisfiable otherwise
+func (p *G1Affine) scalarMulConstant(cs *frontend.ConstraintSystem, p1 *G1Affine, s interface{}) *G1Affine {
+
+	scalar := backend.FromInterface(s)
+	base := *p1
+	res := *p1
+	for i := scalar.BitLen() - 2; i >= 0; i-- {
+		res.Double(cs, &res)
+		if scalar.Bit(i) == 1 {
+			// res != ±base (the difference of the abscissas is invertible), so that the
+			// division of AddAssign is constrained
+			cs.Inverse(cs.Sub(base.X, res.X))
+			res.AddAssign(cs, &base)
+		}
+	}
+	*p = res
+
+	return p
+}
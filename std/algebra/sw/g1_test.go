@@ -23,6 +23,7 @@ import (
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls377/fp"
 	"github.com/consensys/gurvy/bls377/fr"
 
 	"github.com/consensys/gurvy/bls377"
@@ -283,6 +284,72 @@ func TestScalarMulG1(t *testing.T) {
 
 }
 
+// -------------------------------------------------------------------------------------------------
+// membership
+
+type g1Membership struct {
+	A G1Affine
+}
+
+func (circuit *g1Membership) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.A.MustBeOnCurve(cs)
+	circuit.A.MustBeInSubGroup(cs)
+	return nil
+}
+
+func TestMembershipG1(t *testing.T) {
+
+	// create the cs
+	var circuit g1Membership
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func(a *bls377.G1Affine) *g1Membership {
+		var witness g1Membership
+		witness.A.Assign(a)
+		return &witness
+	}
+
+	// a point of the subgroup
+	var a bls377.G1Affine
+	_a := randomPointG1()
+	a.FromJacobian(&_a)
+
+	// a point of the curve which is not in the subgroup
+	b := pointNotInSubGroupG1()
+	if !b.IsOnCurve() || b.IsInSubGroup() {
+		t.Fatal("the point should be on the curve, and not in the subgroup")
+	}
+
+	// a point which is not on the curve
+	c := a
+	c.Y.SetOne().Add(&c.Y, &a.Y)
+
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, newWitness(&a))
+	assert.SolvingFailed(r1cs, newWitness(&b))
+	assert.SolvingFailed(r1cs, newWitness(&c))
+
+}
+
+// pointNotInSubGroupG1 returns a point of the curve y**2 = x**3 + 1 whose order is not r
+func pointNotInSubGroupG1() bls377.G1Affine {
+	var p bls377.G1Affine
+	for x := uint64(1); ; x++ {
+		var rhs fp.Element
+		p.X.SetUint64(x)
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, new(fp.Element).SetOne())
+		if rhs.Legendre() == 1 {
+			p.Y.Sqrt(&rhs)
+			if !p.IsInSubGroup() {
+				return p
+			}
+		}
+	}
+}
+
 func randomPointG1() bls377.G1Jac {
 
 	p1, _, _, _ := bls377.Generators()
@@ -17,6 +17,7 @@ limitations under the License.
 package sw
 
 import (
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/fields"
 	"github.com/consensys/gurvy/bls377"
@@ -219,3 +220,52 @@ func (p *G2Affine) MustBeEqual(cs *frontend.ConstraintSystem, other G2Affine) {
 	p.X.MustBeEqual(cs, other.X)
 	p.Y.MustBeEqual(cs, other.Y)
 }
+
+// MustBeOnCurve constraint p to be on the twist y**2 = x**3 + 1/u of bls377
+func (p *G2Affine) MustBeOnCurve(cs *frontend.ConstraintSystem, ext fields.Extension) {
+
+	// u*(y**2 - x**3) = 1
+	var lhs, x3 fields.E2
+	lhs.Mul(cs, &p.Y, &p.Y, ext)
+	x3.Mul(cs, &p.X, &p.X, ext).Mul(cs, &x3, &p.X, ext)
+	lhs.Sub(cs, &lhs, &x3).MulByIm(cs, &lhs, ext)
+
+	cs.AssertIsEqual(lhs.A0, 1)
+	cs.AssertIsEqual(lhs.A1, 0)
+}
+
+// MustBeInSubGroup constraint p, which must be on the twist (see MustBeOnCurve), to be in the
+// subgroup of order r. As in gurvy, it checks that p + x**2*phi(p) is the infinity, where
+// phi: (x,y)->(w*x,y), w being a cube root of 1 in Fp, and x the seed of bls377.
+func (p *G2Affine) MustBeInSubGroup(cs *frontend.ConstraintSystem, ext fields.Extension) {
+
+	// w = (v**6)**(2*(p**2-1)/6)
+	var res G2Affine
+	res.X.MulByFp(cs, &p.X, "258664426012969093929703085429980814127835149614277183275038967946009968870203535512256352201271898244626862047231")
+	res.Y = p.Y
+	res.scalarMulConstant(cs, &res, "91893752504881257701523279626832445441", ext) // x**2
+
+	// x**2*phi(p) = -p
+	var pNeg G2Affine
+	pNeg.Neg(cs, p)
+	res.MustBeEqual(cs, pNeg)
+}
+
+// scalarMulConstant computes s*p1 (s > 0 is a constant), assigns the result to p and return it
+// the double and add formulas are incomplete: for p1 in the subgroup of order r > s, no
+// intermediate point is the infinity or ±p1
+func (p *G2Affine) scalarMulConstant(cs *frontend.ConstraintSystem, p1 *G2Affine, s interface{}, ext fields.Extension) *G2Affine {
+
+	scalar := backend.FromInterface(s)
+	base := *p1
+	res := *p1
+	for i := scalar.BitLen() - 2; i >= 0; i-- {
+		res.Double(cs, &res, ext)
+		if scalar.Bit(i) == 1 {
+			res.AddAssign(cs, &base, ext)
+		}
+	}
+	*p = res
+
+	return p
+}
@@ -235,6 +235,77 @@ func TestNegG2(t *testing.T) {
 
 }
 
+// -------------------------------------------------------------------------------------------------
+// membership
+
+type g2Membership struct {
+	A G2Affine
+}
+
+func (circuit *g2Membership) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	ext := fields.GetBLS377ExtensionFp12(cs)
+	circuit.A.MustBeOnCurve(cs, ext)
+	circuit.A.MustBeInSubGroup(cs, ext)
+	return nil
+}
+
+func TestMembershipG2(t *testing.T) {
+
+	// create the cs
+	var circuit g2Membership
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newWitness := func(a *bls377.G2Affine) *g2Membership {
+		var witness g2Membership
+		witness.A.Assign(a)
+		return &witness
+	}
+
+	// a point of the subgroup
+	var a bls377.G2Affine
+	_a := randomPointG2()
+	a.FromJacobian(&_a)
+
+	// a point of the twist which is not in the subgroup
+	b := pointNotInSubGroupG2()
+	if !b.IsOnCurve() || b.IsInSubGroup() {
+		t.Fatal("the point should be on the twist, and not in the subgroup")
+	}
+
+	// a point which is not on the twist
+	c := a
+	c.Y.A0.SetOne().Add(&c.Y.A0, &a.Y.A0)
+
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, newWitness(&a))
+	assert.SolvingFailed(r1cs, newWitness(&b))
+	assert.SolvingFailed(r1cs, newWitness(&c))
+
+}
+
+// pointNotInSubGroupG2 returns a point of the twist y**2 = x**3 + 1/u whose order is not r
+func pointNotInSubGroupG2() bls377.G2Affine {
+	var b bls377.E2
+	b.A1.SetOne()
+	b.Inverse(&b)
+
+	var p bls377.G2Affine
+	for x := uint64(1); ; x++ {
+		var rhs bls377.E2
+		p.X.A0.SetUint64(x)
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &b)
+		if rhs.Legendre() == 1 {
+			p.Y.Sqrt(&rhs)
+			if !p.IsInSubGroup() {
+				return p
+			}
+		}
+	}
+}
+
 func randomPointG2() bls377.G2Jac {
 	_, p2, _, _ := bls377.Generators()
 
@@ -209,3 +209,81 @@ func MillerLoopAffine(cs *frontend.ConstraintSystem, P G1Affine, Q G2Affine, res
 
 	return res
 }
+
+// MultiMillerLoopAffine computes the product of the miller loops of the pairs (P[i], Q[i]), with
+// points in affine: the squarings of the accumulator are shared between the pairs
+// When neither Q[i] nor P[i] are the point at infinity
+func MultiMillerLoopAffine(cs *frontend.ConstraintSystem, P []G1Affine, Q []G2Affine, res *fields.E12, pairingInfo PairingContext) *fields.E12 {
+	if len(P) != len(Q) {
+		panic("sw: P and Q must have the same length")
+	}
+
+	var ateLoopNaf [64]int8
+	var ateLoopBigInt big.Int
+	ateLoopBigInt.SetUint64(pairingInfo.AteLoop)
+	utils.NafDecomposition(&ateLoopBigInt, ateLoopNaf[:])
+
+	res.SetOne(cs)
+
+	// the lines go through QCur[k] and QNext
+	QCur := make([]G2Affine, len(Q))
+	QNeg := make([]G2Affine, len(Q))
+	copy(QCur, Q)
+
+	// Stores -Q[k]
+	for k := range Q {
+		QNeg[k].Neg(cs, &Q[k])
+	}
+
+	var QNext, QNextNeg G2Affine
+	var lEval LineEvalRes
+
+	// Miller loop
+	for i := len(ateLoopNaf) - 2; i >= 0; i-- {
+		res.Mul(cs, res, res, pairingInfo.Extension)
+
+		for k := range Q {
+			QNext = QCur[k]
+			QNext.Double(cs, &QNext, pairingInfo.Extension)
+			QNextNeg.Neg(cs, &QNext)
+
+			// evaluates line though Qcur,2Qcur at P
+			LineEvalAffineBLS377(cs, QCur[k], QNextNeg, P[k], &lEval, pairingInfo.Extension)
+			lEval.MulAssign(cs, res, pairingInfo.Extension)
+
+			if ateLoopNaf[i] == 1 {
+				// evaluates line through 2Qcur, Q at P
+				LineEvalAffineBLS377(cs, QNext, Q[k], P[k], &lEval, pairingInfo.Extension)
+				lEval.MulAssign(cs, res, pairingInfo.Extension)
+
+				QNext.AddAssign(cs, &Q[k], pairingInfo.Extension)
+
+			} else if ateLoopNaf[i] == -1 {
+				// evaluates line through 2Qcur, -Q at P
+				LineEvalAffineBLS377(cs, QNext, QNeg[k], P[k], &lEval, pairingInfo.Extension)
+				lEval.MulAssign(cs, res, pairingInfo.Extension)
+
+				QNext.AddAssign(cs, &QNeg[k], pairingInfo.Extension)
+			}
+
+			QCur[k] = QNext
+		}
+	}
+
+	return res
+}
+
+// PairingCheck constraint the product of the pairings e(P[i], Q[i]) on bls377 to be 1, with one
+// multi miller loop and one final exponentiation (cs must be a bw761 circuit)
+// The points must not be the point at infinity. Points of the witness which are not trusted must be
+// checked first (see G2Affine.MustBeOnCurve and G2Affine.MustBeInSubGroup).
+func PairingCheck(cs *frontend.ConstraintSystem, P []G1Affine, Q []G2Affine) {
+	pairingInfo := PairingContext{AteLoop: 9586122913090633729, Extension: fields.GetBLS377ExtensionFp12(cs)}
+
+	var milRes, pairingRes, one fields.E12
+	MultiMillerLoopAffine(cs, P, Q, &milRes, pairingInfo)
+	pairingRes.FinalExpoBLS(cs, &milRes, pairingInfo.AteLoop, pairingInfo.Extension)
+
+	one.SetOne(cs)
+	pairingRes.MustBeEqual(cs, one)
+}
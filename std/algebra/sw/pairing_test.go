@@ -17,6 +17,7 @@ limitations under the License.
 package sw
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
@@ -222,6 +223,80 @@ func TestPairingBLS377(t *testing.T) {
 
 }
 
+// aggregated BLS signature of 2 messages: e(sig, g2) = e(h0, pk0) * e(h1, pk1)
+type pairingCheckBLS377 struct {
+	Sig G1Affine
+	H   [2]G1Affine `gnark:",public"` // hashes of the messages
+	Pk  [2]G2Affine `gnark:",public"`
+}
+
+func (circuit *pairingCheckBLS377) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+
+	// the public keys are not trusted
+	ext := fields.GetBLS377ExtensionFp12(cs)
+	for i := range circuit.Pk {
+		circuit.Pk[i].MustBeOnCurve(cs, ext)
+		circuit.Pk[i].MustBeInSubGroup(cs, ext)
+	}
+
+	// -g2
+	_, _, _, g2 := bls377.Generators()
+	g2.Neg(&g2)
+	var g2Neg G2Affine
+	g2Neg.X.A0 = cs.Constant(bls377FpTobw761fr(&g2.X.A0))
+	g2Neg.X.A1 = cs.Constant(bls377FpTobw761fr(&g2.X.A1))
+	g2Neg.Y.A0 = cs.Constant(bls377FpTobw761fr(&g2.Y.A0))
+	g2Neg.Y.A1 = cs.Constant(bls377FpTobw761fr(&g2.Y.A1))
+
+	PairingCheck(cs,
+		[]G1Affine{circuit.Sig, circuit.H[0], circuit.H[1]},
+		[]G2Affine{g2Neg, circuit.Pk[0], circuit.Pk[1]})
+
+	return nil
+}
+
+func TestPairingCheckBLS377(t *testing.T) {
+
+	// create cs
+	var circuit pairingCheckBLS377
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// keys, messages and signatures
+	_, _, g1, g2 := bls377.Generators()
+	var h, sig [2]bls377.G1Affine
+	var pk [2]bls377.G2Affine
+	for i := 0; i < 2; i++ {
+		sk := big.NewInt(int64(1234567 + i))
+		pk[i].ScalarMultiplication(&g2, sk)
+		h[i].ScalarMultiplication(&g1, big.NewInt(int64(7654321+i)))
+		sig[i].ScalarMultiplication(&h[i], sk)
+	}
+
+	newWitness := func(sig *bls377.G1Affine) *pairingCheckBLS377 {
+		var witness pairingCheckBLS377
+		witness.Sig.Assign(sig)
+		for i := 0; i < 2; i++ {
+			witness.H[i].Assign(&h[i])
+			witness.Pk[i].Assign(&pk[i])
+		}
+		return &witness
+	}
+
+	var aggregated bls377.G1Jac
+	aggregated.FromAffine(&sig[0])
+	aggregated.AddMixed(&sig[1])
+	var aggregatedAff bls377.G1Affine
+	aggregatedAff.FromJacobian(&aggregated)
+
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, newWitness(&aggregatedAff))
+	assert.SolvingFailed(r1cs, newWitness(&sig[0]))
+
+}
+
 func pairingData() (P bls377.G1Affine, Q bls377.G2Affine, pairingRes bls377.GT) {
 	P.X.SetString("68333130937826953018162399284085925021577172705782285525244777453303237942212457240213897533859360921141590695983")
 	P.Y.SetString("243386584320553125968203959498080829207604143167922579970841210259134422887279629198736754149500839244552761526603")
@@ -21,10 +21,14 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark/backend"
+	edbls377 "github.com/consensys/gnark/crypto/twistededwards/bls377"
+	edbw761 "github.com/consensys/gnark/crypto/twistededwards/bw761"
 	"github.com/consensys/gurvy"
+	frbls377 "github.com/consensys/gurvy/bls377/fr"
 	edbls381 "github.com/consensys/gurvy/bls381/twistededwards"
 	"github.com/consensys/gurvy/bn256/fr"
 	edbn256 "github.com/consensys/gurvy/bn256/twistededwards"
+	frbw761 "github.com/consensys/gurvy/bw761/fr"
 )
 
 // EdCurve stores the info on the chosen edwards curve
@@ -39,6 +43,8 @@ func init() {
 	newTwistedEdwards = make(map[gurvy.ID]func() EdCurve)
 	newTwistedEdwards[gurvy.BLS381] = newEdBLS381
 	newTwistedEdwards[gurvy.BN256] = newEdBN256
+	newTwistedEdwards[gurvy.BLS377] = newEdBLS377
+	newTwistedEdwards[gurvy.BW761] = newEdBW761
 }
 
 // NewEdCurve returns an Edwards curve parameters
@@ -49,6 +55,16 @@ func NewEdCurve(id gurvy.ID) (EdCurve, error) {
 	return EdCurve{}, errors.New("unknown curve id")
 }
 
+// scalarNbBits returns the number of bits on which the scalars of a scalar multiplication are
+// decomposed: 256 bits for the scalar fields which fit on it (keeping the circuits on BN256 and
+// BLS381 unchanged), the size of the scalar field for the larger ones (BW761)
+func (curve EdCurve) scalarNbBits() int {
+	if nbBits := curve.Modulus.BitLen(); nbBits > 256 {
+		return nbBits
+	}
+	return 256
+}
+
 // -------------------------------------------------------------------------------------------------
 // constructors
 
@@ -94,3 +110,45 @@ func newEdBLS381() EdCurve {
 	return res
 
 }
+
+func newEdBLS377() EdCurve {
+
+	edcurve := edbls377.GetEdwardsCurve()
+	var cofactorReg big.Int
+	edcurve.Cofactor.ToBigInt(&cofactorReg)
+
+	res := EdCurve{
+		A:        backend.FromInterface(edcurve.A),
+		D:        backend.FromInterface(edcurve.D),
+		Cofactor: backend.FromInterface(cofactorReg),
+		Order:    backend.FromInterface(edcurve.Order),
+		BaseX:    backend.FromInterface(edcurve.Base.X),
+		BaseY:    backend.FromInterface(edcurve.Base.Y),
+		ID:       gurvy.BLS377,
+	}
+	res.Modulus.Set(frbls377.Modulus())
+
+	return res
+
+}
+
+func newEdBW761() EdCurve {
+
+	edcurve := edbw761.GetEdwardsCurve()
+	var cofactorReg big.Int
+	edcurve.Cofactor.ToBigInt(&cofactorReg)
+
+	res := EdCurve{
+		A:        backend.FromInterface(edcurve.A),
+		D:        backend.FromInterface(edcurve.D),
+		Cofactor: backend.FromInterface(cofactorReg),
+		Order:    backend.FromInterface(edcurve.Order),
+		BaseX:    backend.FromInterface(edcurve.Base.X),
+		BaseY:    backend.FromInterface(edcurve.Base.Y),
+		ID:       gurvy.BW761,
+	}
+	res.Modulus.Set(frbw761.Modulus())
+
+	return res
+
+}
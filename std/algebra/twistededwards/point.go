@@ -110,7 +110,7 @@ func (p *Point) Double(cs *frontend.ConstraintSystem, p1 *Point, curve EdCurve)
 func (p *Point) ScalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point, scalar frontend.Variable, curve EdCurve) *Point {
 
 	// first unpack the scalar
-	b := cs.ToBinary(scalar, 256)
+	b := cs.ToBinary(scalar, curve.scalarNbBits())
 
 	res := Point{
 		cs.Constant(0),
@@ -138,7 +138,7 @@ func (p *Point) ScalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point,
 func (p *Point) ScalarMulFixedBase(cs *frontend.ConstraintSystem, x, y interface{}, scalar frontend.Variable, curve EdCurve) *Point {
 
 	// first unpack the scalar
-	b := cs.ToBinary(scalar, 256)
+	b := cs.ToBinary(scalar, curve.scalarNbBits())
 
 	res := Point{
 		cs.Constant(0),
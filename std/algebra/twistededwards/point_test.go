@@ -42,22 +42,25 @@ func (circuit *mustBeOnCurve) Define(curveID gurvy.ID, cs *frontend.ConstraintSy
 
 func TestIsOnCurve(t *testing.T) {
 	assert := groth16.NewAssert(t)
-	var circuit, witness mustBeOnCurve
-	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
-	if err != nil {
-		t.Fatal(err)
-	}
 
-	params, err := NewEdCurve(gurvy.BN256)
-	if err != nil {
-		t.Fatal(err)
-	}
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		var circuit, witness mustBeOnCurve
+		r1cs, err := frontend.Compile(id, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	witness.P.X.Assign(params.BaseX)
-	witness.P.Y.Assign(params.BaseY)
+		params, err := NewEdCurve(id)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	// creates r1cs
-	assert.SolvingSucceeded(r1cs, &witness)
+		witness.P.X.Assign(params.BaseX)
+		witness.P.Y.Assign(params.BaseY)
+
+		// creates r1cs
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
 
 }
 
@@ -0,0 +1,162 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package weierstrass implements the arithmetic of short Weierstrass curves y^2 = x^3 + a*x + b
+// defined on a field which is not the native field (see std/math/emulated), such as secp256k1 or
+// G1 of BN256 and BLS381.
+package weierstrass
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Curve stores the parameters of a short Weierstrass curve y^2 = x^3 + a*x + b
+type Curve struct {
+	A, B   big.Int
+	Gx, Gy big.Int // generator of the subgroup of order Order
+	Order  big.Int
+
+	Fp emulated.Params // the base field
+	Fr emulated.Params // the integers modulo Order (the scalars)
+
+	// the scalar multiplications start from an arbitrary point (whose discrete logarithm is not
+	// known), so that the affine formulas never add a point to itself or to its opposite
+	offsetX, offsetY big.Int
+}
+
+// Secp256k1 returns the parameters of the curve secp256k1 (y^2 = x^3 + 7, used by Bitcoin and
+// Ethereum), with limbs of 64 bits
+func Secp256k1() Curve {
+	var curve Curve
+	curve.B.SetUint64(7)
+	curve.Gx.SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	curve.Gy.SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	curve.Order.SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+	curve.Fp = emulated.Secp256k1Fp()
+	curve.Fr = emulated.Secp256k1Fr()
+	curve.init()
+	return curve
+}
+
+// BN256 returns the parameters of G1 of the curve BN256 (y^2 = x^3 + 3), with limbs of 64 bits
+func BN256() Curve {
+	var curve Curve
+	curve.B.SetUint64(3)
+	curve.Gx.SetUint64(1)
+	curve.Gy.SetUint64(2)
+	curve.Order.SetString("30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001", 16)
+
+	curve.Fp = emulated.BN256Fp()
+	curve.Fr = emulated.BN256Fr()
+	curve.init()
+	return curve
+}
+
+// BLS381 returns the parameters of G1 of the curve BLS381 (y^2 = x^3 + 4), with limbs of 64 bits
+func BLS381() Curve {
+	var curve Curve
+	curve.B.SetUint64(4)
+	curve.Gx.SetString("17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb", 16)
+	curve.Gy.SetString("08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1", 16)
+	curve.Order.SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+	curve.Fp = emulated.BLS381Fp()
+	curve.Fr = emulated.BLS381Fr()
+	curve.init()
+	return curve
+}
+
+// init sets the offset of the scalar multiplications
+func (curve *Curve) init() {
+	p := &curve.Fp.Modulus
+
+	// the offset is the first point whose abscissa is greater than or equal to sha256("gnark")
+	h := sha256.Sum256([]byte("gnark"))
+	x := new(big.Int).SetBytes(h[:])
+	x.Mod(x, p)
+	for {
+		if y := curve.nativeY(x); y != nil {
+			curve.offsetX.Set(x)
+			curve.offsetY.Set(y)
+			return
+		}
+		x.Add(x, big.NewInt(1)).Mod(x, p)
+	}
+}
+
+// -------------------------------------------------------------------------------------------------
+// native arithmetic, for the constants
+
+// nativePoint is a point in affine coordinates, the point at infinity having nil coordinates
+type nativePoint struct {
+	x, y *big.Int
+}
+
+// nativeY returns a square root of x^3 + a*x + b, or nil if there is none
+func (curve *Curve) nativeY(x *big.Int) *big.Int {
+	p := &curve.Fp.Modulus
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Add(rhs, &curve.A).Mul(rhs, x).Add(rhs, &curve.B).Mod(rhs, p)
+	return new(big.Int).ModSqrt(rhs, p)
+}
+
+// nativeAdd returns p1 + p2
+func (curve *Curve) nativeAdd(p1, p2 nativePoint) nativePoint {
+	p := &curve.Fp.Modulus
+	if p1.x == nil {
+		return p2
+	}
+	if p2.x == nil {
+		return p1
+	}
+
+	var l, tmp big.Int
+	if p1.x.Cmp(p2.x) == 0 {
+		if tmp.Add(p1.y, p2.y).Mod(&tmp, p).Sign() == 0 {
+			return nativePoint{}
+		}
+		// l = (3x^2 + a) / 2y
+		l.Mul(p1.x, p1.x).Mul(&l, big.NewInt(3)).Add(&l, &curve.A)
+		tmp.Lsh(p1.y, 1).ModInverse(&tmp, p)
+	} else {
+		// l = (y2-y1) / (x2-x1)
+		l.Sub(p2.y, p1.y)
+		tmp.Sub(p2.x, p1.x).Mod(&tmp, p).ModInverse(&tmp, p)
+	}
+	l.Mul(&l, &tmp).Mod(&l, p)
+
+	x := new(big.Int).Mul(&l, &l)
+	x.Sub(x, p1.x).Sub(x, p2.x).Mod(x, p)
+	y := new(big.Int).Sub(p1.x, x)
+	y.Mul(y, &l).Sub(y, p1.y).Mod(y, p)
+	return nativePoint{x, y}
+}
+
+// nativeScalarMul returns s*p1 (s nonnegative)
+func (curve *Curve) nativeScalarMul(p1 nativePoint, s *big.Int) nativePoint {
+	var res nativePoint
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = curve.nativeAdd(res, res)
+		if s.Bit(i) == 1 {
+			res = curve.nativeAdd(res, p1)
+		}
+	}
+	return res
+}
@@ -0,0 +1,228 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weierstrass
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Point is a point of the curve in affine coordinates (the point at infinity is not representable)
+type Point struct {
+	X, Y emulated.Element
+}
+
+// Placeholder returns a point whose coordinates are not assigned, to be used in the definition of
+// a circuit
+func (curve Curve) Placeholder() Point {
+	return Point{X: curve.Fp.Placeholder(), Y: curve.Fp.Placeholder()}
+}
+
+// Generator returns the generator of the subgroup of order curve.Order
+func (curve Curve) Generator(cs *frontend.ConstraintSystem) Point {
+	return Point{X: curve.Fp.Constant(cs, &curve.Gx), Y: curve.Fp.Constant(cs, &curve.Gy)}
+}
+
+// Assign assigns the coordinates of p to x, y
+func (p *Point) Assign(x, y interface{}, curve Curve) {
+	p.X.Assign(x, curve.Fp)
+	p.Y.Assign(y, curve.Fp)
+}
+
+// MustBeOnCurve checks that p is on the curve (and range checks its coordinates, which may be part
+// of the witness)
+func (p *Point) MustBeOnCurve(cs *frontend.ConstraintSystem, curve Curve) {
+	fp := curve.Fp
+	p.X.RangeCheck(cs, fp)
+	p.Y.RangeCheck(cs, fp)
+
+	// y^2 = x^3 + a*x + b
+	var lhs, rhs, tmp emulated.Element
+	lhs.Mul(cs, &p.Y, &p.Y, fp)
+	rhs.Mul(cs, &p.X, &p.X, fp)
+	if curve.A.Sign() != 0 {
+		a := fp.Constant(cs, &curve.A)
+		rhs.Add(cs, &rhs, &a, fp)
+	}
+	rhs.Mul(cs, &rhs, &p.X, fp)
+	tmp = fp.Constant(cs, &curve.B)
+	rhs.Add(cs, &rhs, &tmp, fp)
+	emulated.AssertIsEqual(cs, &lhs, &rhs, fp)
+}
+
+// Neg sets p to -p1
+func (p *Point) Neg(cs *frontend.ConstraintSystem, p1 *Point, curve Curve) *Point {
+	p.X = p1.X
+	p.Y.Neg(cs, &p1.Y, curve.Fp)
+	return p
+}
+
+// Add sets p to p1+p2, with the affine formulas: p1 must not be equal to p2 or -p2 (the circuit
+// is not satisfied otherwise, as the slope divides by x2-x1 = 0)
+func (p *Point) Add(cs *frontend.ConstraintSystem, p1, p2 *Point, curve Curve) *Point {
+	fp := curve.Fp
+
+	// l = (y2-y1) / (x2-x1)
+	var num, den, l emulated.Element
+	num.Sub(cs, &p2.Y, &p1.Y, fp)
+	den.Sub(cs, &p2.X, &p1.X, fp)
+	l.Div(cs, &num, &den, fp)
+
+	return p.fromSlope(cs, p1, p2, &l, curve)
+}
+
+// Double sets p to 2*p1, with the affine formulas (p1 must not be a point of order 2)
+func (p *Point) Double(cs *frontend.ConstraintSystem, p1 *Point, curve Curve) *Point {
+	fp := curve.Fp
+
+	// l = (3x^2 + a) / 2y
+	var square, num, den, l emulated.Element
+	square.Mul(cs, &p1.X, &p1.X, fp)
+	num.Add(cs, &square, &square, fp)
+	num.Add(cs, &num, &square, fp)
+	if curve.A.Sign() != 0 {
+		a := fp.Constant(cs, &curve.A)
+		num.Add(cs, &num, &a, fp)
+	}
+	den.Add(cs, &p1.Y, &p1.Y, fp)
+	l.Div(cs, &num, &den, fp)
+
+	return p.fromSlope(cs, p1, p1, &l, curve)
+}
+
+// fromSlope sets p to p1+p2, l being the slope of the line through p1 and p2
+func (p *Point) fromSlope(cs *frontend.ConstraintSystem, p1, p2 *Point, l *emulated.Element, curve Curve) *Point {
+	fp := curve.Fp
+
+	// x = l^2 - x1 - x2
+	var x, y emulated.Element
+	x.Mul(cs, l, l, fp)
+	x.Sub(cs, &x, &p1.X, fp)
+	x.Sub(cs, &x, &p2.X, fp)
+
+	// y = l(x1 - x) - y1
+	y.Sub(cs, &p1.X, &x, fp)
+	y.Mul(cs, &y, l, fp)
+	y.Sub(cs, &y, &p1.Y, fp)
+
+	p.X = x
+	p.Y = y
+	return p
+}
+
+// Select sets p to p1 if b is true, to p2 otherwise
+func (p *Point) Select(cs *frontend.ConstraintSystem, b frontend.Variable, p1, p2 *Point, curve Curve) *Point {
+	p.X.Select(cs, b, &p1.X, &p2.X, curve.Fp)
+	p.Y.Select(cs, b, &p1.Y, &p2.Y, curve.Fp)
+	return p
+}
+
+// ScalarMul sets p to s*p1 (s must not be 0 modulo the order of p1)
+func (p *Point) ScalarMul(cs *frontend.ConstraintSystem, p1 *Point, s *emulated.Element, curve Curve) *Point {
+	bits := s.ToBinary(cs, curve.Fr)
+
+	acc := curve.offset(cs, 0)
+	for i := len(bits) - 1; i >= 0; i-- {
+		var tmp Point
+		acc.Double(cs, &acc, curve)
+		tmp.Add(cs, &acc, p1, curve)
+		acc.Select(cs, bits[i], &tmp, &acc, curve)
+	}
+
+	// acc = 2^n * offset + s*p1
+	correction := curve.offset(cs, len(bits))
+	correction.Neg(cs, &correction, curve)
+	return p.Add(cs, &acc, &correction, curve)
+}
+
+// JointScalarMul sets p to s*p1 + t*p2 (Shamir's trick: the doublings are shared). p1 and p2 must
+// not be equal or opposite, and s*p1 + t*p2 must not be the point at infinity.
+func (p *Point) JointScalarMul(cs *frontend.ConstraintSystem, p1, p2 *Point, s, t *emulated.Element, curve Curve) *Point {
+	sBits := s.ToBinary(cs, curve.Fr)
+	tBits := t.ToBinary(cs, curve.Fr)
+
+	var sum Point
+	sum.Add(cs, p1, p2, curve)
+
+	acc := curve.offset(cs, 0)
+	for i := len(sBits) - 1; i >= 0; i-- {
+		acc.Double(cs, &acc, curve)
+
+		// p1, p2 or p1+p2 is added, if one of the bits is set
+		var addend, tmp Point
+		addend.lookup(cs, sBits[i], tBits[i], p1, p2, &sum, curve)
+		tmp.Add(cs, &acc, &addend, curve)
+		acc.Select(cs, cs.Or(sBits[i], tBits[i]), &tmp, &acc, curve)
+	}
+
+	// acc = 2^n * offset + s*p1 + t*p2
+	correction := curve.offset(cs, len(sBits))
+	correction.Neg(cs, &correction, curve)
+	return p.Add(cs, &acc, &correction, curve)
+}
+
+// MultiScalarMul sets p to p0 + Σ scalars[i]*points[i]. The scalars are native variables of at
+// most curve.Order.BitLen() bits, which may be 0, and the result must not be the point at infinity.
+func (p *Point) MultiScalarMul(cs *frontend.ConstraintSystem, p0 *Point, points []Point, scalars []frontend.Variable, curve Curve) *Point {
+	if len(points) != len(scalars) {
+		panic("weierstrass: the numbers of points and scalars differ")
+	}
+	nbBits := curve.Order.BitLen()
+	bits := make([][]frontend.Variable, len(scalars))
+	for i := range scalars {
+		bits[i] = cs.ToBinary(scalars[i], nbBits)
+	}
+
+	// the doublings are shared, and the offset keeps the partial sums away from the points
+	acc := curve.offset(cs, 0)
+	for j := nbBits - 1; j >= 0; j-- {
+		acc.Double(cs, &acc, curve)
+		for i := range points {
+			var tmp Point
+			tmp.Add(cs, &acc, &points[i], curve)
+			acc.Select(cs, bits[i][j], &tmp, &acc, curve)
+		}
+	}
+
+	// acc = 2^n * offset + Σ scalars[i]*points[i]
+	correction := curve.offset(cs, nbBits)
+	correction.Neg(cs, &correction, curve)
+	acc.Add(cs, &acc, p0, curve)
+	return p.Add(cs, &acc, &correction, curve)
+}
+
+// lookup sets p to p1 if (b1, b2) = (1, 0), to p2 if (b1, b2) = (0, 1), and to p12 otherwise
+func (p *Point) lookup(cs *frontend.ConstraintSystem, b1, b2 frontend.Variable, p1, p2, p12 *Point, curve Curve) *Point {
+	p.X.Lookup2(cs, b1, b2, &p12.X, &p1.X, &p2.X, &p12.X, curve.Fp)
+	p.Y.Lookup2(cs, b1, b2, &p12.Y, &p1.Y, &p2.Y, &p12.Y, curve.Fp)
+	return p
+}
+
+// offset returns 2^n times the offset of the scalar multiplications, as a constant
+func (curve Curve) offset(cs *frontend.ConstraintSystem, n int) Point {
+	res := nativePoint{&curve.offsetX, &curve.offsetY}
+	res = curve.nativeScalarMul(res, new(big.Int).Lsh(big.NewInt(1), uint(n)))
+	return Point{X: curve.Fp.Constant(cs, res.x), Y: curve.Fp.Constant(cs, res.y)}
+}
+
+// AssertIsEqual asserts that p1 = p2
+func AssertIsEqual(cs *frontend.ConstraintSystem, p1, p2 *Point, curve Curve) {
+	emulated.AssertIsEqual(cs, &p1.X, &p2.X, curve.Fp)
+	emulated.AssertIsEqual(cs, &p1.Y, &p2.Y, curve.Fp)
+}
@@ -0,0 +1,227 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weierstrass
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+)
+
+type pointCircuit struct {
+	P, Q        Point
+	Sum, Double Point `gnark:",public"`
+	curve       Curve
+}
+
+func (circuit *pointCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.P.MustBeOnCurve(cs, circuit.curve)
+	circuit.Q.MustBeOnCurve(cs, circuit.curve)
+
+	var sum, double, neg Point
+	sum.Add(cs, &circuit.P, &circuit.Q, circuit.curve)
+	double.Double(cs, &circuit.P, circuit.curve)
+	AssertIsEqual(cs, &sum, &circuit.Sum, circuit.curve)
+	AssertIsEqual(cs, &double, &circuit.Double, circuit.curve)
+
+	// P - P + Q = Q is computed with the opposite of P
+	neg.Neg(cs, &circuit.P, circuit.curve)
+	neg.Add(cs, &neg, &sum, circuit.curve)
+	AssertIsEqual(cs, &neg, &circuit.Q, circuit.curve)
+	return nil
+}
+
+func newPointCircuit(curve Curve) pointCircuit {
+	return pointCircuit{
+		P:      curve.Placeholder(),
+		Q:      curve.Placeholder(),
+		Sum:    curve.Placeholder(),
+		Double: curve.Placeholder(),
+		curve:  curve,
+	}
+}
+
+func TestAddDouble(t *testing.T) {
+	assert := test.NewAssert(t)
+	curve := Secp256k1()
+
+	g := nativePoint{&curve.Gx, &curve.Gy}
+	p := curve.nativeScalarMul(g, big.NewInt(12345))
+	q := curve.nativeScalarMul(g, big.NewInt(67890))
+	sum := curve.nativeAdd(p, q)
+	double := curve.nativeAdd(p, p)
+
+	newWitness := func(q nativePoint) *pointCircuit {
+		witness := newPointCircuit(curve)
+		witness.P.Assign(p.x, p.y, curve)
+		witness.Q.Assign(q.x, q.y, curve)
+		witness.Sum.Assign(sum.x, sum.y, curve)
+		witness.Double.Assign(double.x, double.y, curve)
+		return &witness
+	}
+
+	circuit := newPointCircuit(curve)
+	assert.SolvingSucceeded(&circuit, newWitness(q), gurvy.BN256)
+
+	// q is replaced by another point of the curve
+	assert.SolvingFailed(&circuit, newWitness(double), gurvy.BN256)
+
+	// q is not on the curve
+	notOnCurve := nativePoint{q.x, new(big.Int).Add(q.y, big.NewInt(1))}
+	assert.SolvingFailed(&circuit, newWitness(notOnCurve), gurvy.BN256)
+}
+
+type addCircuit struct {
+	P, Q  Point
+	Sum   Point `gnark:",public"`
+	curve Curve
+}
+
+func (circuit *addCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.P.MustBeOnCurve(cs, circuit.curve)
+	circuit.Q.MustBeOnCurve(cs, circuit.curve)
+
+	var sum Point
+	sum.Add(cs, &circuit.P, &circuit.Q, circuit.curve)
+	AssertIsEqual(cs, &sum, &circuit.Sum, circuit.curve)
+	return nil
+}
+
+// TestAddEqualPoints checks that Add can't be used on equal points: the slope is 0/0, and
+// a prover could choose it (and the sum) if the division wasn't constrained to have an invertible
+// divisor. This is what a forged witness of the scalar multiplications would use, when the point
+// added is equal to the accumulator.
+func TestAddEqualPoints(t *testing.T) {
+	assert := test.NewAssert(t)
+	curve := Secp256k1()
+
+	g := nativePoint{&curve.Gx, &curve.Gy}
+	p := curve.nativeScalarMul(g, big.NewInt(12345))
+
+	newWitness := func(sum nativePoint) *addCircuit {
+		witness := addCircuit{P: curve.Placeholder(), Q: curve.Placeholder(), Sum: curve.Placeholder()}
+		witness.P.Assign(p.x, p.y, curve)
+		witness.Q.Assign(p.x, p.y, curve)
+		witness.Sum.Assign(sum.x, sum.y, curve)
+		return &witness
+	}
+
+	circuit := addCircuit{P: curve.Placeholder(), Q: curve.Placeholder(), Sum: curve.Placeholder(), curve: curve}
+
+	// the sum given by the slope 0 (the value of the hint when the divisor is 0), and by the
+	// slope of the tangent
+	var x, y big.Int
+	x.Mul(p.x, big.NewInt(-2)).Mod(&x, &curve.Fp.Modulus)
+	y.Neg(p.y).Mod(&y, &curve.Fp.Modulus)
+	assert.SolvingFailed(&circuit, newWitness(nativePoint{&x, &y}), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(curve.nativeAdd(p, p)), gurvy.BN256)
+}
+
+type scalarMulCircuit struct {
+	P      Point
+	S      emulated.Element `gnark:",public"`
+	Result Point            `gnark:",public"`
+	curve  Curve
+}
+
+func (circuit *scalarMulCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.P.MustBeOnCurve(cs, circuit.curve)
+	circuit.S.RangeCheck(cs, circuit.curve.Fr)
+
+	var res Point
+	res.ScalarMul(cs, &circuit.P, &circuit.S, circuit.curve)
+	AssertIsEqual(cs, &res, &circuit.Result, circuit.curve)
+	return nil
+}
+
+func TestScalarMul(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a scalar multiplication on secp256k1 has about 2 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := Secp256k1()
+
+	s, _ := new(big.Int).SetString("1b6e5a4c3f2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f", 16)
+	g := nativePoint{&curve.Gx, &curve.Gy}
+	res := curve.nativeScalarMul(g, s)
+
+	circuit := scalarMulCircuit{P: curve.Placeholder(), S: curve.Fr.Placeholder(), Result: curve.Placeholder(), curve: curve}
+	witness := scalarMulCircuit{P: curve.Placeholder(), S: curve.Fr.Placeholder(), Result: curve.Placeholder()}
+	witness.P.Assign(g.x, g.y, curve)
+	witness.S.Assign(s, curve.Fr)
+	witness.Result.Assign(res.x, res.y, curve)
+	assert.SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+}
+
+type multiScalarMulCircuit struct {
+	P0, P1, P2 Point
+	S1, S2     frontend.Variable `gnark:",public"`
+	Result     Point             `gnark:",public"`
+	curve      Curve
+}
+
+func (circuit *multiScalarMulCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	var res Point
+	res.MultiScalarMul(cs, &circuit.P0, []Point{circuit.P1, circuit.P2}, []frontend.Variable{circuit.S1, circuit.S2}, circuit.curve)
+	AssertIsEqual(cs, &res, &circuit.Result, circuit.curve)
+	return nil
+}
+
+func TestMultiScalarMul(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a multi scalar multiplication on BN256 has about 3 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := BN256()
+
+	g := nativePoint{&curve.Gx, &curve.Gy}
+	p0 := curve.nativeScalarMul(g, big.NewInt(3))
+	p1 := curve.nativeScalarMul(g, big.NewInt(5))
+	p2 := curve.nativeScalarMul(g, big.NewInt(7))
+
+	newWitness := func(s1, s2 int64) *multiScalarMulCircuit {
+		// p0 + s1*p1 + s2*p2 = (3 + 5*s1 + 7*s2)*g
+		res := curve.nativeScalarMul(g, big.NewInt(3+5*s1+7*s2))
+		witness := multiScalarMulCircuit{P0: curve.Placeholder(), P1: curve.Placeholder(), P2: curve.Placeholder(), Result: curve.Placeholder()}
+		witness.P0.Assign(p0.x, p0.y, curve)
+		witness.P1.Assign(p1.x, p1.y, curve)
+		witness.P2.Assign(p2.x, p2.y, curve)
+		witness.S1.Assign(big.NewInt(s1))
+		witness.S2.Assign(big.NewInt(s2))
+		witness.Result.Assign(res.x, res.y, curve)
+		return &witness
+	}
+
+	circuit := multiScalarMulCircuit{P0: curve.Placeholder(), P1: curve.Placeholder(), P2: curve.Placeholder(), Result: curve.Placeholder(), curve: curve}
+	assert.SolvingSucceeded(&circuit, newWitness(123456789, 0), gurvy.BN256)
+}
+
+func TestGenerators(t *testing.T) {
+	for name, curve := range map[string]Curve{"secp256k1": Secp256k1(), "bn256": BN256(), "bls381": BLS381()} {
+		g := nativePoint{&curve.Gx, &curve.Gy}
+		if y := curve.nativeY(g.x); y == nil {
+			t.Fatal(name, ": the generator is not on the curve")
+		}
+		if r := curve.nativeScalarMul(g, &curve.Order); r.x != nil {
+			t.Fatal(name, ": the order of the generator is wrong")
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groth16
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	groth16_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16"
+	groth16_bls381 "github.com/consensys/gnark/internal/backend/bls381/groth16"
+	groth16_bn256 "github.com/consensys/gnark/internal/backend/bn256/groth16"
+	"github.com/consensys/gnark/std/algebra/fields"
+	"github.com/consensys/gnark/std/algebra/pairing"
+	"github.com/consensys/gnark/std/algebra/sw"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gurvy"
+)
+
+var errUnsupportedCurve = errors.New("unsupported curve for the inner proof")
+
+// NewContext returns the PairingContext verifying proofs on innerCurveID (BLS377, in a BW761
+// circuit only, BN256 or BLS381) in cs
+func NewContext(cs *frontend.ConstraintSystem, innerCurveID gurvy.ID) (PairingContext, error) {
+	switch innerCurveID {
+	case gurvy.BLS377:
+		return NewBLS377Context(cs), nil
+	case gurvy.BN256:
+		return NewEmulatedContext(cs, pairing.BN256()), nil
+	case gurvy.BLS381:
+		return NewEmulatedContext(cs, pairing.BLS381()), nil
+	}
+	return nil, errUnsupportedCurve
+}
+
+// NewPlaceholder returns a Proof and a VerifyingKey with nbPublicInputs public inputs on
+// innerCurveID, whose points are not assigned, to be used in the definition of a circuit
+// (see NewProof and NewVerifyingKey for the assignment of the witness)
+func NewPlaceholder(innerCurveID gurvy.ID, nbPublicInputs int) (Proof, VerifyingKey, error) {
+	var newG1 func() G1
+	var newG2 func() G2
+	var newGT func() GT
+
+	switch innerCurveID {
+	case gurvy.BLS377:
+		newG1 = func() G1 { return &sw.G1Affine{} }
+		newG2 = func() G2 { return &sw.G2Affine{} }
+		newGT = func() GT { return &fields.E12{} }
+	case gurvy.BN256, gurvy.BLS381:
+		curve := pairing.BN256()
+		if innerCurveID == gurvy.BLS381 {
+			curve = pairing.BLS381()
+		}
+		newG1 = func() G1 {
+			p := curve.G1Placeholder()
+			return &p
+		}
+		newG2 = func() G2 {
+			q := curve.G2Placeholder()
+			return &q
+		}
+		newGT = func() GT {
+			e := curve.GTPlaceholder()
+			return &e
+		}
+	default:
+		return Proof{}, VerifyingKey{}, errUnsupportedCurve
+	}
+
+	proof := Proof{Ar: newG1(), Krs: newG1(), Bs: newG2()}
+
+	var vk VerifyingKey
+	vk.E = newGT()
+	vk.G2.GammaNeg = newG2()
+	vk.G2.DeltaNeg = newG2()
+	vk.G1 = make([]G1, nbPublicInputs+1) // the first point is for the ONE_WIRE
+	for i := range vk.G1 {
+		vk.G1[i] = newG1()
+	}
+	return proof, vk, nil
+}
+
+// NewProof returns the assignment of native, a BLS377, BN256 or BLS381 proof
+func NewProof(native groth16.Proof) (Proof, error) {
+	switch p := native.(type) {
+	case *groth16_bls377.Proof:
+		var ar, krs sw.G1Affine
+		var bs sw.G2Affine
+		ar.Assign(&p.Ar)
+		krs.Assign(&p.Krs)
+		bs.Assign(&p.Bs)
+		return Proof{Ar: &ar, Krs: &krs, Bs: &bs}, nil
+	case *groth16_bn256.Proof:
+		curve := pairing.BN256()
+		return Proof{
+			Ar:  newPoint(&p.Ar.X, &p.Ar.Y, curve),
+			Krs: newPoint(&p.Krs.X, &p.Krs.Y, curve),
+			Bs:  newG2Affine(&p.Bs, curve),
+		}, nil
+	case *groth16_bls381.Proof:
+		curve := pairing.BLS381()
+		return Proof{
+			Ar:  newPoint(&p.Ar.X, &p.Ar.Y, curve),
+			Krs: newPoint(&p.Krs.X, &p.Krs.Y, curve),
+			Bs:  newG2Affine(&p.Bs, curve),
+		}, nil
+	}
+	return Proof{}, errUnsupportedCurve
+}
+
+// NewVerifyingKey returns the assignment of native, a BLS377, BN256 or BLS381 verifying key
+func NewVerifyingKey(native groth16.VerifyingKey) (VerifyingKey, error) {
+	var vk VerifyingKey
+	switch v := native.(type) {
+	case *groth16_bls377.VerifyingKey:
+		var e fields.E12
+		var gammaNeg, deltaNeg sw.G2Affine
+		e.Assign(&v.E)
+		gammaNeg.Assign(&v.G2.GammaNeg)
+		deltaNeg.Assign(&v.G2.DeltaNeg)
+		vk.E, vk.G2.GammaNeg, vk.G2.DeltaNeg = &e, &gammaNeg, &deltaNeg
+		vk.G1 = make([]G1, len(v.G1.K))
+		for i := range v.G1.K {
+			var p sw.G1Affine
+			p.Assign(&v.G1.K[i])
+			vk.G1[i] = &p
+		}
+	case *groth16_bn256.VerifyingKey:
+		curve := pairing.BN256()
+		vk.E = newGT(&v.E, curve)
+		vk.G2.GammaNeg = newG2Affine(&v.G2.GammaNeg, curve)
+		vk.G2.DeltaNeg = newG2Affine(&v.G2.DeltaNeg, curve)
+		vk.G1 = make([]G1, len(v.G1.K))
+		for i := range v.G1.K {
+			vk.G1[i] = newPoint(&v.G1.K[i].X, &v.G1.K[i].Y, curve)
+		}
+	case *groth16_bls381.VerifyingKey:
+		curve := pairing.BLS381()
+		vk.E = newGT(&v.E, curve)
+		vk.G2.GammaNeg = newG2Affine(&v.G2.GammaNeg, curve)
+		vk.G2.DeltaNeg = newG2Affine(&v.G2.DeltaNeg, curve)
+		vk.G1 = make([]G1, len(v.G1.K))
+		for i := range v.G1.K {
+			vk.G1[i] = newPoint(&v.G1.K[i].X, &v.G1.K[i].Y, curve)
+		}
+	default:
+		return VerifyingKey{}, errUnsupportedCurve
+	}
+	return vk, nil
+}
+
+func newPoint(x, y interface{}, curve pairing.Curve) *weierstrass.Point {
+	var p weierstrass.Point
+	p.Assign(x, y, curve.G1)
+	return &p
+}
+
+func newG2Affine(q interface{}, curve pairing.Curve) *pairing.G2Affine {
+	var res pairing.G2Affine
+	res.Assign(q, curve)
+	return &res
+}
+
+func newGT(e interface{}, curve pairing.Curve) *pairing.GT {
+	var res pairing.GT
+	res.Assign(e, curve)
+	return &res
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groth16
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields"
+	"github.com/consensys/gnark/std/algebra/pairing"
+	"github.com/consensys/gnark/std/algebra/sw"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+)
+
+// PairingContext implements the arithmetic of the curve of the inner proof which Verify needs
+type PairingContext interface {
+	// AssertIsInG1 asserts that p is on the curve and in the subgroup of order r, and range checks
+	// its coordinates (the points of the witness must be checked before they are used)
+	AssertIsInG1(p G1)
+
+	// AssertIsInG2 asserts that q is on the twist and in the subgroup of order r, and range checks
+	// its coordinates
+	AssertIsInG2(q G2)
+
+	// RangeCheckGT range checks the coordinates of e. An element of the witness which is only
+	// compared with a pairing, as e(α, β), needs no subgroup check: if it is not in GT, it is
+	// never equal to the pairing.
+	RangeCheckGT(e GT)
+
+	// MultiScalarMulG1 returns p0 + Σ scalars[i]*points[i]
+	MultiScalarMulG1(p0 G1, points []G1, scalars []frontend.Variable) G1
+
+	// AssertPairingIsEqual asserts that Π e(P[i], Q[i]) = expected
+	AssertPairingIsEqual(P []G1, Q []G2, expected GT)
+}
+
+// bls377Context verifies BLS377 proofs in a BW761 circuit, whose native field is the base field of
+// BLS377 (the points are *sw.G1Affine and *sw.G2Affine, the elements of GT are *fields.E12)
+type bls377Context struct {
+	cs          *frontend.ConstraintSystem
+	pairingInfo sw.PairingContext
+}
+
+// NewBLS377Context returns the context to verify BLS377 proofs in cs (which must be a BW761 circuit)
+func NewBLS377Context(cs *frontend.ConstraintSystem) PairingContext {
+	return &bls377Context{
+		cs: cs,
+		pairingInfo: sw.PairingContext{
+			Extension: fields.GetBLS377ExtensionFp12(cs),
+			AteLoop:   9586122913090633729,
+		},
+	}
+}
+
+func (ctx *bls377Context) AssertIsInG1(p G1) {
+	p.(*sw.G1Affine).MustBeOnCurve(ctx.cs)
+	p.(*sw.G1Affine).MustBeInSubGroup(ctx.cs)
+}
+
+func (ctx *bls377Context) AssertIsInG2(q G2) {
+	q.(*sw.G2Affine).MustBeOnCurve(ctx.cs, ctx.pairingInfo.Extension)
+	q.(*sw.G2Affine).MustBeInSubGroup(ctx.cs, ctx.pairingInfo.Extension)
+}
+
+// RangeCheckGT does nothing: the coordinates are native variables
+func (ctx *bls377Context) RangeCheckGT(e GT) {}
+
+func (ctx *bls377Context) MultiScalarMulG1(p0 G1, points []G1, scalars []frontend.Variable) G1 {
+	// TODO maybe implement the bucket method with c=1 when there's a large input set
+	var res, tmp sw.G1Affine
+	res = *p0.(*sw.G1Affine)
+	for k, s := range scalars {
+		tmp.ScalarMul(ctx.cs, points[k].(*sw.G1Affine), s, 256)
+		res.AddAssign(ctx.cs, &tmp)
+	}
+	return &res
+}
+
+func (ctx *bls377Context) AssertPairingIsEqual(P []G1, Q []G2, expected GT) {
+	_P := make([]sw.G1Affine, len(P))
+	_Q := make([]sw.G2Affine, len(Q))
+	for i := range P {
+		_P[i] = *P[i].(*sw.G1Affine)
+		_Q[i] = *Q[i].(*sw.G2Affine)
+	}
+
+	// the squarings of the Miller loops are shared
+	var ml, res fields.E12
+	sw.MultiMillerLoopAffine(ctx.cs, _P, _Q, &ml, ctx.pairingInfo)
+	res.FinalExpoBLS(ctx.cs, &ml, ctx.pairingInfo.AteLoop, ctx.pairingInfo.Extension)
+
+	expected.(*fields.E12).MustBeEqual(ctx.cs, res)
+}
+
+// emulatedContext verifies proofs on a curve whose base field is not the native field, with
+// emulated arithmetic (the points are *weierstrass.Point and *pairing.G2Affine, the elements of GT
+// are *pairing.GT)
+type emulatedContext struct {
+	cs      *frontend.ConstraintSystem
+	curve   pairing.Curve
+	pairing *pairing.Pairing
+}
+
+// NewEmulatedContext returns the context to verify proofs on curve (pairing.BN256() or
+// pairing.BLS381()) in cs, whatever its native field. The public inputs of the inner proof must be
+// smaller than the native modulus.
+func NewEmulatedContext(cs *frontend.ConstraintSystem, curve pairing.Curve) PairingContext {
+	return &emulatedContext{cs: cs, curve: curve, pairing: pairing.NewPairing(cs, curve)}
+}
+
+func (ctx *emulatedContext) AssertIsInG1(p G1) {
+	ctx.pairing.AssertIsInG1(*p.(*weierstrass.Point))
+}
+
+func (ctx *emulatedContext) AssertIsInG2(q G2) {
+	ctx.pairing.AssertIsInG2(*q.(*pairing.G2Affine))
+}
+
+func (ctx *emulatedContext) RangeCheckGT(e GT) {
+	ctx.pairing.RangeCheckGT(*e.(*pairing.GT))
+}
+
+func (ctx *emulatedContext) MultiScalarMulG1(p0 G1, points []G1, scalars []frontend.Variable) G1 {
+	_points := make([]weierstrass.Point, len(points))
+	for i := range points {
+		_points[i] = *points[i].(*weierstrass.Point)
+	}
+	var res weierstrass.Point
+	res.MultiScalarMul(ctx.cs, p0.(*weierstrass.Point), _points, scalars, ctx.curve.G1)
+	return &res
+}
+
+func (ctx *emulatedContext) AssertPairingIsEqual(P []G1, Q []G2, expected GT) {
+	_P := make([]weierstrass.Point, len(P))
+	_Q := make([]pairing.G2Affine, len(Q))
+	for i := range P {
+		_P[i] = *P[i].(*weierstrass.Point)
+		_Q[i] = *Q[i].(*pairing.G2Affine)
+	}
+	res := ctx.pairing.Pair(_P, _Q)
+	ctx.pairing.AssertIsEqual(res, *expected.(*pairing.GT))
+}
@@ -18,72 +18,69 @@ package groth16
 
 import (
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/algebra/fields"
-	"github.com/consensys/gnark/std/algebra/sw"
+)
+
+// G1, G2 and GT are a point of G1, a point of G2 and an element of GT of the curve of the inner proof,
+// as represented by a PairingContext (e.g. *sw.G1Affine, *sw.G2Affine, *fields.E12 for BLS377).
+// They hold pointers, so that the variables they contain can be allocated and assigned.
+type (
+	G1 interface{}
+	G2 interface{}
+	GT interface{}
 )
 
 // Proof represents a groth16 proof in a r1cs
 type Proof struct {
-	Ar, Krs sw.G1Affine // πA, πC in https://eprint.iacr.org/2020/278.pdf
-	Bs      sw.G2Affine // πB in https://eprint.iacr.org/2020/278.pdf
+	Ar, Krs G1 // πA, πC in https://eprint.iacr.org/2020/278.pdf
+	Bs      G2 // πB in https://eprint.iacr.org/2020/278.pdf
 }
 
 // VerifyingKey represents the groth16 verifying key in a r1cs
 type VerifyingKey struct {
 
 	// e(α, β)
-	E fields.E12
+	E GT
 
 	// -[γ]2, -[δ]2
 	G2 struct {
-		GammaNeg, DeltaNeg sw.G2Affine
+		GammaNeg, DeltaNeg G2
 	}
 
 	// [Kvk]1 (part of the verifying key yielding psi0, cf https://eprint.iacr.org/2020/278.pdf)
-	G1 []sw.G1Affine // The indexes correspond to the public wires
+	G1 []G1 // The indexes correspond to the public wires
 }
 
 // Verify implements the verification function of groth16.
 // pubInputNames should what r1cs.PublicInputs() outputs for the inner r1cs.
 // It creates public circuits input, corresponding to the pubInputNames slice.
 // Notations and naming are from https://eprint.iacr.org/2020/278.
-func Verify(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, innerVk VerifyingKey, innerProof Proof, innerPubInputs []frontend.Variable) {
-
-	var eπCdelta, eπAπB, epsigamma fields.E12
-
-	// e(-πC, -δ)
-	sw.MillerLoopAffine(cs, innerProof.Krs, innerVk.G2.DeltaNeg, &eπCdelta, pairingInfo)
-
-	// e(πA, πB)
-	sw.MillerLoopAffine(cs, innerProof.Ar, innerProof.Bs, &eπAπB, pairingInfo)
-
-	// compute psi0 using a sequence of multiexponentiations
-	// TODO maybe implement the bucket method with c=1 when there's a large input set
-	var psi0, tmp sw.G1Affine
+//
+// The arithmetic of the curve of the inner proof is given by ctx (see NewBLS377Context and
+// NewEmulatedContext).
+func Verify(cs *frontend.ConstraintSystem, ctx PairingContext, innerVk VerifyingKey, innerProof Proof, innerPubInputs []frontend.Variable) {
+
+	// the proof and the verifying key are part of the witness
+	ctx.AssertIsInG1(innerProof.Ar)
+	ctx.AssertIsInG1(innerProof.Krs)
+	ctx.AssertIsInG2(innerProof.Bs)
+	for _, p := range innerVk.G1 {
+		ctx.AssertIsInG1(p)
+	}
+	ctx.AssertIsInG2(innerVk.G2.GammaNeg)
+	ctx.AssertIsInG2(innerVk.G2.DeltaNeg)
+	ctx.RangeCheckGT(innerVk.E)
 
-	// assign the initial psi0 to the part of the public key corresponding to one_wire
+	// psi0 = [Kvk]1[0] + Σ innerPubInputs[k] * [Kvk]1[k+1]
 	// TODO this assumes ONE_WIRE is at position 0
-	psi0.X = innerVk.G1[0].X
-	psi0.Y = innerVk.G1[0].Y
-
-	for k, v := range innerPubInputs {
-		tmp.ScalarMul(cs, &innerVk.G1[k+1], v, 256)
-		psi0.AddAssign(cs, &tmp)
+	psi0 := innerVk.G1[0]
+	if len(innerPubInputs) != 0 {
+		psi0 = ctx.MultiScalarMulG1(innerVk.G1[0], innerVk.G1[1:], innerPubInputs)
 	}
 
-	// e(psi0, -gamma)
-	sw.MillerLoopAffine(cs, psi0, innerVk.G2.GammaNeg, &epsigamma, pairingInfo)
-
-	// combine the results before performing the final expo
-	var preFinalExpo fields.E12
-	preFinalExpo.Mul(cs, &eπCdelta, &eπAπB, pairingInfo.Extension).
-		Mul(cs, &preFinalExpo, &epsigamma, pairingInfo.Extension)
-
-	// performs the final expo
-	var resPairing fields.E12
-	resPairing.FinalExpoBLS(cs, &preFinalExpo, pairingInfo.AteLoop, pairingInfo.Extension)
-
-	// vk.E must be equal to resPairing
-	innerVk.E.MustBeEqual(cs, resPairing)
-
+	// e(πA, πB) * e(πC, -δ) * e(psi0, -γ) = e(α, β)
+	ctx.AssertPairingIsEqual(
+		[]G1{innerProof.Ar, innerProof.Krs, psi0},
+		[]G2{innerProof.Bs, innerVk.G2.DeltaNeg, innerVk.G2.GammaNeg},
+		innerVk.E,
+	)
 }
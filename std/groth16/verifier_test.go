@@ -17,17 +17,23 @@ limitations under the License.
 package groth16
 
 import (
+	"math/big"
+	"os"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
 	groth16_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16"
+	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
+	groth16_bn256 "github.com/consensys/gnark/internal/backend/bn256/groth16"
 	backend_bw761 "github.com/consensys/gnark/internal/backend/bw761"
-	"github.com/consensys/gnark/std/algebra/fields"
-	"github.com/consensys/gnark/std/algebra/sw"
+	"github.com/consensys/gnark/std/algebra/pairing"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
 	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
 	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256"
 )
 
 //--------------------------------------------------------------------
@@ -35,6 +41,7 @@ import (
 
 const preimage string = "4992816046196248432836492760315135318126925090839638585255611512962528270024"
 const publicHash string = "5100653184692120205048160297349714747883651904319528520089825735266585689318"
+const publicHashBN256 string = "9625271908460726878576818501765902038128134895690166632860096497695104254553"
 
 type mimcCircuit struct {
 	Data frontend.Variable
@@ -87,25 +94,69 @@ func generateBls377InnerProof(t *testing.T, vk *groth16_bls377.VerifyingKey, pro
 	}
 }
 
+// Prepare the data for the inner proof, on BN256
+func generateBn256InnerProof(t *testing.T, vk *groth16_bn256.VerifyingKey, proof *groth16_bn256.Proof) {
+
+	var circuit, witness mimcCircuit
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witness.Data.Assign(preimage)
+	witness.Hash.Assign(publicHashBN256)
+
+	correctAssignment, err := frontend.ParseWitness(&witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pk groth16_bn256.ProvingKey
+	groth16_bn256.Setup(r1cs.(*backend_bn256.R1CS), &pk, vk)
+	_proof, err := groth16_bn256.Prove(r1cs.(*backend_bn256.R1CS), &pk, correctAssignment, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*proof = *_proof
+
+	if err := groth16_bn256.Verify(proof, vk, correctAssignment); err != nil {
+		t.Fatal(err)
+	}
+}
+
 type verifierCircuit struct {
 	InnerProof Proof
 	InnerVk    VerifyingKey
-	Hash       frontend.Variable
+	Hash       frontend.Variable `gnark:",public"`
+	inner      gurvy.ID          // curve of the inner proof
 }
 
 func (circuit *verifierCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
-
-	// pairing data
-	var pairingInfo sw.PairingContext
-	pairingInfo.Extension = fields.GetBLS377ExtensionFp12(cs)
-	pairingInfo.AteLoop = 9586122913090633729
-
-	// create the verifier cs
-	Verify(cs, pairingInfo, circuit.InnerVk, circuit.InnerProof, []frontend.Variable{circuit.Hash})
-
+	ctx, err := NewContext(cs, circuit.inner)
+	if err != nil {
+		return err
+	}
+	Verify(cs, ctx, circuit.InnerVk, circuit.InnerProof, []frontend.Variable{circuit.Hash})
 	return nil
 }
 
+// newVerifierCircuits returns the circuit verifying innerProof with innerVk, and its witness
+func newVerifierCircuits(t *testing.T, innerCurveID gurvy.ID, innerVk groth16.VerifyingKey, innerProof groth16.Proof, hash string) (circuit, witness verifierCircuit) {
+	var err error
+	circuit.inner, witness.inner = innerCurveID, innerCurveID
+	if circuit.InnerProof, circuit.InnerVk, err = NewPlaceholder(innerCurveID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if witness.InnerProof, err = NewProof(innerProof); err != nil {
+		t.Fatal(err)
+	}
+	if witness.InnerVk, err = NewVerifyingKey(innerVk); err != nil {
+		t.Fatal(err)
+	}
+	witness.Hash.Assign(hash)
+	return
+}
+
 func TestVerifier(t *testing.T) {
 
 	// get the data
@@ -113,56 +164,232 @@ func TestVerifier(t *testing.T) {
 	var innerProof groth16_bls377.Proof
 	generateBls377InnerProof(t, &innerVk, &innerProof) // get public inputs of the inner proof
 
-	// create an empty cs
-	var circuit verifierCircuit
-	circuit.InnerVk.G1 = make([]sw.G1Affine, len(innerVk.G1.K))
+	// the private part of the witness consists of the proof and the verifying key,
+	// the public part is exactly the public part of the inner proof,
+	// up to the renaming of the inner ONE_WIRE to not conflict with the one wire of the outer proof.
+	circuit, witness := newVerifierCircuits(t, gurvy.BLS377, &innerVk, &innerProof, publicHash)
 	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// create assignment, the private part consists of the proof,
-	// the public part is exactly the public part of the inner proof,
-	// up to the renaming of the inner ONE_WIRE to not conflict with the one wire of the outer proof.
-	var witness verifierCircuit
-	witness.InnerProof.Ar.Assign(&innerProof.Ar)
-	witness.InnerProof.Krs.Assign(&innerProof.Krs)
-	witness.InnerProof.Bs.Assign(&innerProof.Bs)
-	witness.InnerVk.E.Assign(&innerVk.E)
-	witness.InnerVk.G1 = make([]sw.G1Affine, len(innerVk.G1.K))
-	for i, vkg := range innerVk.G1.K {
-		witness.InnerVk.G1[i].Assign(&vkg)
-	}
-	witness.InnerVk.G2.DeltaNeg.Assign(&innerVk.G2.DeltaNeg)
-	witness.InnerVk.G2.GammaNeg.Assign(&innerVk.G2.GammaNeg)
-	witness.Hash.Assign(publicHash)
-
 	// verifies the cs
 	assertbw761 := groth16.NewAssert(t)
 
 	assertbw761.SolvingSucceeded(r1cs.(*backend_bw761.R1CS), &witness)
 
-	/* comment from here */
+	// a proof of another statement is rejected
+	_, wrongWitness := newVerifierCircuits(t, gurvy.BLS377, &innerVk, &innerProof, preimage)
+	assertbw761.SolvingFailed(r1cs.(*backend_bw761.R1CS), &wrongWitness)
 
-	// TODO uncommenting the lines below yield incredibly long testing time (due to the setup)
-	// generate groth16 instance on bw761 (setup, prove, verify)
-	// var vk groth16_bw761.VerifyingKey
-	// var pk groth16_bw761.ProvingKey
+	// a tampered proof is rejected: its points are in G1, but [A]1 and [C]1 are swapped
+	tampered := innerProof
+	tampered.Ar, tampered.Krs = innerProof.Krs, innerProof.Ar
+	_, tamperedWitness := newVerifierCircuits(t, gurvy.BLS377, &innerVk, &tampered, publicHash)
+	assertbw761.SolvingFailed(r1cs.(*backend_bw761.R1CS), &tamperedWitness)
+}
 
-	// groth16_bw761.Setup(&r1cs, &pk, &vk)
-	// proof, err := groth16_bw761.Prove(&r1cs, &pk, correctAssignment)
-	// if err != nil {
-	// 	t.Fatal(err)
-	// }
+// TestRecursion proves on BW761 the verification of a BLS377 proof
+func TestRecursion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the setup of the verifier circuit on BW761 is slow")
+	}
 
-	// res, err := groth16_bw761.Verify(proof, &vk, correctAssignment)
-	// if err != nil {
-	// 	t.Fatal(err)
-	// }
-	// if !res {
-	// 	t.Fatal("correct proof should pass")
-	// }
+	var innerVk groth16_bls377.VerifyingKey
+	var innerProof groth16_bls377.Proof
+	generateBls377InnerProof(t, &innerVk, &innerProof)
 
+	circuit, witness := newVerifierCircuits(t, gurvy.BLS377, &innerVk, &innerProof, publicHash)
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, &witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := groth16.Verify(proof, vk, map[string]interface{}{"Hash": publicHash}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recursionBN256 is the environment variable which enables the tests of the whole verification of a
+// BN256 proof in a BN256 circuit, whose emulated pairing has about 17 million constraints.
+//
+// On one core, TestVerifierBN256 takes about 6 minutes, with a peak of 4.6 GB of memory (with
+// GOMEMLIMIT=4000MiB). TestRecursionBN256 compiles the circuit and computes its proving key, which
+// needs much more memory. The parts of Verify are tested on small circuits in the other tests.
+const recursionBN256 = "GNARK_TEST_RECURSION_BN256"
+
+func skipRecursionBN256(t *testing.T) {
+	if os.Getenv(recursionBN256) == "" {
+		t.Skip("set " + recursionBN256 + " to verify a BN256 proof in a BN256 circuit (17 million constraints)")
+	}
+}
+
+// TestVerifierBN256 verifies a BN256 proof in a BN256 circuit, whose pairing is emulated. The
+// rejection of wrong witnesses is tested on smaller circuits (TestVerifierPublicInputBN256,
+// TestVerifierG1BN256), so that the pairing is solved once.
+func TestVerifierBN256(t *testing.T) {
+	skipRecursionBN256(t)
+
+	var innerVk groth16_bn256.VerifyingKey
+	var innerProof groth16_bn256.Proof
+	generateBn256InnerProof(t, &innerVk, &innerProof)
+
+	circuit, witness := newVerifierCircuits(t, gurvy.BN256, &innerVk, &innerProof, publicHashBN256)
+	test.NewAssert(t).SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+}
+
+// TestRecursionBN256 proves on BN256 the verification of a BN256 proof. The size of the outer
+// circuit doesn't depend on the inner one (it is mostly the emulated pairing).
+func TestRecursionBN256(t *testing.T) {
+	skipRecursionBN256(t)
+
+	var innerVk groth16_bn256.VerifyingKey
+	var innerProof groth16_bn256.Proof
+	generateBn256InnerProof(t, &innerVk, &innerProof)
+
+	circuit, witness := newVerifierCircuits(t, gurvy.BN256, &innerVk, &innerProof, publicHashBN256)
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pk, vk, err := groth16.Setup(r1cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := groth16.Prove(r1cs, pk, &witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := groth16.Verify(proof, vk, map[string]interface{}{"Hash": publicHashBN256}); err != nil {
+		t.Fatal(err)
+	}
+	if err := groth16.Verify(proof, vk, map[string]interface{}{"Hash": preimage}); err == nil {
+		t.Fatal("the proof is accepted with a wrong public input")
+	}
+}
+
+// verifierPublicInputCircuit checks, as Verify with NewEmulatedContext, that psi0 is the point of
+// G1 of the public input of a BN256 proof
+type verifierPublicInputCircuit struct {
+	Kvk  []G1
+	Psi0 weierstrass.Point
+	Hash frontend.Variable `gnark:",public"`
+}
+
+func (circuit *verifierPublicInputCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	ctx := NewEmulatedContext(cs, pairing.BN256())
+	psi0 := ctx.MultiScalarMulG1(circuit.Kvk[0], circuit.Kvk[1:], []frontend.Variable{circuit.Hash})
+	weierstrass.AssertIsEqual(cs, psi0.(*weierstrass.Point), &circuit.Psi0, pairing.BN256().G1)
+	return nil
+}
+
+// TestVerifierPublicInputBN256 checks that the public input of the outer circuit is the one of the
+// inner proof: with another public input, the pairing check of Verify gets another psi0 and fails
+func TestVerifierPublicInputBN256(t *testing.T) {
+	if testing.Short() {
+		t.Skip("a scalar multiplication on BN256 is too slow for the short tests")
+	}
+
+	var innerVk groth16_bn256.VerifyingKey
+	var innerProof groth16_bn256.Proof
+	generateBn256InnerProof(t, &innerVk, &innerProof)
+
+	// psi0 = [Kvk]1[0] + hash * [Kvk]1[1]
+	var hash big.Int
+	hash.SetString(publicHashBN256, 10)
+	var psi0 bn256.G1Affine
+	var acc bn256.G1Jac
+	psi0.ScalarMultiplication(&innerVk.G1.K[1], &hash)
+	acc.FromAffine(&psi0)
+	acc.AddMixed(&innerVk.G1.K[0])
+	psi0.FromJacobian(&acc)
+
+	_, vk, err := NewPlaceholder(gurvy.BN256, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := verifierPublicInputCircuit{Kvk: vk.G1, Psi0: pairing.BN256().G1Placeholder()}
+
+	if vk, err = NewVerifyingKey(&innerVk); err != nil {
+		t.Fatal(err)
+	}
+	curve := pairing.BN256()
+	witness := verifierPublicInputCircuit{Kvk: vk.G1, Psi0: *newPoint(&psi0.X, &psi0.Y, curve)}
+	witness.Hash.Assign(publicHashBN256)
+
+	assert := test.NewAssert(t)
+	assert.SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+
+	// a proof of another statement is rejected
+	wrongWitness := verifierPublicInputCircuit{Kvk: vk.G1, Psi0: witness.Psi0}
+	wrongWitness.Hash.Assign(preimage)
+	assert.SolvingFailed(&circuit, &wrongWitness, gurvy.BN256)
+}
+
+// verifierG1Circuit checks, as Verify with NewEmulatedContext, the points of G1 of a BN256 proof
+// and of its verifying key
+type verifierG1Circuit struct {
+	Ar, Krs G1
+	Kvk     []G1
+}
+
+func (circuit *verifierG1Circuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	ctx := NewEmulatedContext(cs, pairing.BN256())
+	ctx.AssertIsInG1(circuit.Ar)
+	ctx.AssertIsInG1(circuit.Krs)
+	for _, p := range circuit.Kvk {
+		ctx.AssertIsInG1(p)
+	}
+	return nil
+}
+
+// TestVerifierG1BN256 runs the groth16 backend (setup, prove and verify) on the emulated arithmetic
+// of Verify, with the hints solved by the R1CS solver, in a circuit small enough for the short
+// tests. The whole verification of a BN256 proof (about 17 million constraints) is solved by the
+// test engine in TestVerifierBN256, and proven in TestRecursionBN256 (see recursionBN256).
+func TestVerifierG1BN256(t *testing.T) {
+	var innerVk groth16_bn256.VerifyingKey
+	var innerProof groth16_bn256.Proof
+	generateBn256InnerProof(t, &innerVk, &innerProof)
+
+	proof, vk, err := NewPlaceholder(gurvy.BN256, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := verifierG1Circuit{Ar: proof.Ar, Krs: proof.Krs, Kvk: vk.G1}
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if proof, err = NewProof(&innerProof); err != nil {
+		t.Fatal(err)
+	}
+	if vk, err = NewVerifyingKey(&innerVk); err != nil {
+		t.Fatal(err)
+	}
+	witness := verifierG1Circuit{Ar: proof.Ar, Krs: proof.Krs, Kvk: vk.G1}
+	groth16.NewAssert(t).ProverSucceeded(r1cs, &witness)
+
+	// a tampered proof, whose [C]1 is not on the curve, is rejected
+	tampered := innerProof
+	tampered.Krs.Y.Double(&tampered.Krs.Y)
+	if proof, err = NewProof(&tampered); err != nil {
+		t.Fatal(err)
+	}
+	witness = verifierG1Circuit{Ar: proof.Ar, Krs: proof.Krs, Kvk: vk.G1}
+	groth16.NewAssert(t).ProverFailed(r1cs, &witness)
 }
 
 //--------------------------------------------------------------------
@@ -0,0 +1,113 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package boolean implements the bits of the bitwise hash gadgets (sha256, keccak).
+//
+// A bit is either a constant, or a variable constrained to be boolean. The constants (paddings,
+// initial states, round constants) are propagated at compile time: the operations on constant
+// bits record no constraint.
+package boolean
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// Bit is a constant bit, or a variable constrained to be boolean
+type Bit struct {
+	v        frontend.Variable
+	constant bool
+	value    int // value of a constant bit
+}
+
+// Constant returns the constant bit value (0 or 1)
+func Constant(value int) Bit {
+	return Bit{constant: true, value: value}
+}
+
+// Variable returns the bit v, which must be constrained to be boolean
+func Variable(v frontend.Variable) Bit {
+	return Bit{v: v}
+}
+
+// IsConstant returns true if b is a constant bit
+func (b Bit) IsConstant() bool {
+	return b.constant
+}
+
+// Value returns the value of a constant bit
+func (b Bit) Value() int {
+	return b.value
+}
+
+// Variable returns the bit as a variable
+func (b Bit) Variable(cs *frontend.ConstraintSystem) frontend.Variable {
+	if b.constant {
+		return cs.Constant(b.value)
+	}
+	return b.v
+}
+
+// Not returns 1 - a (no constraint)
+func Not(cs *frontend.ConstraintSystem, a Bit) Bit {
+	if a.constant {
+		return Constant(1 - a.value)
+	}
+	return Variable(cs.Sub(1, a.v))
+}
+
+// Xor returns a XOR b (1 constraint, see ConstraintSystem.Xor)
+func Xor(cs *frontend.ConstraintSystem, a, b Bit) Bit {
+	switch {
+	case a.constant && b.constant:
+		return Constant(a.value ^ b.value)
+	case a.constant && a.value == 0:
+		return b
+	case a.constant:
+		return Not(cs, b)
+	case b.constant:
+		return Xor(cs, b, a)
+	default:
+		return Variable(cs.Xor(a.v, b.v))
+	}
+}
+
+// And returns a AND b (1 constraint, see ConstraintSystem.And)
+func And(cs *frontend.ConstraintSystem, a, b Bit) Bit {
+	switch {
+	case a.constant && a.value == 0, b.constant && b.value == 0:
+		return Constant(0)
+	case a.constant:
+		return b
+	case b.constant:
+		return a
+	default:
+		return Variable(cs.And(a.v, b.v))
+	}
+}
+
+// Or returns a OR b (1 constraint, see ConstraintSystem.Or)
+func Or(cs *frontend.ConstraintSystem, a, b Bit) Bit {
+	switch {
+	case a.constant && a.value == 1, b.constant && b.value == 1:
+		return Constant(1)
+	case a.constant:
+		return b
+	case b.constant:
+		return a
+	default:
+		return Variable(cs.Or(a.v, b.v))
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hashtest implements the tests shared by the bitwise hash gadgets (sha256, keccak): a
+// circuit asserting the digest of a message, and the checks of a message and its digest with the
+// test engine and with the R1CS solver.
+package hashtest
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+)
+
+// Gadget returns the digest of msg, as bits (e.g. sha256.Sum or sha256.SumBytes)
+type Gadget func(cs *frontend.ConstraintSystem, msg ...frontend.Variable) []frontend.Variable
+
+// Hash describes the gadgets of a hash function
+type Hash struct {
+	Sum      Gadget // hashes a message given as bits
+	SumBytes Gadget // hashes a message given as bytes
+	Size     int    // size of the digest, in bits
+
+	// MSBFirst is true if the bits of a byte, in the messages given as bits and in the digests, are
+	// ordered from the most significant (FIPS 180-4), false if they are ordered from the least
+	// significant (Keccak)
+	MSBFirst bool
+}
+
+// circuit asserts that Digest is the digest of Msg
+type circuit struct {
+	Msg    []frontend.Variable
+	Digest []frontend.Variable `gnark:",public"`
+	gadget Gadget
+}
+
+func (circuit *circuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	digest := circuit.gadget(cs, circuit.Msg...)
+	for i := range digest {
+		cs.AssertIsEqual(digest[i], circuit.Digest[i])
+	}
+	return nil
+}
+
+// bit returns the i-th bit of b
+func (h Hash) bit(b []byte, i int) int {
+	if h.MSBFirst {
+		return int(b[i/8]>>(7-i%8)) & 1
+	}
+	return int(b[i/8]>>(i%8)) & 1
+}
+
+// bytesCircuit returns the circuit hashing messages of n bytes, and its witness if msg is not nil
+func (h Hash) bytesCircuit(n int, msg, digest []byte) *circuit {
+	res := &circuit{Msg: make([]frontend.Variable, n), Digest: make([]frontend.Variable, h.Size), gadget: h.SumBytes}
+	if msg != nil {
+		for i := range msg {
+			res.Msg[i].Assign(int(msg[i]))
+		}
+		h.assignDigest(res, digest)
+	}
+	return res
+}
+
+// bitsCircuit returns the circuit hashing messages of 8*n bits, and its witness if msg is not nil
+func (h Hash) bitsCircuit(n int, msg, digest []byte) *circuit {
+	res := &circuit{Msg: make([]frontend.Variable, 8*n), Digest: make([]frontend.Variable, h.Size), gadget: h.Sum}
+	if msg != nil {
+		for i := range res.Msg {
+			res.Msg[i].Assign(h.bit(msg, i))
+		}
+		h.assignDigest(res, digest)
+	}
+	return res
+}
+
+func (h Hash) assignDigest(witness *circuit, digest []byte) {
+	for i := range witness.Digest {
+		witness.Digest[i].Assign(h.bit(digest, i))
+	}
+}
+
+// flip returns digest with its first bit flipped
+func flip(digest []byte) []byte {
+	res := append([]byte{}, digest...)
+	res[0] ^= 1
+	return res
+}
+
+// AssertBytes checks with the test engine that SumBytes hashes msg to digest, and not to another
+// digest
+func (h Hash) AssertBytes(t *testing.T, msg, digest []byte) {
+	assert := test.NewAssert(t)
+	circuit := h.bytesCircuit(len(msg), nil, nil)
+	assert.SolvingSucceeded(circuit, h.bytesCircuit(len(msg), msg, digest), gurvy.BN256)
+	assert.SolvingFailed(circuit, h.bytesCircuit(len(msg), msg, flip(digest)), gurvy.BN256)
+}
+
+// AssertBits checks with the test engine that Sum hashes the bits of msg to digest, and that the
+// bits of the message must be boolean
+func (h Hash) AssertBits(t *testing.T, msg, digest []byte) {
+	assert := test.NewAssert(t)
+	circuit := h.bitsCircuit(len(msg), nil, nil)
+	assert.SolvingSucceeded(circuit, h.bitsCircuit(len(msg), msg, digest), gurvy.BN256)
+
+	witness := h.bitsCircuit(len(msg), nil, nil)
+	witness.Msg[0].Assign(2 + h.bit(msg, 0))
+	for i := 1; i < len(witness.Msg); i++ {
+		witness.Msg[i].Assign(h.bit(msg, i))
+	}
+	h.assignDigest(witness, digest)
+	assert.SolvingFailed(circuit, witness, gurvy.BN256)
+}
+
+// AssertR1CS checks as AssertBytes, with the R1CS compiled from the circuit (and its optimizations)
+// and its solver instead of the test engine
+func (h Hash) AssertR1CS(t *testing.T, msg, digest []byte) {
+	assert := groth16.NewAssert(t)
+	r1cs, err := frontend.Compile(gurvy.BN256, h.bytesCircuit(len(msg), nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.SolvingSucceeded(r1cs, h.bytesCircuit(len(msg), msg, digest))
+	assert.SolvingFailed(r1cs, h.bytesCircuit(len(msg), msg, flip(digest)))
+}
+
+// Benchmark reports the number of constraints of SumBytes on a message of nbBytes bytes
+func (h Hash) Benchmark(b *testing.B, nbBytes int) {
+	var nbConstraints uint64
+	for i := 0; i < b.N; i++ {
+		r1cs, err := frontend.Compile(gurvy.BN256, h.bytesCircuit(nbBytes, nil, nil))
+		if err != nil {
+			b.Fatal(err)
+		}
+		nbConstraints = r1cs.GetNbConstraints()
+	}
+	b.ReportMetric(float64(nbConstraints), "constraints")
+}
@@ -0,0 +1,33 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keccak
+
+import (
+	"github.com/consensys/gnark/std/hash/internal/boolean"
+)
+
+// lane is a 64 bits word of the state, least significant bit first
+type lane [64]boolean.Bit
+
+// rotl returns the left rotation of l by n bits
+func (l lane) rotl(n int) lane {
+	var res lane
+	for i := range l {
+		res[(i+n)%64] = l[i]
+	}
+	return res
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keccak implements the Keccak-256 hash in a gnark circuit, as used by Ethereum (the
+// original Keccak padding, as sha3.NewLegacyKeccak256, not the padding of SHA3-256).
+//
+// The messages and the digests are sequences of bits, in the order of the Keccak specification: the
+// first bit is the least significant bit of the first byte. The length of the message is known at
+// compile time, so that the padding is constant.
+//
+// A block of 1088 bits costs about 190000 constraints (see the benchmarks).
+package keccak
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/internal/boolean"
+)
+
+// Size is the size of a Keccak-256 digest, in bits
+const Size = 256
+
+// rate is the number of bits absorbed by each permutation
+const rate = 1600 - 2*Size
+
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotations[x][y] is the rotation of the lane (x, y) in the step rho
+var rotations = [5][5]int{
+	{0, 36, 3, 41, 18},
+	{1, 44, 10, 45, 2},
+	{62, 6, 43, 15, 61},
+	{28, 55, 25, 21, 56},
+	{27, 20, 39, 8, 14},
+}
+
+// Sum returns the Keccak-256 digest of the message whose bits are msg (in r1cs form), as Size bits.
+//
+// The bits of msg are constrained to be boolean.
+func Sum(cs *frontend.ConstraintSystem, msg ...frontend.Variable) []frontend.Variable {
+	bits := make([]boolean.Bit, len(msg))
+	for i := range msg {
+		cs.AssertIsBoolean(msg[i])
+		bits[i] = boolean.Variable(msg[i])
+	}
+	return sum(cs, bits)
+}
+
+// SumBytes returns the Keccak-256 digest of the message whose bytes are msg (in r1cs form), as
+// Size bits.
+//
+// The elements of msg are constrained to be bytes (8 bits).
+func SumBytes(cs *frontend.ConstraintSystem, msg ...frontend.Variable) []frontend.Variable {
+	bits := make([]boolean.Bit, 0, 8*len(msg))
+	for i := range msg {
+		for _, b := range cs.ToBinary(msg[i], 8) {
+			bits = append(bits, boolean.Variable(b))
+		}
+	}
+	return sum(cs, bits)
+}
+
+func sum(cs *frontend.ConstraintSystem, msg []boolean.Bit) []frontend.Variable {
+	// padding pad10*1: 1, zeros, and 1 at the end of the last block
+	padded := append(make([]boolean.Bit, 0, len(msg)+rate), msg...)
+	padded = append(padded, boolean.Constant(1))
+	for len(padded)%rate != rate-1 {
+		padded = append(padded, boolean.Constant(0))
+	}
+	padded = append(padded, boolean.Constant(1))
+
+	// the lane (x, y) is state[x+5*y], least significant bit first
+	var state [25]lane
+	for i := range state {
+		for j := range state[i] {
+			state[i][j] = boolean.Constant(0)
+		}
+	}
+
+	for start := 0; start < len(padded); start += rate {
+		for i := 0; i < rate; i++ {
+			state[i/64][i%64] = boolean.Xor(cs, state[i/64][i%64], padded[start+i])
+		}
+		state = permute(cs, state)
+	}
+
+	res := make([]frontend.Variable, Size)
+	for i := range res {
+		res[i] = state[i/64][i%64].Variable(cs)
+	}
+	return res
+}
+
+// permute returns the Keccak-f[1600] permutation of the state
+func permute(cs *frontend.ConstraintSystem, a [25]lane) [25]lane {
+	for round := 0; round < 24; round++ {
+		// theta
+		var c, d [5]lane
+		for x := 0; x < 5; x++ {
+			for i := 0; i < 64; i++ {
+				c[x][i] = boolean.Xor(cs, boolean.Xor(cs, boolean.Xor(cs, boolean.Xor(cs, a[x][i], a[x+5][i]), a[x+10][i]), a[x+15][i]), a[x+20][i])
+			}
+		}
+		for x := 0; x < 5; x++ {
+			rotated := c[(x+1)%5].rotl(1)
+			for i := 0; i < 64; i++ {
+				d[x][i] = boolean.Xor(cs, c[(x+4)%5][i], rotated[i])
+			}
+		}
+		for j := range a {
+			for i := 0; i < 64; i++ {
+				a[j][i] = boolean.Xor(cs, a[j][i], d[j%5][i])
+			}
+		}
+
+		// rho and pi
+		var b [25]lane
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = a[x+5*y].rotl(rotations[x][y])
+			}
+		}
+
+		// chi: a = b XOR (NOT b[x+1] AND b[x+2]), and NOT b1 AND b2 = b2 XOR (b1 AND b2)
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b0, b1, b2 := b[x+5*y], b[(x+1)%5+5*y], b[(x+2)%5+5*y]
+				for i := 0; i < 64; i++ {
+					a[x+5*y][i] = boolean.Xor(cs, boolean.Xor(cs, b0[i], b2[i]), boolean.And(cs, b1[i], b2[i]))
+				}
+			}
+		}
+
+		// iota
+		for i := 0; i < 64; i++ {
+			a[0][i] = boolean.Xor(cs, a[0][i], boolean.Constant(int(roundConstants[round]>>i)&1))
+		}
+	}
+	return a
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keccak
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/std/hash/internal/hashtest"
+	"golang.org/x/crypto/sha3"
+)
+
+var hash = hashtest.Hash{Sum: Sum, SumBytes: SumBytes, Size: Size, MSBFirst: false}
+
+func keccak256(msg []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	_, _ = h.Write(msg)
+	return h.Sum(nil)
+}
+
+func TestKeccak256(t *testing.T) {
+	msgs := [][]byte{[]byte("abc")}
+	if !testing.Short() {
+		// the empty message, and the rate boundary: the padding of a message of 135 bytes fits in
+		// its block, the padding of a message of 136 or 137 bytes is in a second block
+		msgs = append(msgs, []byte{}, make([]byte, 135), make([]byte, 136), make([]byte, 137))
+	}
+
+	for _, msg := range msgs {
+		hash.AssertBytes(t, msg, keccak256(msg))
+	}
+}
+
+// TestKeccak256R1CS solves the compiled circuit of a block, whose constants are propagated and
+// whose duplicate products are removed by the optimizations of Compile
+func TestKeccak256R1CS(t *testing.T) {
+	msg := []byte("abc")
+	hash.AssertR1CS(t, msg, keccak256(msg))
+}
+
+func TestKeccak256Bits(t *testing.T) {
+	msg := []byte("the quick brown fox")
+	hash.AssertBits(t, msg, keccak256(msg))
+}
+
+func BenchmarkKeccak256(b *testing.B) {
+	hash.Benchmark(b, 32)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"math/big"
+	"sync"
+
+	pedersen_bls381 "github.com/consensys/gnark/crypto/hash/pedersen/bls381"
+	pedersen_bn256 "github.com/consensys/gnark/crypto/hash/pedersen/bn256"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gurvy"
+)
+
+// pedersenPerCurve holds one Pedersen per curve, so that the segment tables are computed once
+// and shared by all the instances returned by NewPedersen
+var pedersenPerCurve map[gurvy.ID]Pedersen
+
+func init() {
+	pedersenPerCurve = make(map[gurvy.ID]Pedersen)
+	pedersenPerCurve[gurvy.BN256] = newPedersenBN256()
+	pedersenPerCurve[gurvy.BLS381] = newPedersenBLS381()
+}
+
+// -------------------------------------------------------------------------------------------------
+// constructors
+
+func newPedersenBN256() Pedersen {
+	return newPedersenOnCurve(gurvy.BN256, func(segment int) (res point) {
+		g := pedersen_bn256.Generator(segment)
+		g.X.ToBigIntRegular(&res.X)
+		g.Y.ToBigIntRegular(&res.Y)
+		return
+	})
+}
+
+func newPedersenBLS381() Pedersen {
+	return newPedersenOnCurve(gurvy.BLS381, func(segment int) (res point) {
+		g := pedersen_bls381.Generator(segment)
+		g.X.ToBigIntRegular(&res.X)
+		g.Y.ToBigIntRegular(&res.Y)
+		return
+	})
+}
+
+// newPedersenOnCurve returns the Pedersen hash on the twisted Edwards curve embedded in the curve id,
+// whose generator of a segment is given by generator (see Generator in crypto/hash/pedersen).
+// The table of a segment is computed the first time it is needed, and then reused
+func newPedersenOnCurve(id gurvy.ID, generator func(segment int) point) Pedersen {
+	curve, _ := twistededwards.NewEdCurve(id)
+	var lock sync.Mutex
+	var tables [][][4]point
+	return Pedersen{
+		curve: curve,
+		segmentTable: func(segment int) [][4]point {
+			lock.Lock()
+			defer lock.Unlock()
+			for len(tables) <= segment {
+				tables = append(tables, nil)
+			}
+			if tables[segment] != nil {
+				return tables[segment]
+			}
+			table := make([][4]point, nbChunksPerSegment)
+			p := generator(segment) // 16^j * G
+			for j := range table {
+				table[j][0].set(&p)
+				for k := 1; k < 4; k++ {
+					table[j][k].add(&table[j][k-1], &p, &curve)
+				}
+				for i := 0; i < 4; i++ {
+					p.add(&p, &p, &curve)
+				}
+			}
+			tables[segment] = table
+			return table
+		},
+	}
+}
+
+// set sets p to q and returns p
+func (p *point) set(q *point) *point {
+	p.X.Set(&q.X)
+	p.Y.Set(&q.Y)
+	return p
+}
+
+// add sets p to p1+p2 on curve and returns p:
+// x = (x1y2 + y1x2) / (1 + d*x1x2y1y2), y = (y1y2 - a*x1x2) / (1 - d*x1x2y1y2)
+func (p *point) add(p1, p2 *point, curve *twistededwards.EdCurve) *point {
+	m := &curve.Modulus
+	var x1x2, y1y2, dxy, num, den, x, y big.Int
+
+	x1x2.Mul(&p1.X, &p2.X).Mod(&x1x2, m)
+	y1y2.Mul(&p1.Y, &p2.Y).Mod(&y1y2, m)
+	dxy.Mul(&x1x2, &y1y2).Mul(&dxy, &curve.D).Mod(&dxy, m)
+
+	num.Mul(&p1.X, &p2.Y)
+	x.Mul(&p1.Y, &p2.X)
+	num.Add(&num, &x)
+	den.Add(big.NewInt(1), &dxy).ModInverse(&den, m)
+	x.Mul(&num, &den).Mod(&x, m)
+
+	num.Mul(&curve.A, &x1x2)
+	num.Sub(&y1y2, &num)
+	den.Sub(big.NewInt(1), &dxy).Mod(&den, m).ModInverse(&den, m)
+	y.Mul(&num, &den).Mod(&y, m)
+
+	p.X.Set(&x)
+	p.Y.Set(&y)
+	return p
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen implements the Pedersen hash in a gnark circuit, on the twisted Edwards curve
+// whose base field is the scalar field of the curve of the circuit.
+//
+// It computes the same points as crypto/hash/pedersen: the message bits are split into segments
+// of 62 chunks of 3 bits, each segment being hashed with its own generator.
+package pedersen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gurvy"
+)
+
+// nbChunksPerSegment is the number of 3 bits chunks hashed with the same generator
+// (same as NbChunksPerSegment in crypto/hash/pedersen)
+const nbChunksPerSegment = 62
+
+type point struct {
+	X, Y big.Int
+}
+
+// Pedersen contains the params of the Pedersen hash on the twisted Edwards curve embedded in a curve:
+// the twisted Edwards curve, and the multiples of the generators of the segments (computed once per curve,
+// the first time a segment is hashed).
+// It is built with NewPedersen and is safe to reuse for several hashes
+type Pedersen struct {
+	curve twistededwards.EdCurve
+
+	// segmentTable returns the multiples k*16^j*G of the generator G of a segment, for
+	// j < nbChunksPerSegment and k = 1, 2, 3, 4 (the windows of the fixed-base lookups).
+	// The returned table is shared and must not be modified
+	segmentTable func(segment int) [][4]point
+}
+
+// NewPedersen returns a Pedersen instance, than can be used in a gnark circuit
+// compiled on id. It returns an error if the hash isn't implemented for id
+// (only BN256 and BLS381 are supported)
+func NewPedersen(id gurvy.ID) (Pedersen, error) {
+	if h, ok := pedersenPerCurve[id]; ok {
+		return h, nil
+	}
+	return Pedersen{}, errors.New("unknown curve id")
+}
+
+// Hash returns the Pedersen hash of bits (in r1cs form), as a point of the twisted Edwards curve.
+// It is the same as HashPoint in crypto/hash/pedersen, for the message whose bits (least
+// significant first in each byte) are bits.
+//
+// The bits are constrained to be boolean. Each chunk of 3 bits costs a lookup in a window of 4
+// points, a conditional negation and a point addition.
+func (h Pedersen) Hash(cs *frontend.ConstraintSystem, bits ...frontend.Variable) twistededwards.Point {
+
+	if len(bits) == 0 {
+		return twistededwards.Point{X: cs.Constant(0), Y: cs.Constant(1)}
+	}
+
+	var res twistededwards.Point
+	first := true
+
+	for segment, start := 0, 0; start < len(bits); segment, start = segment+1, start+3*nbChunksPerSegment {
+		table := h.segmentTable(segment)
+
+		for j := 0; j < nbChunksPerSegment && start+3*j < len(bits); j++ {
+			c := start + 3*j
+			t := table[j]
+
+			// (1 + s0 + 2*s1) * 16^j * G, the missing bits of the last chunk being 0
+			var p twistededwards.Point
+			if c+1 < len(bits) {
+				p.X = cs.Lookup2(bits[c], bits[c+1], &t[0].X, &t[1].X, &t[2].X, &t[3].X)
+				p.Y = cs.Lookup2(bits[c], bits[c+1], &t[0].Y, &t[1].Y, &t[2].Y, &t[3].Y)
+			} else {
+				p.X = cs.Select(bits[c], &t[1].X, &t[0].X)
+				p.Y = cs.Select(bits[c], &t[1].Y, &t[0].Y)
+			}
+
+			// the opposite of (x, y) is (-x, y)
+			if c+2 < len(bits) {
+				cs.AssertIsBoolean(bits[c+2])
+				p.X = cs.Mul(p.X, cs.Sub(1, cs.Mul(bits[c+2], 2)))
+			}
+
+			if first {
+				res = p
+				first = false
+				continue
+			}
+			res.AddGeneric(cs, &res, &p, h.curve)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,106 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"testing"
+
+	pedersen_bls381 "github.com/consensys/gnark/crypto/hash/pedersen/bls381"
+	pedersen_bn256 "github.com/consensys/gnark/crypto/hash/pedersen/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+)
+
+type pedersenCircuit struct {
+	Bits []frontend.Variable
+	Hash twistededwards.Point `gnark:",public"`
+}
+
+func (circuit *pedersenCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	pedersen, err := NewPedersen(curveID)
+	if err != nil {
+		return err
+	}
+	h := pedersen.Hash(cs, circuit.Bits...)
+	cs.AssertIsEqual(h.X, circuit.Hash.X)
+	cs.AssertIsEqual(h.Y, circuit.Hash.Y)
+	return nil
+}
+
+// nativeHash returns the coordinates of the native Pedersen hash of msg
+func nativeHash(curveID gurvy.ID, msg []byte) (x, y interface{}) {
+	switch curveID {
+	case gurvy.BN256:
+		p := pedersen_bn256.HashPoint(msg)
+		return p.X, p.Y
+	case gurvy.BLS381:
+		p := pedersen_bls381.HashPoint(msg)
+		return p.X, p.Y
+	default:
+		panic("not implemented")
+	}
+}
+
+func parseWitness(witness frontend.Circuit) map[string]interface{} {
+	values, err := frontend.ParseWitness(witness)
+	if err != nil {
+		panic(err)
+	}
+	return values
+}
+
+func TestPedersen(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	msg := []byte("a message hashed on 2 segments.")
+
+	// the last chunk of a message of 2 bytes misses 2 bits, and 1 bit for a message of 31 bytes
+	for _, n := range []int{1, 2, len(msg)} {
+		circuit := pedersenCircuit{Bits: make([]frontend.Variable, 8*n)}
+
+		for _, curveID := range []gurvy.ID{gurvy.BN256, gurvy.BLS381} {
+			x, y := nativeHash(curveID, msg[:n])
+
+			// newWitness returns the witness of msg[:n], with the first bit set to b0
+			newWitness := func(b0 int) *pedersenCircuit {
+				witness := &pedersenCircuit{Bits: make([]frontend.Variable, 8*n)}
+				witness.Bits[0].Assign(b0)
+				for i := 1; i < 8*n; i++ {
+					witness.Bits[i].Assign(int(msg[i/8]>>(i%8)) & 1)
+				}
+				witness.Hash.X.Assign(x)
+				witness.Hash.Y.Assign(y)
+				return witness
+			}
+
+			assert.SolvingSucceeded(&circuit, newWitness(int(msg[0]&1)), curveID)
+
+			r1cs, err := frontend.Compile(curveID, &circuit)
+			assert.NoError(err)
+			assert.NoError(r1cs.IsSolved(parseWitness(newWitness(int(msg[0] & 1)))))
+
+			// other message
+			assert.SolvingFailed(&circuit, newWitness(int(msg[0]&1)^1), curveID)
+			assert.Error(r1cs.IsSolved(parseWitness(newWitness(int(msg[0]&1) ^ 1))))
+
+			// the bits must be boolean
+			assert.SolvingFailed(&circuit, newWitness(2), curveID)
+		}
+	}
+}
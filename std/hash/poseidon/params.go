@@ -0,0 +1,65 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"math/big"
+
+	poseidon_bn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	"github.com/consensys/gurvy"
+)
+
+var circomlibParams map[gurvy.ID]func(nbInputs int) (Params, error)
+
+func init() {
+	circomlibParams = make(map[gurvy.ID]func(nbInputs int) (Params, error))
+	circomlibParams[gurvy.BN256] = circomlibParamsBN256
+}
+
+// -------------------------------------------------------------------------------------------------
+// circomlib params
+
+func circomlibParamsBN256(nbInputs int) (Params, error) {
+	params, err := poseidon_bn256.CircomlibParams(nbInputs)
+	if err != nil {
+		return Params{}, err
+	}
+	return FromBN256(params), nil
+}
+
+// FromBN256 converts the params of the native Poseidon permutation on BN256
+func FromBN256(params *poseidon_bn256.Params) Params {
+	res := Params{
+		T:               params.T,
+		NbFullRounds:    params.NbFullRounds,
+		NbPartialRounds: params.NbPartialRounds,
+	}
+	for _, c := range params.RoundConstants {
+		var cpy big.Int
+		c.ToBigIntRegular(&cpy)
+		res.RoundConstants = append(res.RoundConstants, cpy)
+	}
+	res.MDS = make([][]big.Int, len(params.MDS))
+	for i, row := range params.MDS {
+		for _, m := range row {
+			var cpy big.Int
+			m.ToBigIntRegular(&cpy)
+			res.MDS[i] = append(res.MDS[i], cpy)
+		}
+	}
+	return res
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/crypto/hash/poseidon"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+// Params of a Poseidon permutation of width T (see Params in crypto/hash/poseidon)
+type Params struct {
+	T               int // width of the permutation
+	NbFullRounds    int // number of full rounds, half of them before the partial rounds
+	NbPartialRounds int // number of partial rounds
+
+	// RoundConstants are added to the state before each round: the constants of round r are
+	// RoundConstants[r*T:(r+1)*T]
+	RoundConstants []big.Int
+
+	// MDS is the T x T matrix of the linear layer: state[i] = Σ_j MDS[i][j] * state[j]
+	MDS [][]big.Int
+}
+
+// Poseidon contains the params of a Poseidon permutation with the x^5 S-box
+type Poseidon struct {
+	params Params
+}
+
+// NewPoseidon returns a Poseidon instance with the given params, than can be used in a gnark circuit
+func NewPoseidon(params Params) (Poseidon, error) {
+	mdsRows := make([]int, len(params.MDS))
+	for i := range params.MDS {
+		mdsRows[i] = len(params.MDS[i])
+	}
+	if err := poseidon.CheckSizes(params.T, params.NbFullRounds, params.NbPartialRounds, len(params.RoundConstants), mdsRows); err != nil {
+		return Poseidon{}, err
+	}
+	return Poseidon{params: params}, nil
+}
+
+// NewCircomlibPoseidon returns the Poseidon instance of circomlib hashing nbInputs elements
+// (the width is nbInputs+1)
+func NewCircomlibPoseidon(id gurvy.ID, nbInputs int) (Poseidon, error) {
+	constructor, ok := circomlibParams[id]
+	if !ok {
+		return Poseidon{}, errors.New("unknown curve id")
+	}
+	params, err := constructor(nbInputs)
+	if err != nil {
+		return Poseidon{}, err
+	}
+	return NewPoseidon(params)
+}
+
+// Permutation returns the Poseidon permutation of state (in r1cs form), whose size must be
+// the width of the permutation.
+//
+// Each S-box costs 3 constraints, the round constants and the linear layers are free.
+func (h Poseidon) Permutation(cs *frontend.ConstraintSystem, state []frontend.Variable) []frontend.Variable {
+	t := h.params.T
+	if len(state) != t {
+		panic("the size of the state must be the width of the permutation")
+	}
+
+	res := make([]frontend.Variable, t)
+	copy(res, state)
+
+	halfFullRounds := h.params.NbFullRounds / 2
+
+	for r := 0; r < h.params.NbFullRounds+h.params.NbPartialRounds; r++ {
+		for i := range res {
+			res[i] = cs.Add(res[i], h.params.RoundConstants[r*t+i])
+		}
+
+		if r < halfFullRounds || r >= halfFullRounds+h.params.NbPartialRounds {
+			for i := range res {
+				res[i] = sbox(cs, res[i])
+			}
+		} else {
+			res[0] = sbox(cs, res[0])
+		}
+
+		mixed := make([]frontend.Variable, t)
+		for i := range mixed {
+			mixed[i] = cs.Mul(res[0], h.params.MDS[i][0])
+			for j := 1; j < t; j++ {
+				mixed[i] = cs.Add(mixed[i], cs.Mul(res[j], h.params.MDS[i][j]))
+			}
+		}
+		res = mixed
+	}
+
+	return res
+}
+
+// Hash returns the circomlib Poseidon hash of data (in r1cs form): the first element of the
+// permutation of [0, data...]. The number of elements of data must be the width minus 1.
+func (h Poseidon) Hash(cs *frontend.ConstraintSystem, data ...frontend.Variable) frontend.Variable {
+	if len(data) != h.params.T-1 {
+		panic("the number of inputs must be the width of the permutation minus 1")
+	}
+
+	state := make([]frontend.Variable, h.params.T)
+	state[0] = cs.Constant(0)
+	copy(state[1:], data)
+
+	return h.Permutation(cs, state)[0]
+}
+
+// sbox returns x^5
+func sbox(cs *frontend.ConstraintSystem, x frontend.Variable) frontend.Variable {
+	x2 := cs.Mul(x, x)
+	x4 := cs.Mul(x2, x2)
+	return cs.Mul(x4, x)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"testing"
+
+	poseidon_bn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+type poseidonCircuit struct {
+	Data []frontend.Variable
+	Hash frontend.Variable `gnark:",public"`
+}
+
+func (circuit *poseidonCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	poseidon, err := NewCircomlibPoseidon(curveID, len(circuit.Data))
+	if err != nil {
+		return err
+	}
+	cs.AssertIsEqual(poseidon.Hash(cs, circuit.Data...), circuit.Hash)
+	return nil
+}
+
+func TestPoseidon(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	for _, n := range []int{1, 2, 5} {
+		circuit := poseidonCircuit{Data: make([]frontend.Variable, n)}
+
+		data := make([]fr.Element, n)
+		for i := range data {
+			data[i].SetRandom()
+		}
+		h, err := poseidon_bn256.Hash(data...)
+		assert.NoError(err)
+
+		{
+			var witness poseidonCircuit
+			witness.Data = make([]frontend.Variable, n)
+			for i := range data {
+				witness.Data[i].Assign(data[i])
+			}
+			witness.Hash.Assign(h)
+			assert.SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+		}
+
+		{
+			var witness poseidonCircuit
+			witness.Data = make([]frontend.Variable, n)
+			for i := range data {
+				witness.Data[i].Assign(data[i])
+			}
+			witness.Hash.Assign(data[0])
+			assert.SolvingFailed(&circuit, &witness, gurvy.BN256)
+		}
+
+		// 3 constraints per S-box (the S-box of the first element of the initial state, a constant,
+		// is optimized out), and the final assertion
+		r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+		assert.NoError(err)
+		params, err := poseidon_bn256.CircomlibParams(n)
+		assert.NoError(err)
+		assert.EqualValues(3*(params.NbFullRounds*params.T+params.NbPartialRounds-1)+1, r1cs.GetNbConstraints())
+	}
+
+	_, err := NewCircomlibPoseidon(gurvy.BLS381, 2)
+	assert.Error(err)
+	_, err = NewCircomlibPoseidon(gurvy.BN256, 17)
+	assert.Error(err)
+}
+
+// permutationCircuit checks the permutation with custom params
+type permutationCircuit struct {
+	params Params
+	State  [4]frontend.Variable
+	Result [4]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *permutationCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	poseidon, err := NewPoseidon(circuit.params)
+	if err != nil {
+		return err
+	}
+	res := poseidon.Permutation(cs, circuit.State[:])
+	for i := range res {
+		cs.AssertIsEqual(res[i], circuit.Result[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	params := poseidon_bn256.GenerateParams(4, 4, 10)
+	circuit := permutationCircuit{params: FromBN256(params)}
+
+	state := make([]fr.Element, 4)
+	for i := range state {
+		state[i].SetUint64(uint64(i))
+	}
+	var witness permutationCircuit
+	for i := range state {
+		witness.State[i].Assign(state[i])
+	}
+	params.Permutation(state)
+	for i := range state {
+		witness.Result[i].Assign(state[i])
+	}
+
+	assert.SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+
+	_, err := NewPoseidon(Params{T: 4, NbFullRounds: 4, NbPartialRounds: 10})
+	assert.Error(err)
+}
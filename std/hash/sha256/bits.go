@@ -0,0 +1,148 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha256
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/internal/boolean"
+)
+
+// ch returns (e AND f) XOR (NOT e AND g) = g + e(f - g) (1 constraint)
+func ch(cs *frontend.ConstraintSystem, e, f, g boolean.Bit) boolean.Bit {
+	switch {
+	case e.IsConstant() && e.Value() == 1:
+		return f
+	case e.IsConstant():
+		return g
+	case f.IsConstant() && g.IsConstant():
+		switch {
+		case f.Value() == g.Value():
+			return f
+		case f.Value() == 1:
+			return e
+		default:
+			return boolean.Not(cs, e)
+		}
+	default:
+		fv, gv := f.Variable(cs), g.Variable(cs)
+		return boolean.Variable(cs.Add(gv, cs.Mul(e.Variable(cs), cs.Sub(fv, gv))))
+	}
+}
+
+// maj returns the majority of a, b and c: ab + c(a + b - 2ab) (2 constraints)
+func maj(cs *frontend.ConstraintSystem, a, b, c boolean.Bit) boolean.Bit {
+	// maj is symmetric: if a bit is constant, it is moved to a
+	for i := 0; i < 2 && !a.IsConstant(); i++ {
+		a, b, c = b, c, a
+	}
+
+	switch {
+	case a.IsConstant() && a.Value() == 0:
+		return boolean.And(cs, b, c)
+	case a.IsConstant():
+		return boolean.Or(cs, b, c)
+	default:
+		av, bv := a.Variable(cs), b.Variable(cs)
+		ab := cs.Mul(av, bv)
+		aXorB := cs.Sub(cs.Add(av, bv), cs.Mul(2, ab))
+		return boolean.Variable(cs.Add(ab, cs.Mul(c.Variable(cs), aXorB)))
+	}
+}
+
+// word is a 32 bits word, least significant bit first
+type word [32]boolean.Bit
+
+func constantWord(value uint32) word {
+	var res word
+	for i := range res {
+		res[i] = boolean.Constant(int(value>>i) & 1)
+	}
+	return res
+}
+
+// rotr returns the right rotation of w by n bits
+func (w word) rotr(n int) word {
+	var res word
+	for i := range res {
+		res[i] = w[(i+n)%32]
+	}
+	return res
+}
+
+// shr returns the right shift of w by n bits
+func (w word) shr(n int) word {
+	var res word
+	for i := range res {
+		if i+n < 32 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = boolean.Constant(0)
+		}
+	}
+	return res
+}
+
+// xor3 returns a XOR b XOR c (up to 64 constraints)
+func xor3(cs *frontend.ConstraintSystem, a, b, c word) word {
+	var res word
+	for i := range res {
+		res[i] = boolean.Xor(cs, boolean.Xor(cs, a[i], b[i]), c[i])
+	}
+	return res
+}
+
+// add returns the sum of the words modulo 2^32: the sum is computed as a linear expression, and
+// decomposed in binary (nbBits + 1 constraints, where nbBits is the size of the sum)
+func add(cs *frontend.ConstraintSystem, words ...word) word {
+	var constant, bound big.Int
+	sum := cs.Constant(0)
+	isConstant := true
+
+	for _, w := range words {
+		for i, b := range w {
+			var coeff big.Int
+			coeff.Lsh(big.NewInt(1), uint(i))
+			if b.IsConstant() {
+				constant.Add(&constant, coeff.Mul(&coeff, big.NewInt(int64(b.Value()))))
+				continue
+			}
+			isConstant = false
+			sum = cs.Add(sum, cs.Mul(b.Variable(cs), coeff))
+			bound.Add(&bound, &coeff)
+		}
+	}
+
+	if isConstant {
+		return constantWord(uint32(constant.Uint64()))
+	}
+
+	sum = cs.Add(sum, constant)
+	bound.Add(&bound, &constant)
+
+	bits := cs.ToBinary(sum, bound.BitLen())
+	var res word
+	for i := range res {
+		if i < len(bits) {
+			res[i] = boolean.Variable(bits[i])
+		} else {
+			res[i] = boolean.Constant(0)
+		}
+	}
+	return res
+}
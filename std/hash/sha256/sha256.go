@@ -0,0 +1,146 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sha256 implements the SHA-256 hash (FIPS 180-4) in a gnark circuit.
+//
+// The messages and the digests are sequences of bits, in the order of FIPS 180-4: the first bit is
+// the most significant bit of the first byte. The length of the message is known at compile time, so
+// that the padding is constant.
+//
+// A block of 512 bits costs about 26000 constraints (see the benchmarks).
+package sha256
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/internal/boolean"
+)
+
+// Size is the size of a SHA-256 digest, in bits
+const Size = 256
+
+// blockSize is the size of a SHA-256 block, in bits
+const blockSize = 512
+
+var iv = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var k = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// Sum returns the SHA-256 digest of the message whose bits are msg (in r1cs form), as Size bits.
+//
+// The bits of msg are constrained to be boolean.
+func Sum(cs *frontend.ConstraintSystem, msg ...frontend.Variable) []frontend.Variable {
+	bits := make([]boolean.Bit, len(msg))
+	for i := range msg {
+		cs.AssertIsBoolean(msg[i])
+		bits[i] = boolean.Variable(msg[i])
+	}
+	return sum(cs, bits)
+}
+
+// SumBytes returns the SHA-256 digest of the message whose bytes are msg (in r1cs form), as
+// Size bits.
+//
+// The elements of msg are constrained to be bytes (8 bits).
+func SumBytes(cs *frontend.ConstraintSystem, msg ...frontend.Variable) []frontend.Variable {
+	bits := make([]boolean.Bit, 0, 8*len(msg))
+	for i := range msg {
+		b := cs.ToBinary(msg[i], 8)
+		for j := 7; j >= 0; j-- {
+			bits = append(bits, boolean.Variable(b[j]))
+		}
+	}
+	return sum(cs, bits)
+}
+
+func sum(cs *frontend.ConstraintSystem, msg []boolean.Bit) []frontend.Variable {
+	// padding: 1, then zeros up to 448 bits modulo 512, then the size of the message on 64 bits
+	l := uint64(len(msg))
+	padded := append(make([]boolean.Bit, 0, len(msg)+blockSize+64), msg...)
+	padded = append(padded, boolean.Constant(1))
+	for len(padded)%blockSize != blockSize-64 {
+		padded = append(padded, boolean.Constant(0))
+	}
+	for i := 63; i >= 0; i-- {
+		padded = append(padded, boolean.Constant(int(l>>i)&1))
+	}
+
+	var h [8]word
+	for i := range h {
+		h[i] = constantWord(iv[i])
+	}
+	for start := 0; start < len(padded); start += blockSize {
+		h = compress(cs, h, padded[start:start+blockSize])
+	}
+
+	res := make([]frontend.Variable, 0, Size)
+	for i := range h {
+		for j := 31; j >= 0; j-- {
+			res = append(res, h[i][j].Variable(cs))
+		}
+	}
+	return res
+}
+
+// compress returns the hash value h updated with the block
+func compress(cs *frontend.ConstraintSystem, h [8]word, block []boolean.Bit) [8]word {
+	// message schedule
+	var w [64]word
+	for t := 0; t < 16; t++ {
+		for i := 0; i < 32; i++ {
+			w[t][31-i] = block[32*t+i]
+		}
+	}
+	for t := 16; t < 64; t++ {
+		s0 := xor3(cs, w[t-15].rotr(7), w[t-15].rotr(18), w[t-15].shr(3))
+		s1 := xor3(cs, w[t-2].rotr(17), w[t-2].rotr(19), w[t-2].shr(10))
+		w[t] = add(cs, s1, w[t-7], s0, w[t-16])
+	}
+
+	a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	for t := 0; t < 64; t++ {
+		s1 := xor3(cs, e.rotr(6), e.rotr(11), e.rotr(25))
+		var chEFG word
+		for i := range chEFG {
+			chEFG[i] = ch(cs, e[i], f[i], g[i])
+		}
+		s0 := xor3(cs, a.rotr(2), a.rotr(13), a.rotr(22))
+		var majABC word
+		for i := range majABC {
+			majABC[i] = maj(cs, a[i], b[i], c[i])
+		}
+
+		// T1 = h + Σ1(e) + Ch(e, f, g) + K[t] + W[t] and T2 = Σ0(a) + Maj(a, b, c) are not reduced:
+		// e = d + T1 and a = T1 + T2 are computed with a single binary decomposition each
+		kt := constantWord(k[t])
+		hh, g, f, e, d, c, b, a = g, f, e, add(cs, d, hh, s1, chEFG, kt, w[t]), c, b, a, add(cs, hh, s1, chEFG, kt, w[t], s0, majABC)
+	}
+
+	return [8]word{
+		add(cs, h[0], a), add(cs, h[1], b), add(cs, h[2], c), add(cs, h[3], d),
+		add(cs, h[4], e), add(cs, h[5], f), add(cs, h[6], g), add(cs, h[7], hh),
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha256
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark/std/hash/internal/hashtest"
+)
+
+var hash = hashtest.Hash{Sum: Sum, SumBytes: SumBytes, Size: Size, MSBFirst: true}
+
+func TestSHA256(t *testing.T) {
+	// examples of FIPS 180-4 (one block, two blocks) and the empty message
+	vectors := []struct {
+		msg, digest string
+	}{
+		{"abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", "248d6a61d20638b8e5c026930c3e6039a33ce45964ff2167f6ecedd419db06c1"},
+		{"", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+
+	for _, vector := range vectors {
+		digest, err := hex.DecodeString(vector.digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash.AssertBytes(t, []byte(vector.msg), digest)
+	}
+}
+
+// TestSHA256R1CS solves the compiled circuit of a block, whose constants are propagated and whose
+// duplicate products are removed by the optimizations of Compile
+func TestSHA256R1CS(t *testing.T) {
+	msg := []byte("abc")
+	digest := sha256.Sum256(msg)
+	hash.AssertR1CS(t, msg, digest[:])
+}
+
+func TestSHA256Bits(t *testing.T) {
+	// the padding of a message of 55 bytes fits in its block, not the padding of a message of 56 bytes
+	for _, n := range []int{55, 56} {
+		msg := make([]byte, n)
+		for i := range msg {
+			msg[i] = byte(i * 37)
+		}
+		digest := sha256.Sum256(msg)
+		hash.AssertBits(t, msg, digest[:])
+	}
+}
+
+func BenchmarkSHA256OneBlock(b *testing.B) {
+	hash.Benchmark(b, 55)
+}
+
+func BenchmarkSHA256TwoBlocks(b *testing.B) {
+	hash.Benchmark(b, 119)
+}
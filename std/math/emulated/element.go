@@ -0,0 +1,434 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package emulated implements the arithmetic modulo an arbitrary prime (a non-native field) in a
+// gnark circuit.
+//
+// An element is an integer given by its limbs, which are native variables of Params.NbBits bits.
+// The reduction is lazy: the additions and subtractions only add the limbs, which may then
+// overflow. The products are reduced: the prover gives the quotient and the remainder of the
+// euclidean division by the modulus as hints (see frontend.ConstraintSystem.NewHint), and the
+// circuit checks the division limb by limb, with range checked carries.
+//
+// Field binds the operations to a constraint system and a modulus, to write gadgets over an
+// emulated field (e.g. BN256Fp in a BN256 circuit, or an RSA modulus).
+package emulated
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+)
+
+// Element is an integer modulo Params.Modulus, given by its limbs (least significant limb first).
+//
+// The limbs of the results of the reductions are smaller than 2^Params.NbBits, but the results
+// may be greater than the modulus (see AssertIsReduced).
+type Element struct {
+	Limbs []frontend.Variable
+
+	// the limbs are smaller than 2^(Params.NbBits+overflow): the results of the additions and the
+	// subtractions are not reduced
+	overflow int
+}
+
+// Assign assigns the limbs of e to the value of v (reduced modulo params.Modulus)
+func (e *Element) Assign(value interface{}, params Params) {
+	v := backend.FromInterface(value)
+	v.Mod(&v, &params.Modulus)
+
+	if e.Limbs == nil {
+		e.Limbs = make([]frontend.Variable, params.NbLimbs)
+	}
+	limbs := params.decompose(&v, params.NbLimbs)
+	for i := range limbs {
+		e.Limbs[i].Assign(limbs[i])
+	}
+}
+
+// RangeCheck asserts that the limbs of e are smaller than 2^params.NbBits.
+//
+// The operations assume it of their operands: it must be called on the elements of the witness.
+func (e *Element) RangeCheck(cs *frontend.ConstraintSystem, params Params) {
+	params.rangeCheck(cs, e.Limbs, params.NbLimbs*params.NbBits)
+}
+
+// Reduce sets e to a, with limbs smaller than 2^params.NbBits
+func (e *Element) Reduce(cs *frontend.ConstraintSystem, a *Element, params Params) *Element {
+	if a.overflow == 0 {
+		*e = *a
+		return e
+	}
+	*e = params.reduce(cs, a.Limbs, params.NbBits+a.overflow)
+	return e
+}
+
+// Add sets e to a+b mod p
+func (e *Element) Add(cs *frontend.ConstraintSystem, a, b *Element, params Params) *Element {
+	a, b = params.fit(cs, a, b, max(a.overflow, b.overflow)+1)
+
+	x := make([]frontend.Variable, params.NbLimbs)
+	for i := range x {
+		x[i] = cs.Add(a.Limbs[i], b.Limbs[i])
+	}
+	*e = Element{Limbs: x, overflow: max(a.overflow, b.overflow) + 1}
+	return e
+}
+
+// Sub sets e to a-b mod p
+func (e *Element) Sub(cs *frontend.ConstraintSystem, a, b *Element, params Params) *Element {
+	a, b = params.fit(cs, a, b, max(a.overflow, b.overflow+1)+1)
+
+	// the padding makes the limbs nonnegative
+	pad := params.padding(params.NbBits + b.overflow)
+	x := make([]frontend.Variable, params.NbLimbs)
+	for i := range x {
+		x[i] = cs.Add(cs.Sub(a.Limbs[i], b.Limbs[i]), pad[i])
+	}
+	*e = Element{Limbs: x, overflow: max(a.overflow, b.overflow+1) + 1}
+	return e
+}
+
+// Neg sets e to -a mod p
+func (e *Element) Neg(cs *frontend.ConstraintSystem, a *Element, params Params) *Element {
+	a, _ = params.fit(cs, a, a, a.overflow+1)
+
+	pad := params.padding(params.NbBits + a.overflow)
+	x := make([]frontend.Variable, params.NbLimbs)
+	for i := range x {
+		x[i] = cs.Sub(pad[i], a.Limbs[i])
+	}
+	*e = Element{Limbs: x, overflow: a.overflow + 1}
+	return e
+}
+
+// Mul sets e to a*b mod p
+func (e *Element) Mul(cs *frontend.ConstraintSystem, a, b *Element, params Params) *Element {
+	x := params.mul(cs, a.Limbs, b.Limbs)
+	*e = params.reduce(cs, x, params.productBits(a.overflow, b.overflow))
+	return e
+}
+
+// MulConstant sets e to a*c mod p
+func (e *Element) MulConstant(cs *frontend.ConstraintSystem, a *Element, c *big.Int, params Params) *Element {
+	var reduced big.Int
+	reduced.Mod(c, &params.Modulus)
+	limbs := params.decompose(&reduced, params.NbLimbs)
+
+	x := zeros(cs, 2*params.NbLimbs-1)
+	for i := range a.Limbs {
+		for j := range limbs {
+			x[i+j] = cs.Add(x[i+j], cs.Mul(a.Limbs[i], limbs[j]))
+		}
+	}
+	*e = params.reduce(cs, x, params.productBits(a.overflow, 0))
+	return e
+}
+
+// Div sets e to a/b mod p.
+//
+// b must be invertible modulo p: the circuit is not satisfied otherwise, even if a is 0 mod p
+// (a*(1/b) is computed, and b is multiplied by its inverse).
+func (e *Element) Div(cs *frontend.ConstraintSystem, a, b *Element, params Params) *Element {
+	var inv Element
+	inv.Inverse(cs, b, params)
+	return e.Mul(cs, a, &inv, params)
+}
+
+// Inverse sets e to 1/a mod p. a must be invertible modulo p: the circuit is not satisfied otherwise
+func (e *Element) Inverse(cs *frontend.ConstraintSystem, a *Element, params Params) *Element {
+	one := params.Constant(cs, big.NewInt(1))
+	return e.divide(cs, &one, a, params)
+}
+
+// divide sets e to a/b mod p, given as a hint and constrained by e*b = a mod p: if a and b are
+// both 0 mod p, any e satisfies the constraint, so a must not be 0 mod p
+func (e *Element) divide(cs *frontend.ConstraintSystem, a, b *Element, params Params) *Element {
+	operands := make([]interface{}, 0, 1+len(a.Limbs)+len(b.Limbs))
+	operands = append(operands, len(a.Limbs))
+	operands = append(operands, toInterfaces(a.Limbs)...)
+	operands = append(operands, toInterfaces(b.Limbs)...)
+	res := params.hintLimbs(cs, divisionLimb, params.NbLimbs, operands)
+	params.rangeCheck(cs, res, params.NbLimbs*params.NbBits)
+
+	// res*b - a = 0 mod p
+	pad := params.padding(params.NbBits + a.overflow)
+	x := params.mul(cs, res, b.Limbs)
+	for i := range a.Limbs {
+		x[i] = cs.Add(cs.Sub(x[i], a.Limbs[i]), pad[i])
+	}
+	nbBits := max(params.productBits(0, b.overflow), params.NbBits+a.overflow+1) + 1
+	params.assertMod(cs, x, nbBits, nil)
+
+	*e = Element{Limbs: res}
+	return e
+}
+
+// Select sets e to a if b is true, to c otherwise (b must be boolean)
+func (e *Element) Select(cs *frontend.ConstraintSystem, b frontend.Variable, a, c *Element, params Params) *Element {
+	limbs := make([]frontend.Variable, params.NbLimbs)
+	for i := range limbs {
+		limbs[i] = cs.Select(b, a.Limbs[i], c.Limbs[i])
+	}
+	*e = Element{Limbs: limbs, overflow: max(a.overflow, c.overflow)}
+	return e
+}
+
+// Lookup2 sets e to i0, i1, i2 or i3 depending on the bits b0 and b1 (see
+// frontend.ConstraintSystem.Lookup2)
+func (e *Element) Lookup2(cs *frontend.ConstraintSystem, b0, b1 frontend.Variable, i0, i1, i2, i3 *Element, params Params) *Element {
+	limbs := make([]frontend.Variable, params.NbLimbs)
+	for i := range limbs {
+		limbs[i] = cs.Lookup2(b0, b1, i0.Limbs[i], i1.Limbs[i], i2.Limbs[i], i3.Limbs[i])
+	}
+	*e = Element{Limbs: limbs, overflow: max(i0.overflow, i1.overflow, i2.overflow, i3.overflow)}
+	return e
+}
+
+// ToBinary returns the bits of e (least significant bit first), which is reduced first if its
+// limbs overflow, but not modulo p
+func (e *Element) ToBinary(cs *frontend.ConstraintSystem, params Params) []frontend.Variable {
+	var reduced Element
+	reduced.Reduce(cs, e, params)
+
+	res := make([]frontend.Variable, 0, params.NbLimbs*params.NbBits)
+	for i := range reduced.Limbs {
+		res = append(res, cs.ToBinary(reduced.Limbs[i], params.NbBits)...)
+	}
+	return res
+}
+
+// AssertIsEqual asserts that a = b mod p
+func AssertIsEqual(cs *frontend.ConstraintSystem, a, b *Element, params Params) {
+	pad := params.padding(params.NbBits + b.overflow)
+	x := make([]frontend.Variable, params.NbLimbs)
+	for i := range x {
+		x[i] = cs.Add(cs.Sub(a.Limbs[i], b.Limbs[i]), pad[i])
+	}
+	params.assertMod(cs, x, max(params.NbBits+a.overflow, params.NbBits+b.overflow+1)+1, nil)
+}
+
+// AssertIsReduced asserts that a (as an integer) is smaller than p. The limbs of a must not
+// overflow (see Reduce).
+func AssertIsReduced(cs *frontend.ConstraintSystem, a *Element, params Params) {
+	if a.overflow != 0 {
+		panic("emulated: the limbs of the element overflow")
+	}
+
+	var pMinusOne big.Int
+	pMinusOne.Sub(&params.Modulus, big.NewInt(1))
+	limbs := params.decompose(&pMinusOne, params.NbLimbs)
+
+	// d = p-1-a is nonnegative: the hint computes (p-1-a) mod p from nonnegative limbs
+	pad := params.padding(params.NbBits)
+	x := make([]frontend.Variable, params.NbLimbs)
+	for i := range x {
+		x[i] = cs.Add(cs.Sub(limbs[i], a.Limbs[i]), pad[i])
+	}
+	d := params.hintLimbs(cs, remainderLimb, params.NbLimbs, toInterfaces(x))
+	params.rangeCheck(cs, d, params.NbLimbs*params.NbBits)
+
+	// a + d = p-1
+	for i := range x {
+		x[i] = cs.Sub(cs.Add(a.Limbs[i], d[i]), limbs[i])
+	}
+	params.assertIsZero(cs, x, params.NbBits+1)
+}
+
+// -------------------------------------------------------------------------------------------------
+// reduction
+
+// fit returns a and b, reduced if the limbs of the result of an operation on them would overflow
+// by more than maxOverflow bits
+func (params Params) fit(cs *frontend.ConstraintSystem, a, b *Element, overflow int) (*Element, *Element) {
+	if overflow <= params.maxOverflow() {
+		return a, b
+	}
+	var ra, rb Element
+	return ra.Reduce(cs, a, params), rb.Reduce(cs, b, params)
+}
+
+// productBits returns the size of the limbs of the product of two elements, before the reduction
+func (params Params) productBits(overflowA, overflowB int) int {
+	return 2*params.NbBits + overflowA + overflowB + bits.Len(uint(params.NbLimbs))
+}
+
+// mul returns the limbs of the product of the integers given by the limbs a and b
+func (params Params) mul(cs *frontend.ConstraintSystem, a, b []frontend.Variable) []frontend.Variable {
+	res := zeros(cs, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			res[i+j] = cs.Add(res[i+j], cs.Mul(a[i], b[j]))
+		}
+	}
+	return res
+}
+
+// reduce returns x mod p, x being the integer Σ x[i] * 2^(NbBits*i) whose limbs x[i] are
+// nonnegative, smaller than 2^nbBits
+func (params Params) reduce(cs *frontend.ConstraintSystem, x []frontend.Variable, nbBits int) Element {
+	r := params.hintLimbs(cs, remainderLimb, params.NbLimbs, toInterfaces(x))
+	params.rangeCheck(cs, r, params.NbLimbs*params.NbBits)
+	params.assertMod(cs, x, nbBits, r)
+	return Element{Limbs: r}
+}
+
+// assertMod asserts that x = r mod p, x being the integer Σ x[i] * 2^(NbBits*i) whose limbs x[i]
+// are nonnegative, smaller than 2^nbBits, and r being the limbs of an element (nil for 0)
+func (params Params) assertMod(cs *frontend.ConstraintSystem, x []frontend.Variable, nbBits int, r []frontend.Variable) {
+	w := params.NbBits
+
+	// x = q*p + r, with q < x / 2^(p.BitLen()-1)
+	nbQuotientBits := nbBits + w*(len(x)-1) - params.Modulus.BitLen() + 1
+	if nbQuotientBits < 1 {
+		nbQuotientBits = 1
+	}
+	nbQuotientLimbs := (nbQuotientBits + w - 1) / w
+	q := params.hintLimbs(cs, quotientLimb, nbQuotientLimbs, toInterfaces(x))
+	params.rangeCheck(cs, q, nbQuotientBits)
+
+	// the limbs of x - q*p - r, which may be negative
+	p := params.decompose(&params.Modulus, params.NbLimbs)
+	diff := zeros(cs, max(len(x), nbQuotientLimbs+params.NbLimbs-1, len(r)))
+	for i := range x {
+		diff[i] = cs.Add(diff[i], x[i])
+	}
+	for i := range q {
+		for j := range p {
+			diff[i+j] = cs.Sub(diff[i+j], cs.Mul(q[i], p[j]))
+		}
+	}
+	for i := range r {
+		diff[i] = cs.Sub(diff[i], r[i])
+	}
+
+	nbProductBits := w + min(nbQuotientBits, w) + bits.Len(uint(min(nbQuotientLimbs, params.NbLimbs)))
+	params.assertIsZero(cs, diff, max(nbBits, nbProductBits, w)+1)
+}
+
+// assertIsZero asserts that Σ x[i] * 2^(NbBits*i) = 0, the limbs x[i] being (possibly negative)
+// integers whose absolute value is smaller than 2^nbBits.
+//
+// The carries c[i] = (x[i] + c[i-1]) / 2^NbBits are given by hints, shifted by an offset so that
+// they are nonnegative, and range checked: the divisions are exact, and the last limb (with the
+// carry) is 0. The limbs are first grouped as larger limbs, as long as they fit in maxNbBits, so
+// that fewer carries are checked.
+func (params Params) assertIsZero(cs *frontend.ConstraintSystem, x []frontend.Variable, nbBits int) {
+	if nbBits > maxNbBits {
+		panic("emulated: the limbs are too large for the native field")
+	}
+	w := params.NbBits
+
+	// |Σ_{j<k} x[j] * 2^(w*j)| < 2^(nbBits + (k-1)*w + 1)
+	if k := min(1+(maxNbBits-nbBits-1)/w, len(x)); k > 1 {
+		var base big.Int
+		base.Lsh(big.NewInt(1), uint(w))
+		grouped := make([]frontend.Variable, 0, (len(x)+k-1)/k)
+		for i := 0; i < len(x); i += k {
+			var coeff big.Int
+			coeff.SetUint64(1)
+			limb := cs.Constant(0)
+			for j := i; j < len(x) && j < i+k; j++ {
+				limb = cs.Add(limb, cs.Mul(x[j], coeff))
+				coeff.Mul(&coeff, &base)
+			}
+			grouped = append(grouped, limb)
+		}
+		x = grouped
+		nbBits += (k-1)*w + 1
+		w *= k
+	}
+
+	// |x[i] + c[i-1]| < 2^(nbBits+1), and |c[i]| < 2^(nbBits+1-w)
+	var offset, carryOffset, base big.Int
+	offset.Lsh(big.NewInt(1), uint(nbBits+1))
+	carryOffset.Lsh(big.NewInt(1), uint(nbBits+1-w))
+	base.Lsh(big.NewInt(1), uint(w))
+
+	carry := cs.Constant(0)
+	for i := 0; i < len(x)-1; i++ {
+		shifted := cs.Add(x[i], carry, offset)
+		c := cs.NewHint(shiftRight, shifted, w)
+		cs.ToBinary(c, nbBits+2-w)
+		cs.AssertIsEqual(shifted, cs.Mul(c, base))
+		carry = cs.Sub(c, carryOffset)
+	}
+	cs.AssertIsEqual(cs.Add(x[len(x)-1], carry), 0)
+}
+
+// rangeCheck asserts that the integer given by limbs has at most nbBits bits
+func (params Params) rangeCheck(cs *frontend.ConstraintSystem, limbs []frontend.Variable, nbBits int) {
+	for i := range limbs {
+		n := min(nbBits-i*params.NbBits, params.NbBits)
+		if n <= 0 {
+			cs.AssertIsEqual(limbs[i], 0)
+			continue
+		}
+		cs.ToBinary(limbs[i], n)
+	}
+}
+
+// hintLimbs returns nbLimbs variables, the limbs computed by f (see hints.go) from operands
+func (params Params) hintLimbs(cs *frontend.ConstraintSystem, f frontend.HintFunction, nbLimbs int, operands []interface{}) []frontend.Variable {
+	modulus := params.decompose(&params.Modulus, params.NbLimbs)
+	inputs := make([]interface{}, 0, 3+len(modulus)+len(operands))
+	inputs = append(inputs, params.NbBits, 0, len(modulus))
+	for i := range modulus {
+		inputs = append(inputs, modulus[i])
+	}
+	inputs = append(inputs, operands...)
+
+	res := make([]frontend.Variable, nbLimbs)
+	for i := range res {
+		inputs[1] = i
+		res[i] = cs.NewHint(f, inputs...)
+	}
+	return res
+}
+
+func zeros(cs *frontend.ConstraintSystem, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = cs.Constant(0)
+	}
+	return res
+}
+
+func toInterfaces(v []frontend.Variable) []interface{} {
+	res := make([]interface{}, len(v))
+	for i := range v {
+		res[i] = v[i]
+	}
+	return res
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a int, others ...int) int {
+	for _, b := range others {
+		if b > a {
+			a = b
+		}
+	}
+	return a
+}
@@ -0,0 +1,286 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+)
+
+// the base field of secp256k1
+var secp256k1Fp, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+
+type arithmeticCircuit struct {
+	A, B                      Element
+	Sum, Diff, Neg, Prod, Quo Element `gnark:",public"`
+	ProdConstant, InverseA    Element `gnark:",public"`
+	params                    Params
+	constant                  *big.Int
+}
+
+func (circuit *arithmeticCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	params := circuit.params
+	circuit.A.RangeCheck(cs, params)
+	circuit.B.RangeCheck(cs, params)
+
+	var sum, diff, neg, prod, quo, prodConstant, inverse Element
+	sum.Add(cs, &circuit.A, &circuit.B, params)
+	diff.Sub(cs, &circuit.A, &circuit.B, params)
+	neg.Neg(cs, &circuit.A, params)
+	prod.Mul(cs, &circuit.A, &circuit.B, params)
+	quo.Div(cs, &circuit.A, &circuit.B, params)
+	prodConstant.MulConstant(cs, &circuit.A, circuit.constant, params)
+	inverse.Inverse(cs, &circuit.A, params)
+
+	AssertIsEqual(cs, &sum, &circuit.Sum, params)
+	AssertIsEqual(cs, &diff, &circuit.Diff, params)
+	AssertIsEqual(cs, &neg, &circuit.Neg, params)
+	AssertIsEqual(cs, &prod, &circuit.Prod, params)
+	AssertIsEqual(cs, &quo, &circuit.Quo, params)
+	AssertIsEqual(cs, &prodConstant, &circuit.ProdConstant, params)
+	AssertIsEqual(cs, &inverse, &circuit.InverseA, params)
+	return nil
+}
+
+func newArithmeticCircuit(params Params, constant *big.Int) arithmeticCircuit {
+	return arithmeticCircuit{
+		A:            params.Placeholder(),
+		B:            params.Placeholder(),
+		Sum:          params.Placeholder(),
+		Diff:         params.Placeholder(),
+		Neg:          params.Placeholder(),
+		Prod:         params.Placeholder(),
+		Quo:          params.Placeholder(),
+		ProdConstant: params.Placeholder(),
+		InverseA:     params.Placeholder(),
+		params:       params,
+		constant:     constant,
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// a prime of 255 bits, and a prime which is not aligned on the limbs
+	p25519, _ := new(big.Int).SetString("57896044618658097711785492504343953926634992332820282019728792003956564819949", 10)
+	moduli := []*big.Int{secp256k1Fp, p25519, big.NewInt(1000003)}
+
+	for _, modulus := range moduli {
+		for _, nbBits := range []int{64, 86} {
+			params, err := NewParams(modulus, nbBits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a := new(big.Int).Sub(modulus, big.NewInt(3))
+			b := new(big.Int).Rsh(modulus, 1)
+			constant := new(big.Int).Lsh(modulus, 2) // reduced modulo p
+			constant.Add(constant, big.NewInt(7))
+
+			var sum, diff, neg, prod, quo, prodConstant, inverse big.Int
+			sum.Add(a, b).Mod(&sum, modulus)
+			diff.Sub(a, b).Mod(&diff, modulus)
+			neg.Neg(a).Mod(&neg, modulus)
+			prod.Mul(a, b).Mod(&prod, modulus)
+			inverse.ModInverse(a, modulus)
+			quo.ModInverse(b, modulus).Mul(&quo, a).Mod(&quo, modulus)
+			prodConstant.Mul(a, constant).Mod(&prodConstant, modulus)
+
+			newWitness := func(prod *big.Int) *arithmeticCircuit {
+				var witness arithmeticCircuit
+				witness.A.Assign(a, params)
+				witness.B.Assign(b, params)
+				witness.Sum.Assign(&sum, params)
+				witness.Diff.Assign(&diff, params)
+				witness.Neg.Assign(&neg, params)
+				witness.Prod.Assign(prod, params)
+				witness.Quo.Assign(&quo, params)
+				witness.ProdConstant.Assign(&prodConstant, params)
+				witness.InverseA.Assign(&inverse, params)
+				return &witness
+			}
+
+			circuit := newArithmeticCircuit(params, constant)
+			assert.SolvingSucceeded(&circuit, newWitness(&prod), gurvy.BN256)
+
+			wrong := new(big.Int).Add(&prod, big.NewInt(1))
+			assert.SolvingFailed(&circuit, newWitness(wrong), gurvy.BN256)
+		}
+	}
+}
+
+type lazyCircuit struct {
+	A, B   Element
+	Result Element `gnark:",public"`
+	params Params
+}
+
+func (circuit *lazyCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	params := circuit.params
+	circuit.A.RangeCheck(cs, params)
+	circuit.B.RangeCheck(cs, params)
+
+	// the limbs of the sums and differences overflow more and more, until they are reduced
+	acc := circuit.A
+	for i := 0; i < 100; i++ {
+		acc.Add(cs, &acc, &circuit.A, params)
+		acc.Sub(cs, &acc, &circuit.B, params)
+	}
+	acc.Mul(cs, &acc, &acc, params)
+	AssertIsEqual(cs, &acc, &circuit.Result, params)
+	return nil
+}
+
+func TestLazyReduction(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	params, err := NewParams(secp256k1Fp, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := lazyCircuit{A: params.Placeholder(), B: params.Placeholder(), Result: params.Placeholder(), params: params}
+
+	a := new(big.Int).Sub(secp256k1Fp, big.NewInt(1))
+	b := big.NewInt(42)
+	var result big.Int
+	result.Mul(a, big.NewInt(101)).Sub(&result, new(big.Int).Mul(b, big.NewInt(100)))
+	result.Mul(&result, &result).Mod(&result, secp256k1Fp)
+
+	newWitness := func(result *big.Int) *lazyCircuit {
+		var witness lazyCircuit
+		witness.A.Assign(a, params)
+		witness.B.Assign(b, params)
+		witness.Result.Assign(result, params)
+		return &witness
+	}
+	assert.SolvingSucceeded(&circuit, newWitness(&result), gurvy.BN256)
+
+	wrong := new(big.Int).Add(&result, big.NewInt(1))
+	assert.SolvingFailed(&circuit, newWitness(wrong), gurvy.BN256)
+}
+
+type reducedCircuit struct {
+	A      Element
+	params Params
+}
+
+func (circuit *reducedCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.A.RangeCheck(cs, circuit.params)
+	AssertIsReduced(cs, &circuit.A, circuit.params)
+	return nil
+}
+
+func TestAssertIsReduced(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	params, err := NewParams(secp256k1Fp, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := reducedCircuit{A: params.Placeholder(), params: params}
+
+	// assign the limbs of v, which is not reduced by Assign
+	newWitness := func(v *big.Int) *reducedCircuit {
+		witness := reducedCircuit{A: params.Placeholder()}
+		limbs := params.decompose(v, params.NbLimbs)
+		for i := range limbs {
+			witness.A.Limbs[i].Assign(limbs[i])
+		}
+		return &witness
+	}
+
+	pMinusOne := new(big.Int).Sub(secp256k1Fp, big.NewInt(1))
+	assert.SolvingSucceeded(&circuit, newWitness(pMinusOne), gurvy.BN256)
+	assert.SolvingSucceeded(&circuit, newWitness(big.NewInt(0)), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(secp256k1Fp), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(new(big.Int).Add(secp256k1Fp, big.NewInt(5))), gurvy.BN256)
+}
+
+type rangeCheckCircuit struct {
+	A, B   Element
+	params Params
+}
+
+func (circuit *rangeCheckCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.A.RangeCheck(cs, circuit.params)
+	circuit.B.RangeCheck(cs, circuit.params)
+	var prod Element
+	prod.Mul(cs, &circuit.A, &circuit.A, circuit.params)
+	AssertIsEqual(cs, &prod, &circuit.B, circuit.params)
+	return nil
+}
+
+func TestRangeCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	params, err := NewParams(secp256k1Fp, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circuit := rangeCheckCircuit{A: params.Placeholder(), B: params.Placeholder(), params: params}
+
+	// 2^64 as a limb of 65 bits is the same integer as 1 in the second limb
+	witness := rangeCheckCircuit{A: params.Placeholder(), B: params.Placeholder()}
+	witness.A.Limbs[0].Assign(new(big.Int).Lsh(big.NewInt(1), 64))
+	witness.A.Limbs[1].Assign(0)
+	witness.A.Limbs[2].Assign(0)
+	witness.A.Limbs[3].Assign(0)
+	witness.B.Assign(new(big.Int).Lsh(big.NewInt(1), 128), params)
+	assert.SolvingFailed(&circuit, &witness, gurvy.BN256)
+
+	witness = rangeCheckCircuit{A: params.Placeholder(), B: params.Placeholder()}
+	witness.A.Assign(new(big.Int).Lsh(big.NewInt(1), 64), params)
+	witness.B.Assign(new(big.Int).Lsh(big.NewInt(1), 128), params)
+	assert.SolvingSucceeded(&circuit, &witness, gurvy.BN256)
+}
+
+func TestNewParams(t *testing.T) {
+	if _, err := NewParams(secp256k1Fp, 124); err == nil {
+		t.Fatal("limbs of 124 bits do not fit in the native field")
+	}
+	if _, err := NewParams(big.NewInt(1), 64); err == nil {
+		t.Fatal("the modulus must be greater than 1")
+	}
+	params, err := NewParams(secp256k1Fp, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.NbLimbs != 4 {
+		t.Fatal("a 256 bits modulus should have 4 limbs of 64 bits")
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	params, err := NewParams(secp256k1Fp, 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var nbConstraints uint64
+	for i := 0; i < b.N; i++ {
+		circuit := rangeCheckCircuit{A: params.Placeholder(), B: params.Placeholder(), params: params}
+		r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+		if err != nil {
+			b.Fatal(err)
+		}
+		nbConstraints = r1cs.GetNbConstraints()
+	}
+	b.ReportMetric(float64(nbConstraints), "constraints")
+}
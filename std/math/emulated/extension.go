@@ -0,0 +1,606 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ExtElement is an element of an extension of the field modulo Params.Modulus (see ExtField),
+// given by its coordinates in the basis 1, X, ..., X^(n-1)
+type ExtElement struct {
+	Coeffs []Element
+}
+
+// ExtPlaceholder returns an element of an extension of degree n whose coordinates are not
+// assigned, to be used in the definition of a circuit
+func (params Params) ExtPlaceholder(n int) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, n)}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = params.Placeholder()
+	}
+	return res
+}
+
+// Assign assigns the coordinates of e to coeffs (reduced modulo params.Modulus)
+func (e *ExtElement) Assign(coeffs []interface{}, params Params) {
+	if e.Coeffs == nil {
+		e.Coeffs = make([]Element, len(coeffs))
+	}
+	for i := range coeffs {
+		e.Coeffs[i].Assign(coeffs[i], params)
+	}
+}
+
+// ExtField implements the arithmetic of the extension Fp[X]/(X^n - Σ Poly[i] * X^i) of the field
+// modulo Params.Modulus, n being len(Poly). The polynomial must be irreducible, with small
+// coefficients: for instance Fp2 = Fp[X]/(X^2 + 1) is given by Poly = {-1, 0}.
+//
+// The coordinates of a product are computed on the limbs, without reduction, the polynomial
+// reduction being applied to the unreduced coordinates: a product costs n reductions modulo p,
+// instead of at least one per product of coordinates in a tower of extensions.
+type ExtField struct {
+	Base *Field
+	Poly []int64
+
+	// the coordinates of a product, before the reduction modulo p, are smaller than mulFactor
+	// times the largest product of two coordinates
+	mulFactor big.Int
+
+	// frobenius[k][j] are the coordinates of X^(j*p^k), computed when needed (see Frobenius)
+	frobenius map[int][][]big.Int
+}
+
+// NewExtField returns the arithmetic of Fp[X]/(X^n - Σ poly[i] * X^i) in cs, Fp being the field
+// modulo params.Modulus
+func NewExtField(cs *frontend.ConstraintSystem, params Params, poly []int64) *ExtField {
+	if len(poly) < 2 {
+		panic("emulated: the degree of an extension must be at least 2")
+	}
+	f := &ExtField{
+		Base:      NewField(cs, params),
+		Poly:      poly,
+		frobenius: make(map[int][][]big.Int),
+	}
+
+	// number of products of coordinates in each coordinate of a product, after the polynomial
+	// reduction (in absolute value)
+	n := len(poly)
+	counts := make([]big.Int, 2*n-1)
+	for k := range counts {
+		counts[k].SetInt64(int64(min(k, 2*n-2-k) + 1))
+	}
+	var tmp big.Int
+	for k := 2*n - 2; k >= n; k-- {
+		for i, c := range poly {
+			tmp.SetInt64(c).Abs(&tmp).Mul(&tmp, &counts[k])
+			counts[k-n+i].Add(&counts[k-n+i], &tmp)
+		}
+	}
+	for k := 0; k < n; k++ {
+		if counts[k].Cmp(&f.mulFactor) > 0 {
+			f.mulFactor.Set(&counts[k])
+		}
+	}
+	return f
+}
+
+// Degree returns the degree n of the extension
+func (f *ExtField) Degree() int {
+	return len(f.Poly)
+}
+
+// Constant returns the constant element whose coordinates are coeffs (the missing coordinates
+// are 0)
+func (f *ExtField) Constant(coeffs ...*big.Int) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		if i < len(coeffs) {
+			res.Coeffs[i] = f.Base.Constant(coeffs[i])
+		} else {
+			res.Coeffs[i] = f.Base.Zero()
+		}
+	}
+	return res
+}
+
+// Zero returns the constant 0
+func (f *ExtField) Zero() ExtElement {
+	return f.Constant()
+}
+
+// One returns the constant 1
+func (f *ExtField) One() ExtElement {
+	return f.Constant(big.NewInt(1))
+}
+
+// FromBase returns a, as an element of the extension
+func (f *ExtField) FromBase(a Element) ExtElement {
+	res := f.Zero()
+	res.Coeffs[0] = a
+	return res
+}
+
+// Add returns a+b
+func (f *ExtField) Add(a, b ExtElement) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Add(a.Coeffs[i], b.Coeffs[i])
+	}
+	return res
+}
+
+// Sub returns a-b
+func (f *ExtField) Sub(a, b ExtElement) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Sub(a.Coeffs[i], b.Coeffs[i])
+	}
+	return res
+}
+
+// Neg returns -a
+func (f *ExtField) Neg(a ExtElement) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Neg(a.Coeffs[i])
+	}
+	return res
+}
+
+// MulByBase returns a*c, c being an element of the base field
+func (f *ExtField) MulByBase(a ExtElement, c Element) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Mul(a.Coeffs[i], c)
+	}
+	return res
+}
+
+// Mul returns a*b
+func (f *ExtField) Mul(a, b ExtElement) ExtElement {
+	return f.MulSparse(a, b, nil)
+}
+
+// MulSparse returns a*b, the coordinates of b being 0 except those of the degrees in nonZero (all
+// of them if nonZero is nil): the products by the other coordinates are skipped
+func (f *ExtField) MulSparse(a, b ExtElement, nonZero []int) ExtElement {
+	if nonZero == nil {
+		nonZero = make([]int, f.Degree())
+		for i := range nonZero {
+			nonZero[i] = i
+		}
+	}
+	a, b = f.fitMul(a, b)
+
+	params := f.Base.Params
+	x := make([]lazy, 2*f.Degree()-1)
+	for i := range a.Coeffs {
+		for _, j := range nonZero {
+			x[i+j].add(f.Base.cs, params.lazyMul(f.Base.cs, &a.Coeffs[i], &b.Coeffs[j]), 1)
+		}
+	}
+	return f.reduce(x)
+}
+
+// Square returns a^2
+func (f *ExtField) Square(a ExtElement) ExtElement {
+	a, _ = f.fitMul(a, a)
+
+	// the products of distinct coordinates are computed once
+	params := f.Base.Params
+	x := make([]lazy, 2*f.Degree()-1)
+	for i := range a.Coeffs {
+		x[2*i].add(f.Base.cs, params.lazyMul(f.Base.cs, &a.Coeffs[i], &a.Coeffs[i]), 1)
+		for j := i + 1; j < len(a.Coeffs); j++ {
+			x[i+j].add(f.Base.cs, params.lazyMul(f.Base.cs, &a.Coeffs[i], &a.Coeffs[j]), 2)
+		}
+	}
+	return f.reduce(x)
+}
+
+// Div returns a/b. b must be invertible: the circuit is not satisfied otherwise, even if a is 0
+// (a*(1/b) is computed, and b is multiplied by its inverse, as in Element.Div)
+func (f *ExtField) Div(a, b ExtElement) ExtElement {
+	return f.Mul(a, f.Inverse(b))
+}
+
+// Inverse returns 1/a. a must be invertible: the circuit is not satisfied otherwise
+func (f *ExtField) Inverse(a ExtElement) ExtElement {
+	return f.divide(f.One(), a)
+}
+
+// divide returns a/b, given as a hint and constrained by res*b = a: if a and b are both 0, any res
+// satisfies the constraint, so a must not be 0
+func (f *ExtField) divide(a, b ExtElement) ExtElement {
+	cs := f.Base.cs
+	params := f.Base.Params
+	n := f.Degree()
+
+	operands := make([]interface{}, 0, 1+2*n+2*n*params.NbLimbs)
+	operands = append(operands, n)
+	for _, c := range f.Poly {
+		// the hints take nonnegative inputs
+		if c < 0 {
+			operands = append(operands, uint64(-c), 1)
+		} else {
+			operands = append(operands, uint64(c), 0)
+		}
+	}
+	for i := range a.Coeffs {
+		operands = append(operands, toInterfaces(a.Coeffs[i].Limbs)...)
+	}
+	for i := range b.Coeffs {
+		operands = append(operands, toInterfaces(b.Coeffs[i].Limbs)...)
+	}
+	limbs := params.hintLimbs(cs, extDivisionLimb, n*params.NbLimbs, operands)
+	res := ExtElement{Coeffs: make([]Element, n)}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = Element{Limbs: limbs[i*params.NbLimbs : (i+1)*params.NbLimbs]}
+		res.Coeffs[i].RangeCheck(cs, params)
+	}
+
+	// res*b - a = 0 mod p
+	_, b = f.fitMul(res, b)
+	x := make([]lazy, 2*n-1)
+	for i := range res.Coeffs {
+		for j := range b.Coeffs {
+			x[i+j].add(cs, params.lazyMul(cs, &res.Coeffs[i], &b.Coeffs[j]), 1)
+		}
+	}
+	for i := range a.Coeffs {
+		x[i].add(cs, params.lazyElement(&a.Coeffs[i]), -1)
+	}
+	f.fold(x)
+	for i := 0; i < n; i++ {
+		params.lazyAssertIsZero(cs, x[i])
+	}
+	return res
+}
+
+// Frobenius returns a^(p^k)
+func (f *ExtField) Frobenius(a ExtElement, k int) ExtElement {
+	k %= f.Degree()
+	if k == 0 {
+		return a
+	}
+	matrix, ok := f.frobenius[k]
+	if !ok {
+		matrix = f.frobeniusMatrix(k)
+		f.frobenius[k] = matrix
+	}
+
+	// the map is linear: the coordinates of a are multiplied by constants
+	cs := f.Base.cs
+	params := f.Base.Params
+	x := make([]lazy, f.Degree())
+	for j := range a.Coeffs {
+		for i := range x {
+			if matrix[j][i].Sign() != 0 {
+				x[i].add(cs, params.lazyMulConstant(cs, &a.Coeffs[j], &matrix[j][i]), 1)
+			}
+		}
+	}
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = params.lazyReduce(cs, x[i])
+	}
+	return res
+}
+
+// Select returns a if b is true, c otherwise (b must be boolean)
+func (f *ExtField) Select(b frontend.Variable, a, c ExtElement) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Select(b, a.Coeffs[i], c.Coeffs[i])
+	}
+	return res
+}
+
+// RangeCheck asserts that the limbs of the coordinates of a (part of the witness) are smaller than
+// 2^Params.NbBits
+func (f *ExtField) RangeCheck(a ExtElement) {
+	for i := range a.Coeffs {
+		f.Base.RangeCheck(a.Coeffs[i])
+	}
+}
+
+// AssertIsEqual asserts that a = b
+func (f *ExtField) AssertIsEqual(a, b ExtElement) {
+	for i := range a.Coeffs {
+		f.Base.AssertIsEqual(a.Coeffs[i], b.Coeffs[i])
+	}
+}
+
+// fitMul returns a and b, whose coordinates are reduced if the coordinates of their product would
+// not fit in the native field
+func (f *ExtField) fitMul(a, b ExtElement) (ExtElement, ExtElement) {
+	params := f.Base.Params
+	for {
+		overflowA, overflowB := a.overflow(), b.overflow()
+
+		// see lazyMul and lazyReduce
+		var bound big.Int
+		bound.Lsh(&f.mulFactor, uint(params.productBits(overflowA, overflowB)))
+		if bound.BitLen()+3 <= maxNbBits {
+			return a, b
+		}
+		if overflowA == 0 && overflowB == 0 {
+			panic("emulated: the products of the extension do not fit in the native field")
+		}
+		if overflowA >= overflowB {
+			a = f.reduceCoeffs(a)
+		} else {
+			b = f.reduceCoeffs(b)
+		}
+	}
+}
+
+// reduceCoeffs returns a, whose coordinates have limbs smaller than 2^Params.NbBits
+func (f *ExtField) reduceCoeffs(a ExtElement) ExtElement {
+	res := ExtElement{Coeffs: make([]Element, len(a.Coeffs))}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Reduce(a.Coeffs[i])
+	}
+	return res
+}
+
+// fold applies X^n = Σ Poly[i] * X^i to the coordinates of degree n and more of x
+func (f *ExtField) fold(x []lazy) {
+	n := f.Degree()
+	for k := len(x) - 1; k >= n; k-- {
+		for i, c := range f.Poly {
+			x[k-n+i].add(f.Base.cs, x[k], c)
+		}
+	}
+}
+
+// reduce returns the element whose unreduced coordinates (of degree up to 2n-2) are x
+func (f *ExtField) reduce(x []lazy) ExtElement {
+	f.fold(x)
+	res := ExtElement{Coeffs: make([]Element, f.Degree())}
+	for i := range res.Coeffs {
+		res.Coeffs[i] = f.Base.Params.lazyReduce(f.Base.cs, x[i])
+	}
+	return res
+}
+
+// overflow returns the largest overflow of the coordinates of e
+func (e ExtElement) overflow() int {
+	res := 0
+	for i := range e.Coeffs {
+		res = max(res, e.Coeffs[i].overflow)
+	}
+	return res
+}
+
+// -------------------------------------------------------------------------------------------------
+// unreduced products
+
+// lazy is the integer Σ limbs[i] * 2^(NbBits*i), whose limbs may be negative and are smaller
+// than bound in absolute value: the products and linear combinations of elements are computed
+// on the limbs, and reduced once (see lazyReduce)
+type lazy struct {
+	limbs []frontend.Variable
+	bound big.Int
+}
+
+// add sets l to l + c*x
+func (l *lazy) add(cs *frontend.ConstraintSystem, x lazy, c int64) {
+	if c == 0 || len(x.limbs) == 0 {
+		return
+	}
+	for len(l.limbs) < len(x.limbs) {
+		l.limbs = append(l.limbs, cs.Constant(0))
+	}
+	coeff := big.NewInt(c)
+	for i := range x.limbs {
+		switch c {
+		case 1:
+			l.limbs[i] = cs.Add(l.limbs[i], x.limbs[i])
+		case -1:
+			l.limbs[i] = cs.Sub(l.limbs[i], x.limbs[i])
+		default:
+			l.limbs[i] = cs.Add(l.limbs[i], cs.Mul(x.limbs[i], coeff))
+		}
+	}
+	var tmp big.Int
+	tmp.Abs(coeff).Mul(&tmp, &x.bound)
+	l.bound.Add(&l.bound, &tmp)
+}
+
+// lazyElement returns a as an unreduced integer
+func (params Params) lazyElement(a *Element) lazy {
+	res := lazy{limbs: make([]frontend.Variable, len(a.Limbs))}
+	copy(res.limbs, a.Limbs)
+	res.bound.Lsh(big.NewInt(1), uint(params.NbBits+a.overflow))
+	return res
+}
+
+// lazyMul returns the unreduced product of a and b
+func (params Params) lazyMul(cs *frontend.ConstraintSystem, a, b *Element) lazy {
+	res := lazy{limbs: params.mul(cs, a.Limbs, b.Limbs)}
+	res.bound.SetInt64(int64(params.NbLimbs)).Lsh(&res.bound, uint(2*params.NbBits+a.overflow+b.overflow))
+	return res
+}
+
+// lazyMulConstant returns the unreduced product of a and the constant c (0 <= c < p): no
+// constraint is added
+func (params Params) lazyMulConstant(cs *frontend.ConstraintSystem, a *Element, c *big.Int) lazy {
+	limbs := params.decompose(c, params.NbLimbs)
+	res := lazy{limbs: zeros(cs, len(a.Limbs)+len(limbs)-1)}
+	for i := range a.Limbs {
+		for j := range limbs {
+			if limbs[j].Sign() != 0 {
+				res.limbs[i+j] = cs.Add(res.limbs[i+j], cs.Mul(a.Limbs[i], &limbs[j]))
+			}
+		}
+	}
+	res.bound.SetInt64(int64(params.NbLimbs)).Lsh(&res.bound, uint(2*params.NbBits+a.overflow))
+	return res
+}
+
+// lazyReduce returns x mod p
+func (params Params) lazyReduce(cs *frontend.ConstraintSystem, x lazy) Element {
+	if len(x.limbs) == 0 {
+		return params.Constant(cs, big.NewInt(0))
+	}
+	nbBits := x.bound.BitLen()
+	return params.reduce(cs, params.pad(cs, x, nbBits), nbBits+2)
+}
+
+// lazyAssertIsZero asserts that x = 0 mod p
+func (params Params) lazyAssertIsZero(cs *frontend.ConstraintSystem, x lazy) {
+	if len(x.limbs) == 0 {
+		return
+	}
+	nbBits := x.bound.BitLen()
+	params.assertMod(cs, params.pad(cs, x, nbBits), nbBits+2, nil)
+}
+
+// pad returns the limbs of x plus a multiple of p, which are nonnegative and smaller than
+// 2^(nbBits+2), the limbs of x being smaller than 2^nbBits in absolute value
+func (params Params) pad(cs *frontend.ConstraintSystem, x lazy, nbBits int) []frontend.Variable {
+	pad := params.paddingLimbs(nbBits, max(len(x.limbs), params.NbLimbs))
+	res := make([]frontend.Variable, len(pad))
+	for i := range res {
+		if i < len(x.limbs) {
+			res[i] = cs.Add(x.limbs[i], pad[i])
+		} else {
+			res[i] = cs.Constant(pad[i])
+		}
+	}
+	return res
+}
+
+// -------------------------------------------------------------------------------------------------
+// native arithmetic, for the constants and the hints
+
+// polyMul returns a*b mod (X^n - Σ poly[i] * X^i), the coefficients being reduced modulo modulus
+func polyMul(a, b []big.Int, poly []int64, modulus *big.Int) []big.Int {
+	n := len(poly)
+	x := make([]big.Int, 2*n-1)
+	var tmp big.Int
+	for i := range a {
+		for j := range b {
+			tmp.Mul(&a[i], &b[j])
+			x[i+j].Add(&x[i+j], &tmp)
+		}
+	}
+	for k := 2*n - 2; k >= n; k-- {
+		x[k].Mod(&x[k], modulus)
+		for i, c := range poly {
+			tmp.SetInt64(c).Mul(&tmp, &x[k])
+			x[k-n+i].Add(&x[k-n+i], &tmp)
+		}
+	}
+	res := x[:n]
+	for i := range res {
+		res[i].Mod(&res[i], modulus)
+	}
+	return res
+}
+
+// frobeniusMatrix returns the coordinates of X^(j*p^k), for j < n
+func (f *ExtField) frobeniusMatrix(k int) [][]big.Int {
+	modulus := &f.Base.Params.Modulus
+	n := f.Degree()
+
+	// X^(p^k), by k exponentiations to the power p
+	xp := make([]big.Int, n)
+	xp[1].SetUint64(1)
+	for ; k > 0; k-- {
+		base := xp
+		xp = make([]big.Int, n)
+		xp[0].SetUint64(1)
+		for i := modulus.BitLen() - 1; i >= 0; i-- {
+			xp = polyMul(xp, xp, f.Poly, modulus)
+			if modulus.Bit(i) == 1 {
+				xp = polyMul(xp, base, f.Poly, modulus)
+			}
+		}
+	}
+
+	res := make([][]big.Int, n)
+	res[0] = make([]big.Int, n)
+	res[0][0].SetUint64(1)
+	for j := 1; j < n; j++ {
+		res[j] = polyMul(res[j-1], xp, f.Poly, modulus)
+	}
+	return res
+}
+
+// polyDiv returns a/b mod (X^n - Σ poly[i] * X^i), or nil if b is not invertible, by solving the
+// linear system given by the multiplication by b
+func polyDiv(a, b []big.Int, poly []int64, modulus *big.Int) []big.Int {
+	n := len(poly)
+
+	// the columns of the matrix are b*X^j, the last column is a
+	m := make([][]big.Int, n)
+	for i := range m {
+		m[i] = make([]big.Int, n+1)
+	}
+	col := b
+	for j := 0; j < n; j++ {
+		if j > 0 {
+			x := make([]big.Int, n)
+			x[1].SetUint64(1)
+			col = polyMul(col, x, poly, modulus)
+		}
+		for i := range m {
+			m[i][j].Set(&col[i])
+		}
+	}
+	for i := range m {
+		m[i][n].Mod(&a[i], modulus)
+	}
+
+	// gaussian elimination
+	var inv, tmp big.Int
+	for j := 0; j < n; j++ {
+		pivot := j
+		for pivot < n && m[pivot][j].Sign() == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil
+		}
+		m[j], m[pivot] = m[pivot], m[j]
+		inv.ModInverse(&m[j][j], modulus)
+		for c := j; c <= n; c++ {
+			m[j][c].Mul(&m[j][c], &inv).Mod(&m[j][c], modulus)
+		}
+		for i := range m {
+			if i == j || m[i][j].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Int).Set(&m[i][j])
+			for c := j; c <= n; c++ {
+				tmp.Mul(factor, &m[j][c])
+				m[i][c].Sub(&m[i][c], &tmp).Mod(&m[i][c], modulus)
+			}
+		}
+	}
+
+	res := make([]big.Int, n)
+	for i := range res {
+		res[i].Set(&m[i][n])
+	}
+	return res
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256"
+)
+
+type extensionCircuit struct {
+	A, B                    ExtElement
+	Prod, Square, Quo, Conj ExtElement `gnark:",public"`
+	params                  Params
+}
+
+func (circuit *extensionCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	// Fp2 = Fp[X]/(X^2 + 1)
+	f := NewExtField(cs, circuit.params, []int64{-1, 0})
+	f.RangeCheck(circuit.A)
+	f.RangeCheck(circuit.B)
+
+	f.AssertIsEqual(f.Mul(circuit.A, circuit.B), circuit.Prod)
+	f.AssertIsEqual(f.Square(f.Sub(circuit.A, circuit.B)), circuit.Square)
+	f.AssertIsEqual(f.Div(circuit.A, circuit.B), circuit.Quo)
+	f.AssertIsEqual(f.Frobenius(circuit.A, 1), circuit.Conj)
+	return nil
+}
+
+// TestExtension checks the arithmetic of the quadratic extension of the base field of BN256, in a
+// BN256 circuit, against gurvy
+func TestExtension(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := BN256Fp()
+
+	// the coordinates of GT are elements of Fp2 = Fp[u]/(u^2 + 1)
+	var gt bn256.GT
+	if _, err := gt.SetRandom(); err != nil {
+		t.Fatal(err)
+	}
+	a, b := gt.C0.B0, gt.C1.B2
+	prod, square, quo, conj := a, a, a, a
+	prod.Mul(&a, &b)
+	square.Sub(&a, &b).Square(&square)
+	quo.Inverse(&b).Mul(&quo, &a)
+	conj.Conjugate(&a)
+
+	witness := func(prodA0, prodA1 interface{}) *extensionCircuit {
+		var witness extensionCircuit
+		witness.A.Assign([]interface{}{&a.A0, &a.A1}, params)
+		witness.B.Assign([]interface{}{&b.A0, &b.A1}, params)
+		witness.Prod.Assign([]interface{}{prodA0, prodA1}, params)
+		witness.Square.Assign([]interface{}{&square.A0, &square.A1}, params)
+		witness.Quo.Assign([]interface{}{&quo.A0, &quo.A1}, params)
+		witness.Conj.Assign([]interface{}{&conj.A0, &conj.A1}, params)
+		return &witness
+	}
+
+	circuit := extensionCircuit{
+		A:      params.ExtPlaceholder(2),
+		B:      params.ExtPlaceholder(2),
+		Prod:   params.ExtPlaceholder(2),
+		Square: params.ExtPlaceholder(2),
+		Quo:    params.ExtPlaceholder(2),
+		Conj:   params.ExtPlaceholder(2),
+		params: params,
+	}
+	assert.SolvingSucceeded(&circuit, witness(&prod.A0, &prod.A1), gurvy.BN256)
+	assert.SolvingFailed(&circuit, witness(&prod.A1, &prod.A0), gurvy.BN256)
+
+	// a = b = 0: the other results are 0, but 0/0 has no solution
+	var zero [6]ExtElement
+	for i := range zero {
+		zero[i].Assign([]interface{}{0, 0}, params)
+	}
+	zeros := extensionCircuit{A: zero[0], B: zero[1], Prod: zero[2], Square: zero[3], Quo: zero[4], Conj: zero[5]}
+	assert.SolvingFailed(&circuit, &zeros, gurvy.BN256)
+}
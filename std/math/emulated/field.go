@@ -0,0 +1,189 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Field implements the arithmetic modulo Params.Modulus in a constraint system. It calls the
+// methods of Element, and is convenient to write gadgets over an emulated field:
+//
+//	f := emulated.NewField(cs, params)
+//	res := f.Add(f.Mul(a, b), c)
+//
+// The modulus does not need to be prime (e.g. an RSA modulus), but only the invertible elements
+// have an inverse.
+type Field struct {
+	cs     *frontend.ConstraintSystem
+	Params Params
+}
+
+// NewField returns the arithmetic modulo params.Modulus in cs
+func NewField(cs *frontend.ConstraintSystem, params Params) *Field {
+	return &Field{cs: cs, Params: params}
+}
+
+// Constant returns the constant element v
+func (f *Field) Constant(v *big.Int) Element {
+	return f.Params.Constant(f.cs, v)
+}
+
+// Zero returns the constant 0
+func (f *Field) Zero() Element {
+	return f.Constant(big.NewInt(0))
+}
+
+// One returns the constant 1
+func (f *Field) One() Element {
+	return f.Constant(big.NewInt(1))
+}
+
+// Add returns a+b
+func (f *Field) Add(a, b Element) Element {
+	var res Element
+	res.Add(f.cs, &a, &b, f.Params)
+	return res
+}
+
+// Sub returns a-b
+func (f *Field) Sub(a, b Element) Element {
+	var res Element
+	res.Sub(f.cs, &a, &b, f.Params)
+	return res
+}
+
+// Neg returns -a
+func (f *Field) Neg(a Element) Element {
+	var res Element
+	res.Neg(f.cs, &a, f.Params)
+	return res
+}
+
+// Mul returns a*b
+func (f *Field) Mul(a, b Element) Element {
+	var res Element
+	res.Mul(f.cs, &a, &b, f.Params)
+	return res
+}
+
+// MulConstant returns a*c
+func (f *Field) MulConstant(a Element, c *big.Int) Element {
+	var res Element
+	res.MulConstant(f.cs, &a, c, f.Params)
+	return res
+}
+
+// Square returns a^2
+func (f *Field) Square(a Element) Element {
+	return f.Mul(a, a)
+}
+
+// Div returns a/b. b must be invertible: the circuit is not satisfied otherwise, including when
+// a and b are both 0 (see Element.Div)
+func (f *Field) Div(a, b Element) Element {
+	var res Element
+	res.Div(f.cs, &a, &b, f.Params)
+	return res
+}
+
+// Inverse returns 1/a. a must be invertible: the circuit is not satisfied otherwise
+func (f *Field) Inverse(a Element) Element {
+	var res Element
+	res.Inverse(f.cs, &a, f.Params)
+	return res
+}
+
+// Exp returns a^e, e being a nonnegative constant (square and multiply)
+func (f *Field) Exp(a Element, e *big.Int) Element {
+	if e.Sign() == 0 {
+		return f.One()
+	}
+	res := a
+	for i := e.BitLen() - 2; i >= 0; i-- {
+		res = f.Square(res)
+		if e.Bit(i) == 1 {
+			res = f.Mul(res, a)
+		}
+	}
+	return res
+}
+
+// Select returns a if b is true, c otherwise (b must be boolean)
+func (f *Field) Select(b frontend.Variable, a, c Element) Element {
+	var res Element
+	res.Select(f.cs, b, &a, &c, f.Params)
+	return res
+}
+
+// Reduce returns a, with limbs smaller than 2^Params.NbBits
+func (f *Field) Reduce(a Element) Element {
+	var res Element
+	res.Reduce(f.cs, &a, f.Params)
+	return res
+}
+
+// ToBinary returns the bits of a (least significant bit first), which is not reduced modulo the
+// modulus
+func (f *Field) ToBinary(a Element) []frontend.Variable {
+	return a.ToBinary(f.cs, f.Params)
+}
+
+// FromBinary returns the element whose bits are b (least significant bit first). The bits are
+// asserted to be boolean.
+func (f *Field) FromBinary(b []frontend.Variable) Element {
+	nbBits := f.Params.NbBits
+	if len(b) > f.Params.NbLimbs*nbBits {
+		panic("emulated: too many bits for an element")
+	}
+
+	var coeff big.Int
+	limbs := make([]frontend.Variable, f.Params.NbLimbs)
+	for i := range limbs {
+		// the terms of a limb are summed at once
+		terms := []interface{}{f.cs.Constant(0)}
+		for j := 0; j < nbBits && i*nbBits+j < len(b); j++ {
+			f.cs.AssertIsBoolean(b[i*nbBits+j])
+			coeff.Lsh(big.NewInt(1), uint(j))
+			terms = append(terms, f.cs.Mul(b[i*nbBits+j], coeff))
+		}
+		if len(terms) == 1 {
+			limbs[i] = terms[0].(frontend.Variable)
+			continue
+		}
+		limbs[i] = f.cs.Add(terms[0], terms[1], terms[2:]...)
+	}
+	return Element{Limbs: limbs}
+}
+
+// RangeCheck asserts that the limbs of a (part of the witness) are smaller than 2^Params.NbBits
+func (f *Field) RangeCheck(a Element) {
+	a.RangeCheck(f.cs, f.Params)
+}
+
+// AssertIsEqual asserts that a = b
+func (f *Field) AssertIsEqual(a, b Element) {
+	AssertIsEqual(f.cs, &a, &b, f.Params)
+}
+
+// AssertIsReduced asserts that a (as an integer) is smaller than the modulus. The limbs of a must
+// not overflow (see Reduce).
+func (f *Field) AssertIsReduced(a Element) {
+	AssertIsReduced(f.cs, &a, f.Params)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+	fpbn256 "github.com/consensys/gurvy/bn256/fp"
+)
+
+type fieldCircuit struct {
+	A, B   Element
+	Result Element `gnark:",public"`
+	params Params
+}
+
+func (circuit *fieldCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	f := NewField(cs, circuit.params)
+	f.RangeCheck(circuit.A)
+	f.RangeCheck(circuit.B)
+
+	// ((a+b) * (a-b) / b)^5 - 1/a
+	res := f.Mul(f.Add(circuit.A, circuit.B), f.Sub(circuit.A, circuit.B))
+	res = f.Div(res, circuit.B)
+	res = f.Exp(res, big.NewInt(5))
+	res = f.Sub(res, f.Inverse(circuit.A))
+	f.AssertIsEqual(res, circuit.Result)
+
+	// the bits of a give back a
+	f.AssertIsEqual(f.FromBinary(f.ToBinary(circuit.A)), circuit.A)
+	return nil
+}
+
+// TestBN256InBN256 emulates the base field of BN256 in a BN256 circuit
+func TestBN256InBN256(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := BN256Fp()
+
+	var a, b, res, tmp fpbn256.Element
+	a.SetRandom()
+	b.SetRandom()
+	res.Add(&a, &b)
+	tmp.Sub(&a, &b)
+	res.Mul(&res, &tmp)
+	res.Div(&res, &b)
+	res.Exp(res, big.NewInt(5))
+	tmp.Inverse(&a)
+	res.Sub(&res, &tmp)
+
+	newWitness := func(res fpbn256.Element) *fieldCircuit {
+		var witness fieldCircuit
+		witness.A.Assign(&a, params)
+		witness.B.Assign(&b, params)
+		witness.Result.Assign(&res, params)
+		return &witness
+	}
+
+	circuit := fieldCircuit{A: params.Placeholder(), B: params.Placeholder(), Result: params.Placeholder(), params: params}
+	assert.SolvingSucceeded(&circuit, newWitness(res), gurvy.BN256)
+
+	var wrong fpbn256.Element
+	wrong.SetOne()
+	wrong.Add(&res, &wrong)
+	assert.SolvingFailed(&circuit, newWitness(wrong), gurvy.BN256)
+}
+
+type divCircuit struct {
+	A, B     Element
+	Quotient Element `gnark:",public"`
+	params   Params
+}
+
+func (circuit *divCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	f := NewField(cs, circuit.params)
+	f.RangeCheck(circuit.A)
+	f.RangeCheck(circuit.B)
+	f.AssertIsEqual(f.Div(circuit.A, circuit.B), circuit.Quotient)
+	return nil
+}
+
+// TestDivisionByZero checks that no quotient satisfies the circuit when the divisor is 0, even
+// if the dividend is 0 (then a*0 = 0 for any quotient)
+func TestDivisionByZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	params := BN256Fp()
+
+	newWitness := func(a, b, quotient int64) *divCircuit {
+		var witness divCircuit
+		witness.A.Assign(big.NewInt(a), params)
+		witness.B.Assign(big.NewInt(b), params)
+		witness.Quotient.Assign(big.NewInt(quotient), params)
+		return &witness
+	}
+
+	circuit := divCircuit{A: params.Placeholder(), B: params.Placeholder(), Quotient: params.Placeholder(), params: params}
+	assert.SolvingSucceeded(&circuit, newWitness(6, 3, 2), gurvy.BN256)
+	assert.SolvingSucceeded(&circuit, newWitness(0, 3, 0), gurvy.BN256)
+	for _, quotient := range []int64{0, 1, 42} {
+		assert.SolvingFailed(&circuit, newWitness(0, 0, quotient), gurvy.BN256)
+		assert.SolvingFailed(&circuit, newWitness(6, 0, quotient), gurvy.BN256)
+	}
+}
+
+type rsaCircuit struct {
+	Signature Element
+	Message   Element `gnark:",public"`
+	params    Params
+	exponent  int64
+}
+
+func (circuit *rsaCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	f := NewField(cs, circuit.params)
+	f.RangeCheck(circuit.Signature)
+	f.AssertIsEqual(f.Exp(circuit.Signature, big.NewInt(circuit.exponent)), circuit.Message)
+	return nil
+}
+
+// TestRSA checks a textbook RSA signature, the modulus of the key not being prime
+func TestRSA(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := key.PublicKey.N
+	params, err := NewParams(n, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := rand.Int(rand.Reader, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := new(big.Int).Exp(m, key.D, n)
+
+	newWitness := func(m *big.Int) *rsaCircuit {
+		var witness rsaCircuit
+		witness.Signature.Assign(s, params)
+		witness.Message.Assign(m, params)
+		return &witness
+	}
+
+	circuit := rsaCircuit{
+		Signature: params.Placeholder(),
+		Message:   params.Placeholder(),
+		params:    params,
+		exponent:  int64(key.PublicKey.E),
+	}
+	assert.SolvingSucceeded(&circuit, newWitness(m), gurvy.BN256)
+	assert.SolvingFailed(&circuit, newWitness(new(big.Int).Add(m, big.NewInt(1))), gurvy.BN256)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gurvy"
+)
+
+// the hints computing limbs take as inputs the size of a limb, the index of the limb to compute,
+// the number of limbs of the modulus and its limbs (it may be larger than the native field), and
+// then the limbs of their operands
+
+var errInvalidHintInputs = errors.New("invalid number of inputs")
+
+func init() {
+	hint.Register(quotientLimb)
+	hint.Register(remainderLimb)
+	hint.Register(divisionLimb)
+	hint.Register(extDivisionLimb)
+	hint.Register(shiftRight)
+}
+
+// recompose returns the integer Σ limbs[i] * 2^(nbBits*i)
+func recompose(limbs []*big.Int, nbBits uint) *big.Int {
+	res := new(big.Int)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		res.Lsh(res, nbBits).Add(res, limbs[i])
+	}
+	return res
+}
+
+// parseInputs returns the size of a limb, the index of the limb to compute, the modulus and the
+// limbs of the operands
+func parseInputs(inputs []*big.Int) (nbBits uint, index uint, modulus *big.Int, operands []*big.Int, err error) {
+	if len(inputs) < 3 || !inputs[2].IsUint64() || uint64(len(inputs)-3) < inputs[2].Uint64() {
+		return 0, 0, nil, nil, errInvalidHintInputs
+	}
+	nbBits = uint(inputs[0].Uint64())
+	index = uint(inputs[1].Uint64())
+	nbModulusLimbs := 3 + int(inputs[2].Uint64())
+	modulus = recompose(inputs[3:nbModulusLimbs], nbBits)
+	if modulus.Sign() == 0 {
+		return 0, 0, nil, nil, errInvalidHintInputs
+	}
+	return nbBits, index, modulus, inputs[nbModulusLimbs:], nil
+}
+
+// limb sets result to the index-th limb of v (nonnegative)
+func limb(v *big.Int, nbBits, index uint, result *big.Int) {
+	var mask big.Int
+	mask.Lsh(big.NewInt(1), nbBits).Sub(&mask, big.NewInt(1))
+	result.Rsh(v, nbBits*index).And(result, &mask)
+}
+
+// quotientLimb sets result to a limb of x / modulus, x being given by its limbs
+func quotientLimb(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	nbBits, index, modulus, operands, err := parseInputs(inputs)
+	if err != nil {
+		return err
+	}
+	x := recompose(operands, nbBits)
+	x.Quo(x, modulus)
+	limb(x, nbBits, index, result)
+	return nil
+}
+
+// remainderLimb sets result to a limb of x mod modulus, x being given by its limbs
+func remainderLimb(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	nbBits, index, modulus, operands, err := parseInputs(inputs)
+	if err != nil {
+		return err
+	}
+	x := recompose(operands, nbBits)
+	x.Mod(x, modulus)
+	limb(x, nbBits, index, result)
+	return nil
+}
+
+// divisionLimb sets result to a limb of a / b mod modulus. The first operand is the number of limbs
+// of a, followed by the limbs of a and b.
+//
+// If b is not invertible, result is set to 0 (and the circuit is not satisfied).
+func divisionLimb(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	nbBits, index, modulus, operands, err := parseInputs(inputs)
+	if err != nil {
+		return err
+	}
+	if len(operands) < 1 || !operands[0].IsUint64() || uint64(len(operands)-1) < operands[0].Uint64() {
+		return errInvalidHintInputs
+	}
+	nbLimbs := 1 + int(operands[0].Uint64())
+	a := recompose(operands[1:nbLimbs], nbBits)
+	b := recompose(operands[nbLimbs:], nbBits)
+	if b.ModInverse(b, modulus) == nil {
+		result.SetUint64(0)
+		return nil
+	}
+	a.Mul(a, b).Mod(a, modulus)
+	limb(a, nbBits, index, result)
+	return nil
+}
+
+// extDivisionLimb sets result to a limb of a / b in the extension of degree n given by poly (see
+// ExtField). The operands are n, the absolute values and the signs of the coefficients of poly,
+// and the limbs of the coordinates of a and b; the index is the index of the coordinate times
+// the number of limbs of a coordinate, plus the index of the limb.
+//
+// If b is not invertible, result is set to 0 (and the circuit is not satisfied).
+func extDivisionLimb(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	nbBits, index, modulus, operands, err := parseInputs(inputs)
+	if err != nil {
+		return err
+	}
+	nbLimbs := len(inputs) - len(operands) - 3
+	if len(operands) < 1 || !operands[0].IsUint64() {
+		return errInvalidHintInputs
+	}
+	n := int(operands[0].Uint64())
+	operands = operands[1:]
+	if n < 2 || len(operands) != 2*n+2*n*nbLimbs {
+		return errInvalidHintInputs
+	}
+
+	poly := make([]int64, n)
+	for i := range poly {
+		poly[i] = operands[2*i].Int64()
+		if operands[2*i+1].Sign() != 0 {
+			poly[i] = -poly[i]
+		}
+	}
+	operands = operands[2*n:]
+
+	a := make([]big.Int, n)
+	b := make([]big.Int, n)
+	for i := 0; i < n; i++ {
+		a[i].Set(recompose(operands[i*nbLimbs:(i+1)*nbLimbs], nbBits))
+		b[i].Set(recompose(operands[(n+i)*nbLimbs:(n+i+1)*nbLimbs], nbBits))
+	}
+	res := polyDiv(a, b, poly, modulus)
+	if res == nil || index >= uint(n*nbLimbs) {
+		result.SetUint64(0)
+		return nil
+	}
+	limb(&res[index/uint(nbLimbs)], nbBits, index%uint(nbLimbs), result)
+	return nil
+}
+
+// shiftRight sets result to inputs[0] >> inputs[1]
+func shiftRight(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	if len(inputs) != 2 {
+		return errInvalidHintInputs
+	}
+	result.Rsh(inputs[0], uint(inputs[1].Uint64()))
+	return nil
+}
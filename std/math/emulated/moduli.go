@@ -0,0 +1,68 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"math/big"
+
+	fpbls381 "github.com/consensys/gurvy/bls381/fp"
+	frbls381 "github.com/consensys/gurvy/bls381/fr"
+	fpbn256 "github.com/consensys/gurvy/bn256/fp"
+	frbn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+// the fields which are usually emulated, with limbs of 64 bits
+
+// BN256Fp returns the representation of the base field of BN256
+func BN256Fp() Params {
+	return mustParams(fpbn256.Modulus())
+}
+
+// BN256Fr returns the representation of the scalar field of BN256
+func BN256Fr() Params {
+	return mustParams(frbn256.Modulus())
+}
+
+// BLS381Fp returns the representation of the base field of BLS381
+func BLS381Fp() Params {
+	return mustParams(fpbls381.Modulus())
+}
+
+// BLS381Fr returns the representation of the scalar field of BLS381
+func BLS381Fr() Params {
+	return mustParams(frbls381.Modulus())
+}
+
+// Secp256k1Fp returns the representation of the base field of secp256k1
+func Secp256k1Fp() Params {
+	p, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	return mustParams(p)
+}
+
+// Secp256k1Fr returns the representation of the scalar field of secp256k1
+func Secp256k1Fr() Params {
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	return mustParams(n)
+}
+
+func mustParams(modulus *big.Int) Params {
+	params, err := NewParams(modulus, 64)
+	if err != nil {
+		panic(err)
+	}
+	return params
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// maxNbBits bounds the size (in bits, sign excluded) of the intermediate values checked in the
+// circuit: the smallest supported native field (the scalar field of BLS377) has 253 bits.
+const maxNbBits = 250
+
+// Params defines the representation of the integers modulo Modulus, as NbLimbs limbs of NbBits bits
+type Params struct {
+	Modulus big.Int
+	NbLimbs int // number of limbs of an element
+	NbBits  int // number of bits of a limb
+}
+
+// NewParams returns the representation of the integers modulo modulus, with limbs of nbBits bits
+func NewParams(modulus *big.Int, nbBits int) (Params, error) {
+	if modulus.Cmp(big.NewInt(2)) < 0 {
+		return Params{}, errors.New("the modulus must be greater than 1")
+	}
+	if nbBits < 2 {
+		return Params{}, errors.New("the limbs must have at least 2 bits")
+	}
+	nbLimbs := (modulus.BitLen() + nbBits - 1) / nbBits
+
+	res := Params{NbLimbs: nbLimbs, NbBits: nbBits}
+	res.Modulus.Set(modulus)
+
+	// the difference of two reduced elements must be representable
+	if res.maxOverflow() < 2 {
+		return Params{}, errors.New("the limbs are too large for the native field")
+	}
+	return res, nil
+}
+
+// maxOverflow returns the number of bits by which the limbs of an element may overflow: the limbs
+// of the product of two such elements, before the reduction, must fit in maxNbBits
+func (params Params) maxOverflow() int {
+	return (maxNbBits - 2 - 2*params.NbBits - bits.Len(uint(params.NbLimbs))) / 2
+}
+
+// Placeholder returns an element whose limbs are not assigned, to be used in the definition of a
+// circuit
+func (params Params) Placeholder() Element {
+	return Element{Limbs: make([]frontend.Variable, params.NbLimbs)}
+}
+
+// Constant returns the constant element v (reduced modulo params.Modulus)
+func (params Params) Constant(cs *frontend.ConstraintSystem, v *big.Int) Element {
+	var reduced big.Int
+	reduced.Mod(v, &params.Modulus)
+
+	limbs := params.decompose(&reduced, params.NbLimbs)
+	res := Element{Limbs: make([]frontend.Variable, params.NbLimbs)}
+	for i := range limbs {
+		res.Limbs[i] = cs.Constant(limbs[i])
+	}
+	return res
+}
+
+// decompose returns the nbLimbs limbs of v (nonnegative), least significant limb first
+func (params Params) decompose(v *big.Int, nbLimbs int) []big.Int {
+	res := make([]big.Int, nbLimbs)
+
+	var mask, tmp big.Int
+	mask.Lsh(big.NewInt(1), uint(params.NbBits)).Sub(&mask, big.NewInt(1))
+	tmp.Set(v)
+	for i := range res {
+		res[i].And(&tmp, &mask)
+		tmp.Rsh(&tmp, uint(params.NbBits))
+	}
+	return res
+}
+
+// padding returns limbs whose value is a multiple of the modulus, all greater than or equal to
+// 2^nbBits: adding them to the difference of two elements whose limbs have less than nbBits bits
+// gives nonnegative limbs, without changing the value modulo the modulus.
+//
+// The limbs of the padding are smaller than 2^(nbBits+1).
+func (params Params) padding(nbBits int) []big.Int {
+	return params.paddingLimbs(nbBits, params.NbLimbs)
+}
+
+// paddingLimbs returns nbLimbs limbs (at least NbLimbs) as padding does, for the unreduced
+// products which have more limbs than an element
+func (params Params) paddingLimbs(nbBits, nbLimbs int) []big.Int {
+	res := make([]big.Int, nbLimbs)
+
+	// start with 2^nbBits in each limb, then add the limbs of the opposite of the total value
+	var total, limbValue big.Int
+	for i := range res {
+		res[i].Lsh(big.NewInt(1), uint(nbBits))
+		limbValue.Lsh(&res[i], uint(i*params.NbBits))
+		total.Add(&total, &limbValue)
+	}
+	total.Neg(&total).Mod(&total, &params.Modulus)
+
+	complement := params.decompose(&total, params.NbLimbs)
+	for i := range complement {
+		res[i].Add(&res[i], &complement[i])
+	}
+	return res
+}
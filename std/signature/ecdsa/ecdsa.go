@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ecdsa implements the verification of ECDSA signatures on short Weierstrass curves whose
+// base field is not the native field, such as secp256k1 (see std/algebra/weierstrass).
+package ecdsa
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// PublicKey stores an ecdsa public key (to be used in gnark circuit)
+type PublicKey struct {
+	Q weierstrass.Point
+}
+
+// Signature stores a signature (to be used in gnark circuit)
+type Signature struct {
+	R, S emulated.Element
+}
+
+// NewPublicKey returns a public key whose coordinates are not assigned, to be used in the
+// definition of a circuit
+func NewPublicKey(curve weierstrass.Curve) PublicKey {
+	return PublicKey{Q: curve.Placeholder()}
+}
+
+// NewSignature returns a signature which is not assigned, to be used in the definition of a circuit
+func NewSignature(curve weierstrass.Curve) Signature {
+	return Signature{R: curve.Fr.Placeholder(), S: curve.Fr.Placeholder()}
+}
+
+// Assign assigns the public key to the point (x, y)
+func (pub *PublicKey) Assign(x, y interface{}, curve weierstrass.Curve) {
+	pub.Q.Assign(x, y, curve)
+}
+
+// Assign assigns the signature to (r, s)
+func (sig *Signature) Assign(r, s interface{}, curve weierstrass.Curve) {
+	sig.R.Assign(r, curve.Fr)
+	sig.S.Assign(s, curve.Fr)
+}
+
+// Verify verifies an ecdsa signature of the message whose hash is msgHash (an element of the
+// scalar field: the hash, truncated to the size of curve.Order, reduced modulo curve.Order).
+// cf https://en.wikipedia.org/wiki/Elliptic_Curve_Digital_Signature_Algorithm
+//
+// The inputs are range checked, and the public key is checked to be on the curve. The scalar
+// multiplication uses incomplete formulas: a valid signature is rejected if the public key is
+// the generator or its opposite, which happens with negligible probability for a random key.
+// The exceptional cases of the formulas can't be used to forge a signature: the slopes are
+// divisions whose divisor is constrained to be invertible, so the circuit is not satisfied
+// when two added points are equal or opposite.
+func Verify(cs *frontend.ConstraintSystem, sig Signature, msgHash emulated.Element, pubKey PublicKey, curve weierstrass.Curve) {
+	fr := curve.Fr
+
+	// r and s are in [1, n-1]: r is reduced and invertible, s is inverted below
+	sig.R.RangeCheck(cs, fr)
+	sig.S.RangeCheck(cs, fr)
+	msgHash.RangeCheck(cs, fr)
+	emulated.AssertIsReduced(cs, &sig.R, fr)
+	var rInv emulated.Element
+	rInv.Inverse(cs, &sig.R, fr)
+	pubKey.Q.MustBeOnCurve(cs, curve)
+
+	// u1 = z/s, u2 = r/s
+	var sInv, u1, u2 emulated.Element
+	sInv.Inverse(cs, &sig.S, fr)
+	u1.Mul(cs, &msgHash, &sInv, fr)
+	u2.Mul(cs, &sig.R, &sInv, fr)
+
+	// (x, y) = u1*G + u2*Q
+	var p weierstrass.Point
+	g := curve.Generator(cs)
+	p.JointScalarMul(cs, &g, &pubKey.Q, &u1, &u2, curve)
+
+	// r = x mod n: x is reduced modulo p, and its limbs are read as an integer modulo n (the
+	// fields of the curves of std/algebra/weierstrass have the same number of limbs)
+	var x emulated.Element
+	x.Reduce(cs, &p.X, curve.Fp)
+	emulated.AssertIsReduced(cs, &x, curve.Fp)
+	emulated.AssertIsEqual(cs, &x, &sig.R, fr)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"github.com/consensys/gurvy"
+)
+
+type ecdsaCircuit struct {
+	PublicKey PublicKey        `gnark:",public"`
+	Signature Signature        `gnark:",public"`
+	MsgHash   emulated.Element `gnark:",public"`
+	curve     weierstrass.Curve
+}
+
+func (circuit *ecdsaCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	Verify(cs, circuit.Signature, circuit.MsgHash, circuit.PublicKey, circuit.curve)
+	return nil
+}
+
+func newECDSACircuit(curve weierstrass.Curve) ecdsaCircuit {
+	return ecdsaCircuit{
+		PublicKey: NewPublicKey(curve),
+		Signature: NewSignature(curve),
+		MsgHash:   curve.Fr.Placeholder(),
+		curve:     curve,
+	}
+}
+
+func TestECDSA(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the verification of a secp256k1 signature has about 2 million constraints")
+	}
+	assert := test.NewAssert(t)
+	curve := weierstrass.Secp256k1()
+
+	// sign a message with crypto/ecdsa
+	privKey, err := ecdsa.GenerateKey(secp256k1, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte("gnark"))
+	r, s, err := ecdsa.Sign(rand.Reader, privKey, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.Verify(&privKey.PublicKey, hash[:], r, s) {
+		t.Fatal("crypto/ecdsa should verify the signature")
+	}
+
+	newWitness := func(msgHash *big.Int) *ecdsaCircuit {
+		witness := newECDSACircuit(curve)
+		witness.PublicKey.Assign(privKey.X, privKey.Y, curve)
+		witness.Signature.Assign(r, s, curve)
+		witness.MsgHash.Assign(msgHash, curve.Fr)
+		return &witness
+	}
+
+	circuit := newECDSACircuit(curve)
+	z := new(big.Int).SetBytes(hash[:])
+	assert.SolvingSucceeded(&circuit, newWitness(z), gurvy.BN256)
+
+	wrong := new(big.Int).Add(z, big.NewInt(1))
+	assert.SolvingFailed(&circuit, newWitness(wrong), gurvy.BN256)
+}
+
+// -------------------------------------------------------------------------------------------------
+// secp256k1 as an elliptic.Curve (elliptic.CurveParams implements the curves with a = -3), for
+// crypto/ecdsa
+
+type secp256k1Curve struct {
+	params *elliptic.CurveParams
+}
+
+var secp256k1 = newSecp256k1()
+
+func newSecp256k1() secp256k1Curve {
+	params := &elliptic.CurveParams{Name: "secp256k1", BitSize: 256}
+	params.P, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	params.N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	params.B = big.NewInt(7)
+	params.Gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	params.Gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	return secp256k1Curve{params}
+}
+
+func (curve secp256k1Curve) Params() *elliptic.CurveParams {
+	return curve.params
+}
+
+func (curve secp256k1Curve) IsOnCurve(x, y *big.Int) bool {
+	p := curve.params.P
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, p)
+	rhs := new(big.Int).Mul(x, x)
+	rhs.Mul(rhs, x).Add(rhs, curve.params.B).Mod(rhs, p)
+	return lhs.Cmp(rhs) == 0
+}
+
+// Add returns (x1, y1) + (x2, y2), the point at infinity being (0, 0)
+func (curve secp256k1Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := curve.params.P
+	if x1.Sign() == 0 && y1.Sign() == 0 {
+		return x2, y2
+	}
+	if x2.Sign() == 0 && y2.Sign() == 0 {
+		return x1, y1
+	}
+
+	var l, tmp big.Int
+	if x1.Cmp(x2) == 0 {
+		if tmp.Add(y1, y2).Mod(&tmp, p).Sign() == 0 {
+			return new(big.Int), new(big.Int)
+		}
+		// l = 3x^2 / 2y
+		l.Mul(x1, x1).Mul(&l, big.NewInt(3))
+		tmp.Lsh(y1, 1).ModInverse(&tmp, p)
+	} else {
+		// l = (y2-y1) / (x2-x1)
+		l.Sub(y2, y1)
+		tmp.Sub(x2, x1).Mod(&tmp, p).ModInverse(&tmp, p)
+	}
+	l.Mul(&l, &tmp).Mod(&l, p)
+
+	x := new(big.Int).Mul(&l, &l)
+	x.Sub(x, x1).Sub(x, x2).Mod(x, p)
+	y := new(big.Int).Sub(x1, x)
+	y.Mul(y, &l).Sub(y, y1).Mod(y, p)
+	return x, y
+}
+
+func (curve secp256k1Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return curve.Add(x1, y1, x1, y1)
+}
+
+func (curve secp256k1Curve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	x, y := new(big.Int), new(big.Int)
+	s := new(big.Int).SetBytes(k)
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		x, y = curve.Double(x, y)
+		if s.Bit(i) == 1 {
+			x, y = curve.Add(x, y, x1, y1)
+		}
+	}
+	return x, y
+}
+
+func (curve secp256k1Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return curve.ScalarMult(curve.params.Gx, curve.params.Gy, k)
+}
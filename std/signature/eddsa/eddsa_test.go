@@ -17,15 +17,26 @@ limitations under the License.
 package eddsa
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	mimc_bls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
+	mimc_bls381 "github.com/consensys/gnark/crypto/hash/mimc/bls381"
 	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	mimc_bw761 "github.com/consensys/gnark/crypto/hash/mimc/bw761"
+	eddsa_bls377 "github.com/consensys/gnark/crypto/signature/eddsa/bls377"
+	eddsa_bls381 "github.com/consensys/gnark/crypto/signature/eddsa/bls381"
 	eddsa_bn256 "github.com/consensys/gnark/crypto/signature/eddsa/bn256"
+	eddsa_bw761 "github.com/consensys/gnark/crypto/signature/eddsa/bw761"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/twistededwards"
 	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
 	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
 )
 
 type eddsaCircuit struct {
@@ -35,7 +46,7 @@ type eddsaCircuit struct {
 }
 
 func (circuit *eddsaCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
-	params, err := twistededwards.NewEdCurve(gurvy.BN256)
+	params, err := twistededwards.NewEdCurve(curveID)
 	if err != nil {
 		return err
 	}
@@ -47,6 +58,88 @@ func (circuit *eddsaCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSys
 	return nil
 }
 
+// signedMessage stores a message, a public key and a signature of the message, as computed by the
+// eddsa package of the curve
+type signedMessage struct {
+	msg, pubX, pubY, rX, rY, s interface{}
+}
+
+// signers sign a message (reduced in the scalar field of the curve) with a key derived from seed
+var signers = map[gurvy.ID]func(t *testing.T, seed [32]byte, msg string) signedMessage{
+	gurvy.BN256: func(t *testing.T, seed [32]byte, msg string) signedMessage {
+		pubKey, privKey := eddsa_bn256.New(seed, mimc_bn256.NewMiMC("seed"))
+		var frMsg fr_bn256.Element
+		frMsg.SetString(msg)
+		msgBin := frMsg.Bytes()
+		signature, err := eddsa_bn256.Sign(msgBin[:], pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := eddsa_bn256.Verify(signature, msgBin[:], pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("Verifying the signature should return true")
+		}
+		return signedMessage{frMsg, pubKey.A.X, pubKey.A.Y, signature.R.X, signature.R.Y, signature.S}
+	},
+	gurvy.BLS381: func(t *testing.T, seed [32]byte, msg string) signedMessage {
+		pubKey, privKey := eddsa_bls381.New(seed, mimc_bls381.NewMiMC("seed"))
+		var frMsg fr_bls381.Element
+		frMsg.SetString(msg)
+		msgBin := frMsg.Bytes()
+		signature, err := eddsa_bls381.Sign(msgBin[:], pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := eddsa_bls381.Verify(signature, msgBin[:], pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("Verifying the signature should return true")
+		}
+		return signedMessage{frMsg, pubKey.A.X, pubKey.A.Y, signature.R.X, signature.R.Y, signature.S}
+	},
+	gurvy.BLS377: func(t *testing.T, seed [32]byte, msg string) signedMessage {
+		pubKey, privKey := eddsa_bls377.New(seed, mimc_bls377.NewMiMC("seed"))
+		var frMsg fr_bls377.Element
+		frMsg.SetString(msg)
+		msgBin := frMsg.Bytes()
+		signature, err := eddsa_bls377.Sign(msgBin[:], pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := eddsa_bls377.Verify(signature, msgBin[:], pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("Verifying the signature should return true")
+		}
+		return signedMessage{frMsg, pubKey.A.X, pubKey.A.Y, signature.R.X, signature.R.Y, signature.S}
+	},
+	gurvy.BW761: func(t *testing.T, seed [32]byte, msg string) signedMessage {
+		pubKey, privKey := eddsa_bw761.New(seed, mimc_bw761.NewMiMC("seed"))
+		var frMsg fr_bw761.Element
+		frMsg.SetString(msg)
+		msgBin := frMsg.Bytes()
+		signature, err := eddsa_bw761.Sign(msgBin[:], pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := eddsa_bw761.Verify(signature, msgBin[:], pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("Verifying the signature should return true")
+		}
+		return signedMessage{frMsg, pubKey.A.X, pubKey.A.Y, signature.R.X, signature.R.Y, signature.S}
+	},
+}
+
 func TestEddsa(t *testing.T) {
 
 	assert := groth16.NewAssert(t)
@@ -57,61 +150,68 @@ func TestEddsa(t *testing.T) {
 		seed[i] = v
 	}
 
-	hFunc := mimc_bn256.NewMiMC("seed")
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
 
-	// generate eddsa witnesses from the crypto lib
-	pubKey, privKey := eddsa_bn256.New(seed, hFunc)
+		// generate eddsa witnesses from the crypto lib
+		signed := signers[id](t, seed, "44717650746155748460101257525078853138837311576962212923649547644148297035978")
 
-	var frMsg fr_bn256.Element
-	frMsg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
-	msgBin := frMsg.Bytes()
-	signature, err := eddsa_bn256.Sign(msgBin[:], pubKey, privKey)
-	if err != nil {
-		t.Fatal(err)
-	}
-	res, err := eddsa_bn256.Verify(signature, msgBin[:], pubKey)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if !res {
-		t.Fatal("Verifying the signature should return true")
-	}
+		var circuit eddsaCircuit
+		r1cs, err := frontend.Compile(id, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	var circuit eddsaCircuit
-	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
-	if err != nil {
-		t.Fatal(err)
-	}
+		// verification with the correct Message
+		{
+			var witness eddsaCircuit
+			witness.Message.Assign(signed.msg)
 
-	// verification with the correct Message
-	{
-		var witness eddsaCircuit
-		witness.Message.Assign(frMsg)
+			witness.PublicKey.A.X.Assign(signed.pubX)
+			witness.PublicKey.A.Y.Assign(signed.pubY)
 
-		witness.PublicKey.A.X.Assign(pubKey.A.X)
-		witness.PublicKey.A.Y.Assign(pubKey.A.Y)
+			witness.Signature.R.A.X.Assign(signed.rX)
+			witness.Signature.R.A.Y.Assign(signed.rY)
 
-		witness.Signature.R.A.X.Assign(signature.R.X)
-		witness.Signature.R.A.Y.Assign(signature.R.Y)
+			witness.Signature.S.Assign(signed.s)
 
-		witness.Signature.S.Assign(signature.S)
+			assert.SolvingSucceeded(r1cs, &witness)
+		}
 
-		assert.SolvingSucceeded(r1cs, &witness)
-	}
+		// verification with incorrect Message
+		{
+			var witness eddsaCircuit
+			msg := backend.FromInterface(signed.msg)
+			msg.Add(&msg, big.NewInt(1))
+			witness.Message.Assign(msg)
 
-	// verification with incorrect Message
-	{
-		var witness eddsaCircuit
-		witness.Message.Assign("44717650746155748460101257525078853138837311576962212923649547644148297035979")
+			witness.PublicKey.A.X.Assign(signed.pubX)
+			witness.PublicKey.A.Y.Assign(signed.pubY)
 
-		witness.PublicKey.A.X.Assign(pubKey.A.X)
-		witness.PublicKey.A.Y.Assign(pubKey.A.Y)
+			witness.Signature.R.A.X.Assign(signed.rX)
+			witness.Signature.R.A.Y.Assign(signed.rY)
 
-		witness.Signature.R.A.X.Assign(signature.R.X)
-		witness.Signature.R.A.Y.Assign(signature.R.Y)
+			witness.Signature.S.Assign(signed.s)
 
-		witness.Signature.S.Assign(signature.S)
+			assert.SolvingFailed(r1cs, &witness)
+		}
+	}
+}
 
-		assert.SolvingFailed(r1cs, &witness)
+func TestEddsaNbConstraints(t *testing.T) {
+	// the scalars are decomposed on 256 bits on the curves whose scalar field fits on it,
+	// such that the circuits on BN256 and BLS381 don't change
+	expected := map[gurvy.ID]uint64{
+		gurvy.BN256:  20000,
+		gurvy.BLS381: 19545,
+	}
+	for id, nbConstraints := range expected {
+		var circuit eddsaCircuit
+		r1cs, err := frontend.Compile(id, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r1cs.GetNbConstraints() != nbConstraints {
+			t.Fatal(id, "expected", nbConstraints, "constraints, got", r1cs.GetNbConstraints())
+		}
 	}
 }
@@ -0,0 +1,66 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+	"github.com/stretchr/testify/require"
+)
+
+// curves on which the circuits are tested when none is specified
+var curves = []gurvy.ID{gurvy.BN256, gurvy.BLS377, gurvy.BLS381, gurvy.BW761}
+
+// Assert is a helper to test circuits with the test engine
+type Assert struct {
+	*require.Assertions
+}
+
+// NewAssert returns an Assert helper
+func NewAssert(t *testing.T) *Assert {
+	return &Assert{require.New(t)}
+}
+
+// SolvingSucceeded checks that witness solves circuit on each of curveIDs (all the curves if empty)
+//
+// witness must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) SolvingSucceeded(circuit frontend.Circuit, witness interface{}, curveIDs ...gurvy.ID) {
+	if len(curveIDs) == 0 {
+		curveIDs = curves
+	}
+	for _, curveID := range curveIDs {
+		err := IsSolved(circuit, witness, curveID)
+		assert.NoError(err, "%s: solving with a good witness should not output an error", curveID.String())
+	}
+}
+
+// SolvingFailed checks that witness does NOT solve circuit on each of curveIDs (all the curves if empty)
+//
+// witness must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) SolvingFailed(circuit frontend.Circuit, witness interface{}, curveIDs ...gurvy.ID) {
+	if len(curveIDs) == 0 {
+		curveIDs = curves
+	}
+	for _, curveID := range curveIDs {
+		err := IsSolved(circuit, witness, curveID)
+		assert.Error(err, "%s: solving with a bad witness should output an error", curveID.String())
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package test provides helpers to test circuits and gadgets without compiling them.
+//
+// The circuit's Define method is executed on the values of a witness, with big.Int arithmetic
+// modulo the scalar field of the curve: no R1CS is built, no setup is run, and a failure
+// reports the Go stack trace of the API call which added the unsatisfied constraint.
+package test
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+// IsSolved executes circuit.Define with the values of witness on the scalar field of curveID,
+// and returns an error if a constraint is not satisfied (see frontend.Execute).
+//
+// circuit must not be assigned; witness must be map[string]interface{} or must implement
+// frontend.Circuit ( see frontend.ParseWitness )
+func IsSolved(circuit frontend.Circuit, witness interface{}, curveID gurvy.ID) error {
+	return frontend.Execute(curveID, circuit, witness)
+}
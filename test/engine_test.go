@@ -0,0 +1,163 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type cubicCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+// Define declares x**3 + x + 5 == y
+func (circuit *cubicCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	x3 := cs.Mul(circuit.X, circuit.X, circuit.X)
+	cs.AssertIsEqual(circuit.Y, cs.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestCubic(t *testing.T) {
+	assert := NewAssert(t)
+	var circuit, good, bad cubicCircuit
+
+	good.X.Assign(3)
+	good.Y.Assign(35)
+	assert.SolvingSucceeded(&circuit, &good)
+
+	bad.X.Assign(3)
+	bad.Y.Assign(42)
+	assert.SolvingFailed(&circuit, &bad)
+
+	// the error points to the API call which added the unsatisfied constraint
+	err := IsSolved(&circuit, &bad, gurvy.BN256)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint), "unexpected error %v", err)
+	assert.Contains(err.Error(), "frontend.(*ConstraintSystem).AssertIsEqual")
+	assert.Contains(err.Error(), "test.(*cubicCircuit).Define")
+	assert.Contains(err.Error(), "engine_test.go")
+
+	// missing input
+	err = IsSolved(&circuit, map[string]interface{}{"X": 3}, gurvy.BN256)
+	assert.True(errors.Is(err, backend.ErrInputNotSet), "unexpected error %v", err)
+}
+
+type inverseCircuit struct {
+	X frontend.Variable
+}
+
+// Define declares the inverse of x
+func (circuit *inverseCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	cs.Inverse(circuit.X)
+	return nil
+}
+
+func TestInverse(t *testing.T) {
+	assert := NewAssert(t)
+	var circuit inverseCircuit
+
+	assert.SolvingSucceeded(&circuit, map[string]interface{}{"X": 3})
+
+	// the computational constraint x * (1/x) == 1 fails as it is added: the error points to the
+	// API call which added it
+	err := IsSolved(&circuit, map[string]interface{}{"X": 0}, gurvy.BN256)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint), "unexpected error %v", err)
+	assert.Contains(err.Error(), "frontend.(*ConstraintSystem).Inverse")
+	assert.Contains(err.Error(), "test.(*inverseCircuit).Define")
+	assert.NotContains(err.Error(), "frontend.(*engine)")
+}
+
+type gadgetCircuit struct {
+	A, B frontend.Variable
+	C    frontend.Variable `gnark:",public"`
+}
+
+// Define declares c == 2*h + (a xor b), with a and b as booleans, c as a 4 bits integer
+// and h computed by a hint
+func (circuit *gadgetCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	bits := cs.ToBinary(circuit.C, 4)
+	cs.AssertIsEqual(cs.Xor(circuit.A, circuit.B), bits[0])
+
+	h := cs.NewHint(halfHint, circuit.C)
+	cs.AssertIsEqual(cs.Add(cs.Mul(h, 2), bits[0]), circuit.C)
+	return nil
+}
+
+func halfHint(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	result.Rsh(inputs[0], 1)
+	return nil
+}
+
+func TestGadget(t *testing.T) {
+	assert := NewAssert(t)
+	var circuit gadgetCircuit
+
+	assert.SolvingSucceeded(&circuit, map[string]interface{}{"A": 1, "B": 0, "C": 11})
+	assert.SolvingSucceeded(&circuit, map[string]interface{}{"A": 1, "B": 1, "C": 6})
+
+	// A is not a boolean
+	err := IsSolved(&circuit, map[string]interface{}{"A": 2, "B": 1, "C": 3}, gurvy.BN256)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint), "unexpected error %v", err)
+	assert.Contains(err.Error(), "frontend.(*ConstraintSystem).Xor")
+
+	// C doesn't fit on 4 bits
+	err = IsSolved(&circuit, map[string]interface{}{"A": 0, "B": 0, "C": 16}, gurvy.BN256)
+	assert.True(errors.Is(err, backend.ErrUnsatisfiedConstraint), "unexpected error %v", err)
+	assert.Contains(err.Error(), "frontend.(*ConstraintSystem).ToBinary")
+	// the witness also solves the R1CS
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	assert.NoError(err)
+	assert.NoError(r1cs.IsSolved(map[string]interface{}{"A": 1, "B": 0, "C": 11}))
+	assert.Error(r1cs.IsSolved(map[string]interface{}{"A": 1, "B": 1, "C": 11}))
+
+}
+
+type curveIDCircuit struct {
+	ID frontend.Variable `gnark:",public"`
+}
+
+// Define declares that ID is the curve ID the hints are called with
+func (circuit *curveIDCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	cs.AssertIsEqual(cs.NewHint(curveIDHint), circuit.ID)
+	return nil
+}
+
+func curveIDHint(curveID gurvy.ID, inputs []*big.Int, result *big.Int) error {
+	result.SetUint64(uint64(curveID))
+	return nil
+}
+
+func TestHintCurveID(t *testing.T) {
+	assert := NewAssert(t)
+	var circuit curveIDCircuit
+
+	for _, curveID := range []gurvy.ID{gurvy.BN256, gurvy.BLS377, gurvy.BLS381, gurvy.BW761} {
+		witness := map[string]interface{}{"ID": uint64(curveID)}
+		assert.NoError(IsSolved(&circuit, witness, curveID), curveID.String())
+
+		// as the R1CS solver
+		r1cs, err := frontend.Compile(curveID, &circuit)
+		assert.NoError(err)
+		assert.NoError(r1cs.IsSolved(witness), curveID.String())
+	}
+}
@@ -0,0 +1,396 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+// Reference is a plain Go implementation of a circuit: it returns true if witness should solve
+// the circuit on curveID. The values of witness are reduced modulo the scalar field of curveID.
+type Reference func(curveID gurvy.ID, witness map[string]*big.Int) bool
+
+// FuzzOption enables optional behaviours of Fuzz
+type FuzzOption func(opt *fuzzConfig)
+
+type fuzzConfig struct {
+	curves    []gurvy.ID
+	nbTests   int
+	witnesses []interface{}
+	noProver  bool
+	seed      *int64
+}
+
+// WithCurves sets the curves on which the circuit is fuzzed (all the curves by default)
+func WithCurves(curveIDs ...gurvy.ID) FuzzOption {
+	return func(opt *fuzzConfig) {
+		opt.curves = curveIDs
+	}
+}
+
+// WithNbTests sets the number of witnesses tested on each curve, for each property
+func WithNbTests(nbTests int) FuzzOption {
+	return func(opt *fuzzConfig) {
+		opt.nbTests = nbTests
+	}
+}
+
+// WithWitnesses adds valid witnesses, mutated to find under-constrained circuits. Random witnesses
+// seldom solve a circuit: most circuits need at least one valid witness to be mutated.
+//
+// witnesses must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func WithWitnesses(witnesses ...interface{}) FuzzOption {
+	return func(opt *fuzzConfig) {
+		opt.witnesses = append(opt.witnesses, witnesses...)
+	}
+}
+
+// WithoutProver doesn't run groth16.Prove and groth16.Verify, which are much slower than
+// solving the R1CS
+func WithoutProver() FuzzOption {
+	return func(opt *fuzzConfig) {
+		opt.noProver = true
+	}
+}
+
+// WithSeed seeds the random generator of gopter, which is seeded with the current time by default,
+// so that the generated witnesses are the same on every run
+func WithSeed(seed int64) FuzzOption {
+	return func(opt *fuzzConfig) {
+		opt.seed = &seed
+	}
+}
+
+// Fuzz checks that reference, the test engine (see IsSolved), R1CS.IsSolved and groth16 agree on
+// witnesses generated with gopter:
+//
+// 1. random witnesses, whose values are boundary values (0, 1, -1, modulus-1, ...), random bit
+// patterns or random field elements
+//
+// 2. mutations of the valid witnesses (see WithWitnesses, and the random witnesses accepted by
+// reference): an input is replaced by another value, incremented, decremented, or has a bit flipped
+//
+// A witness which solves the circuit but is rejected by reference is reported as under-constrained.
+// circuit must not be assigned.
+func (assert *Assert) Fuzz(circuit frontend.Circuit, reference Reference, opts ...FuzzOption) {
+	config := fuzzConfig{curves: curves, nbTests: gopter.DefaultTestParameters().MinSuccessfulTests}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	for _, curveID := range config.curves {
+		f, err := newFuzzer(circuit, reference, curveID, &config)
+		assert.NoError(err, "%s: compiling the circuit", curveID.String())
+
+		for _, witness := range config.witnesses {
+			values, err := frontend.ParseWitness(witness)
+			assert.NoError(err)
+			w := make(map[string]*big.Int, len(values))
+			for name, v := range values {
+				b := backend.FromInterface(v)
+				w[name] = &b
+			}
+			assert.True(reference(curveID, f.reduce(w)), "%s: reference rejects a valid witness", curveID.String())
+			assert.NoError(f.check(w), "%s: valid witness", curveID.String())
+		}
+
+		parameters := gopter.DefaultTestParameters()
+		if config.seed != nil {
+			parameters = gopter.DefaultTestParametersWithSeed(*config.seed)
+		}
+		parameters.MinSuccessfulTests = config.nbTests
+
+		properties := gopter.NewProperties(parameters)
+		properties.Property("random witnesses", prop.ForAll(
+			func(w map[string]*big.Int) *gopter.PropResult {
+				return f.propResult(w)
+			},
+			f.genWitness(),
+		))
+		assert.True(properties.Run(gopter.ConsoleReporter(false)), "%s: fuzzing failed, see the reported witness", curveID.String())
+
+		// the random witnesses accepted by reference are mutated too
+		if len(f.valid) == 0 {
+			continue
+		}
+		properties = gopter.NewProperties(parameters)
+		properties.Property("mutated witnesses", prop.ForAll(
+			func(m mutation) *gopter.PropResult {
+				return f.propResult(m.apply(f))
+			},
+			f.genMutation(),
+		))
+		assert.True(properties.Run(gopter.ConsoleReporter(false)), "%s: fuzzing failed, see the reported witness", curveID.String())
+	}
+}
+
+// fuzzer cross checks the solvers of a circuit on a curve
+type fuzzer struct {
+	circuit   frontend.Circuit
+	reference Reference
+	curveID   gurvy.ID
+	modulus   *big.Int
+
+	r1cs           r1cs.R1CS
+	pk             groth16.ProvingKey
+	vk             groth16.VerifyingKey
+	public, secret []string // names of the inputs
+
+	valid []map[string]*big.Int // witnesses accepted by reference, to be mutated
+}
+
+func newFuzzer(circuit frontend.Circuit, reference Reference, curveID gurvy.ID, config *fuzzConfig) (*fuzzer, error) {
+	f := &fuzzer{circuit: circuit, reference: reference, curveID: curveID}
+
+	var err error
+	if f.r1cs, err = frontend.Compile(curveID, circuit); err != nil {
+		return nil, err
+	}
+	// the circuit is compiled again for the next curve
+	frontend.ResetInputs(circuit)
+
+	var untypedR1CS *r1cs.UntypedR1CS
+	untypedR1CS, f.modulus = untyped(f.r1cs)
+	f.public, f.secret = untypedR1CS.PublicWires, untypedR1CS.SecretWires
+	for i, name := range f.public {
+		if name == backend.OneWire {
+			f.public = append(f.public[:i:i], f.public[i+1:]...)
+			break
+		}
+	}
+
+	if !config.noProver {
+		if f.pk, f.vk, err = groth16.Setup(f.r1cs); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// names returns the names of the inputs of the circuit, in a deterministic order
+func (f *fuzzer) names() []string {
+	names := append(append([]string{}, f.public...), f.secret...)
+	sort.Strings(names)
+	return names
+}
+
+// reduce returns the values of w modulo the scalar field
+func (f *fuzzer) reduce(w map[string]*big.Int) map[string]*big.Int {
+	reduced := make(map[string]*big.Int, len(w))
+	for name, v := range w {
+		reduced[name] = new(big.Int).Mod(v, f.modulus)
+	}
+	return reduced
+}
+
+// check returns an error if the solvers don't agree with the reference on w
+func (f *fuzzer) check(w map[string]*big.Int) error {
+	expected := f.reference(f.curveID, f.reduce(w))
+
+	witness := make(map[string]interface{}, len(w))
+	publicWitness := make(map[string]interface{}, len(f.public))
+	for name, v := range w {
+		witness[name] = v
+	}
+	for _, name := range f.public {
+		publicWitness[name] = w[name]
+	}
+
+	diagnostic := func(solver string, err error) error {
+		if expected {
+			return fmt.Errorf("%s rejects a witness accepted by the reference: %v", solver, err)
+		}
+		return fmt.Errorf("%s accepts a witness rejected by the reference: the circuit is under-constrained", solver)
+	}
+
+	if err := IsSolved(f.circuit, witness, f.curveID); (err == nil) != expected {
+		return diagnostic("the test engine", err)
+	}
+	if err := f.r1cs.IsSolved(witness); (err == nil) != expected {
+		return diagnostic("R1CS.IsSolved", err)
+	}
+	if f.pk != nil {
+		proof, err := groth16.Prove(f.r1cs, f.pk, witness)
+		if err == nil {
+			err = groth16.Verify(proof, f.vk, publicWitness)
+		}
+		if (err == nil) != expected {
+			return diagnostic("groth16", err)
+		}
+	}
+
+	if expected {
+		f.valid = append(f.valid, w)
+	}
+	return nil
+}
+
+func (f *fuzzer) propResult(w map[string]*big.Int) *gopter.PropResult {
+	if err := f.check(w); err != nil {
+		return gopter.NewPropResult(false, fmt.Sprintf("%v\nwitness: %s", err, formatWitness(w)))
+	}
+	return gopter.NewPropResult(true, "")
+}
+
+func formatWitness(w map[string]*big.Int) string {
+	names := make([]string, 0, len(w))
+	for name := range w {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for i, name := range names {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name + "=" + w[name].String())
+	}
+	return sb.String()
+}
+
+// genValue generates boundary values, random bit patterns and random field elements
+func (f *fuzzer) genValue() gopter.Gen {
+	modulus := f.modulus
+	var minusOne, modulusMinusOne, modulusPlusOne big.Int
+	minusOne.SetInt64(-1)
+	modulusMinusOne.Sub(modulus, big.NewInt(1))
+	modulusPlusOne.Add(modulus, big.NewInt(1))
+
+	boundaries := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2), &minusOne,
+		&modulusMinusOne, new(big.Int).Set(modulus), &modulusPlusOne,
+	}
+	genBoundary := gen.IntRange(0, len(boundaries)-1).Map(func(i int) *big.Int {
+		return new(big.Int).Set(boundaries[i])
+	})
+
+	nbWords := (modulus.BitLen() + 63) / 64
+	genWords := gen.SliceOfN(nbWords, gen.UInt64())
+
+	// nbBits random bits, or 1 followed by nbBits-1 zeros, or nbBits ones
+	genBits := gopter.CombineGens(gen.IntRange(1, modulus.BitLen()), gen.IntRange(0, 2), genWords).
+		Map(func(values []interface{}) *big.Int {
+			nbBits := values[0].(int)
+			res := new(big.Int)
+			switch values[1].(int) {
+			case 0:
+				res = leadingBits(values[2].([]uint64), nbBits)
+			case 1:
+				res.Lsh(big.NewInt(1), uint(nbBits-1))
+			case 2:
+				res.Lsh(big.NewInt(1), uint(nbBits)).Sub(res, big.NewInt(1))
+			}
+			return res
+		})
+
+	genElement := genWords.Map(func(words []uint64) *big.Int {
+		res := wordsToBig(words)
+		return res.Mod(res, modulus)
+	})
+
+	return gen.OneGenOf(genBoundary, gen.UInt64Range(0, 16).Map(func(v uint64) *big.Int {
+		return new(big.Int).SetUint64(v)
+	}), genBits, genElement)
+}
+
+// wordsToBig returns the integer whose big-endian 64 bits words are words
+func wordsToBig(words []uint64) *big.Int {
+	res := new(big.Int)
+	for _, w := range words {
+		res.Lsh(res, 64).Add(res, new(big.Int).SetUint64(w))
+	}
+	return res
+}
+
+// leadingBits returns the integer formed by the nbBits most significant bits of words (see wordsToBig)
+func leadingBits(words []uint64, nbBits int) *big.Int {
+	res := wordsToBig(words)
+	return res.Rsh(res, uint(64*len(words)-nbBits))
+}
+
+// genWitness generates random witnesses for all the inputs of the circuit
+func (f *fuzzer) genWitness() gopter.Gen {
+	names := f.names()
+	genValues := make([]gopter.Gen, len(names))
+	for i := range genValues {
+		genValues[i] = f.genValue()
+	}
+	return gopter.CombineGens(genValues...).Map(func(values []interface{}) map[string]*big.Int {
+		w := make(map[string]*big.Int, len(names))
+		for i, name := range names {
+			w[name] = values[i].(*big.Int)
+		}
+		return w
+	})
+}
+
+// mutation of a valid witness
+type mutation struct {
+	witness, input int // indexes of the valid witness and the mutated input (modulo their number)
+	kind           int // 0: replace, 1: increment, 2: decrement, 3: flip a bit
+	value          *big.Int
+	bit            int
+}
+
+func (f *fuzzer) genMutation() gopter.Gen {
+	return gopter.CombineGens(gen.IntRange(0, 1<<16), gen.IntRange(0, 1<<16), gen.IntRange(0, 3), f.genValue(), gen.IntRange(0, f.modulus.BitLen()-1)).
+		Map(func(values []interface{}) mutation {
+			return mutation{values[0].(int), values[1].(int), values[2].(int), values[3].(*big.Int), values[4].(int)}
+		})
+}
+
+// apply returns a copy of a valid witness of f, mutated by m
+func (m mutation) apply(f *fuzzer) map[string]*big.Int {
+	valid := f.valid[m.witness%len(f.valid)]
+	w := make(map[string]*big.Int, len(valid))
+	for name, v := range valid {
+		w[name] = new(big.Int).Set(v)
+	}
+
+	names := f.names()
+	if len(names) == 0 {
+		return w
+	}
+	name := names[m.input%len(names)]
+	v := new(big.Int).Mod(w[name], f.modulus)
+	switch m.kind {
+	case 0:
+		v.Set(m.value)
+	case 1:
+		v.Add(v, big.NewInt(1))
+	case 2:
+		v.Sub(v, big.NewInt(1))
+	case 3:
+		v.SetBit(v, m.bit, v.Bit(m.bit)^1)
+	}
+	w[name] = v
+	return w
+}
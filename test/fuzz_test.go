@@ -0,0 +1,123 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/consensys/gurvy"
+	"github.com/stretchr/testify/require"
+)
+
+func cubicReference(curveID gurvy.ID, witness map[string]*big.Int) bool {
+	x := witness["X"]
+	var y big.Int
+	y.Mul(x, x).Mul(&y, x).Add(&y, x).Add(&y, big.NewInt(5)).Mod(&y, utils.ScalarField(curveID))
+	return y.Cmp(witness["Y"]) == 0
+}
+
+func TestFuzzCubic(t *testing.T) {
+	assert := NewAssert(t)
+	var circuit cubicCircuit
+
+	assert.Fuzz(&circuit, cubicReference,
+		WithNbTests(20),
+		WithWitnesses(map[string]interface{}{"X": 3, "Y": 35}),
+	)
+}
+
+type muxCircuit struct {
+	B, X, Y frontend.Variable
+	Z       frontend.Variable `gnark:",public"`
+	// if set, B is not constrained to be a boolean
+	underConstrained bool
+}
+
+// Define declares z == b ? y : x
+func (circuit *muxCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	if circuit.underConstrained {
+		cs.AssertIsEqual(circuit.Z, cs.Add(cs.Mul(circuit.B, cs.Sub(circuit.Y, circuit.X)), circuit.X))
+		return nil
+	}
+	cs.AssertIsEqual(circuit.Z, cs.Select(circuit.B, circuit.Y, circuit.X))
+	return nil
+}
+
+func muxReference(curveID gurvy.ID, witness map[string]*big.Int) bool {
+	b := witness["B"]
+	switch {
+	case b.Cmp(big.NewInt(0)) == 0:
+		return witness["Z"].Cmp(witness["X"]) == 0
+	case b.Cmp(big.NewInt(1)) == 0:
+		return witness["Z"].Cmp(witness["Y"]) == 0
+	default:
+		return false
+	}
+}
+
+// failures records the failures of an Assert
+type failures []string
+
+func (f *failures) Errorf(format string, args ...interface{}) {
+	*f = append(*f, fmt.Sprintf(format, args...))
+}
+
+func (f *failures) FailNow() {}
+
+func TestFuzzUnderConstrained(t *testing.T) {
+	valid := map[string]interface{}{"B": 1, "X": 7, "Y": 7, "Z": 7}
+
+	NewAssert(t).Fuzz(&muxCircuit{}, muxReference,
+		WithCurves(gurvy.BN256),
+		WithNbTests(20),
+		WithWitnesses(valid),
+		WithoutProver(),
+	)
+
+	// b = 2, x = y = z is accepted by the under-constrained circuit, and rejected by the reference.
+	// The mutations of the valid witness which find it are random: the seed is fixed so that the
+	// test doesn't depend on them.
+	var f failures
+	assert := &Assert{require.New(&f)}
+	assert.Fuzz(&muxCircuit{underConstrained: true}, muxReference,
+		WithCurves(gurvy.BN256),
+		WithNbTests(100),
+		WithWitnesses(valid),
+		WithoutProver(),
+		WithSeed(42),
+	)
+	if len(f) == 0 {
+		t.Fatal("fuzzing should detect that the circuit is under-constrained")
+	}
+}
+
+func TestLeadingBits(t *testing.T) {
+	ones := []uint64{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+	for _, nbBits := range []int{1, 2, 63, 64, 65, 254, 256} {
+		expected := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+		expected.Sub(expected, big.NewInt(1))
+		require.Equal(t, 0, leadingBits(ones, nbBits).Cmp(expected), "%d bits", nbBits)
+	}
+
+	words := []uint64{0xa000000000000000, 0, 0, 1}
+	require.Equal(t, "5", leadingBits(words, 3).String())
+	require.Equal(t, "10", leadingBits(words, 4).String())
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/backend/r1cs"
+	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
+	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
+	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
+	backend_bw761 "github.com/consensys/gnark/internal/backend/bw761"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// untyped returns the curve independent form of a compiled R1CS, and the modulus of its scalar field
+func untyped(r r1cs.R1CS) (*r1cs.UntypedR1CS, *big.Int) {
+	var res r1cs.UntypedR1CS
+
+	switch _r := r.(type) {
+	case *backend_bls377.R1CS:
+		res = r1cs.UntypedR1CS{NbWires: _r.NbWires, NbPublicWires: _r.NbPublicWires, NbSecretWires: _r.NbSecretWires, SecretWires: _r.SecretWires, PublicWires: _r.PublicWires, Logs: _r.Logs, DebugInfo: _r.DebugInfo, NbConstraints: _r.NbConstraints, NbCOConstraints: _r.NbCOConstraints, Constraints: _r.Constraints, Hints: _r.Hints}
+		res.Coefficients = make([]big.Int, len(_r.Coefficients))
+		for i := range _r.Coefficients {
+			_r.Coefficients[i].ToBigIntRegular(&res.Coefficients[i])
+		}
+	case *backend_bls381.R1CS:
+		res = r1cs.UntypedR1CS{NbWires: _r.NbWires, NbPublicWires: _r.NbPublicWires, NbSecretWires: _r.NbSecretWires, SecretWires: _r.SecretWires, PublicWires: _r.PublicWires, Logs: _r.Logs, DebugInfo: _r.DebugInfo, NbConstraints: _r.NbConstraints, NbCOConstraints: _r.NbCOConstraints, Constraints: _r.Constraints, Hints: _r.Hints}
+		res.Coefficients = make([]big.Int, len(_r.Coefficients))
+		for i := range _r.Coefficients {
+			_r.Coefficients[i].ToBigIntRegular(&res.Coefficients[i])
+		}
+	case *backend_bn256.R1CS:
+		res = r1cs.UntypedR1CS{NbWires: _r.NbWires, NbPublicWires: _r.NbPublicWires, NbSecretWires: _r.NbSecretWires, SecretWires: _r.SecretWires, PublicWires: _r.PublicWires, Logs: _r.Logs, DebugInfo: _r.DebugInfo, NbConstraints: _r.NbConstraints, NbCOConstraints: _r.NbCOConstraints, Constraints: _r.Constraints, Hints: _r.Hints}
+		res.Coefficients = make([]big.Int, len(_r.Coefficients))
+		for i := range _r.Coefficients {
+			_r.Coefficients[i].ToBigIntRegular(&res.Coefficients[i])
+		}
+	case *backend_bw761.R1CS:
+		res = r1cs.UntypedR1CS{NbWires: _r.NbWires, NbPublicWires: _r.NbPublicWires, NbSecretWires: _r.NbSecretWires, SecretWires: _r.SecretWires, PublicWires: _r.PublicWires, Logs: _r.Logs, DebugInfo: _r.DebugInfo, NbConstraints: _r.NbConstraints, NbCOConstraints: _r.NbCOConstraints, Constraints: _r.Constraints, Hints: _r.Hints}
+		res.Coefficients = make([]big.Int, len(_r.Coefficients))
+		for i := range _r.Coefficients {
+			_r.Coefficients[i].ToBigIntRegular(&res.Coefficients[i])
+		}
+	case *r1cs.UntypedR1CS:
+		res = *_r
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+
+	return &res, utils.ScalarField(r.GetCurveID())
+}
@@ -0,0 +1,334 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+)
+
+// UnderConstrainedReport lists the wires of a R1CS which are likely under-constrained
+// (see FindUnderConstrained)
+type UnderConstrainedReport struct {
+	// secret and internal wires which appear in no constraint: the prover can set them to any value
+	Unconstrained []WireReport
+
+	// internal wires computed by a constraint and used in no other constraint: the computation
+	// doesn't constrain anything
+	UnusedOutputs []WireReport
+
+	// wires used as bits (of a binary decomposition, or computed by hint.IthBit) which are not
+	// constrained to be boolean
+	NonBoolean []WireReport
+}
+
+// WireReport describes a wire of a R1CS
+type WireReport struct {
+	ID        int      // id of the wire in the R1CS (wires = [internal | secret | public])
+	Name      string   // name of the input, or description of the internal wire
+	DebugInfo []string // debug info of the assertions and the logs referencing the wire
+}
+
+// IsEmpty returns true if no wire is reported
+func (report *UnderConstrainedReport) IsEmpty() bool {
+	return len(report.Unconstrained) == 0 && len(report.UnusedOutputs) == 0 && len(report.NonBoolean) == 0
+}
+
+func (report *UnderConstrainedReport) String() string {
+	var sb strings.Builder
+	write := func(title string, wires []WireReport) {
+		if len(wires) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%d %s:\n", len(wires), title))
+		for _, w := range wires {
+			sb.WriteString("\t" + w.Name + "\n")
+			for _, debugInfo := range w.DebugInfo {
+				sb.WriteString("\t\t" + strings.ReplaceAll(debugInfo, "\n", "\n\t\t") + "\n")
+			}
+		}
+	}
+	write("unconstrained wires", report.Unconstrained)
+	write("unused outputs", report.UnusedOutputs)
+	write("bits not constrained to be boolean", report.NonBoolean)
+	if sb.Len() == 0 {
+		return "no under-constrained wire"
+	}
+	return sb.String()
+}
+
+// FindUnderConstrained analyzes the constraints of a compiled R1CS and reports the wires which are
+// likely under-constrained:
+//
+// 1. secret and internal wires which appear in no constraint (for example, a wire computed by a hint
+// but never asserted)
+//
+// 2. internal wires which appear in a single computational constraint, unless another wire of
+// that constraint is computed by a hint and constrained elsewhere (for example, the inverse
+// computed by IsZero, whose constraint checks the result of the hint)
+//
+// 3. bits of a binary decomposition and results of hint.IthBit missing their boolean constraint
+// (see ConstraintSystem.AssertIsBoolean)
+//
+// The analysis is static: an empty report doesn't prove that the circuit is sound.
+func FindUnderConstrained(r r1cs.R1CS) *UnderConstrainedReport {
+	a := newAnalysis(r)
+	report := &UnderConstrainedReport{}
+
+	for w := 0; w < a.nbInternal+a.nbSecret; w++ {
+		switch {
+		case len(a.constraints[w]) == 0:
+			report.Unconstrained = append(report.Unconstrained, a.wireReport(w))
+		case w < a.nbInternal && len(a.constraints[w]) == 1:
+			c := a.constraints[w][0]
+			if c < int(a.r1cs.NbCOConstraints) && !a.constrainsHint(a.r1cs.Constraints[c], w) {
+				report.UnusedOutputs = append(report.UnusedOutputs, a.wireReport(w))
+			}
+		}
+	}
+
+	for _, w := range a.bits {
+		if !a.isBoolean[w] {
+			report.NonBoolean = append(report.NonBoolean, a.wireReport(w))
+		}
+	}
+
+	return report
+}
+
+// NotUnderConstrained checks that FindUnderConstrained reports no wire of r1cs
+func (assert *Assert) NotUnderConstrained(r1cs r1cs.R1CS) {
+	report := FindUnderConstrained(r1cs)
+	assert.True(report.IsEmpty(), report.String())
+}
+
+// analysis of the wires of a R1CS
+type analysis struct {
+	r1cs                            *r1cs.UntypedR1CS
+	modulus                         *big.Int
+	nbInternal, nbSecret, nbPublic  int
+	oneWire                         int
+	constraints                     [][]int // indexes of the constraints in which each wire appears
+	isHint, isBoolean, isIthBitHint []bool
+	bits                            []int // wires used as bits, in increasing order
+	debugInfo                       [][]string
+}
+
+func newAnalysis(r r1cs.R1CS) *analysis {
+	a := &analysis{}
+	a.r1cs, a.modulus = untyped(r)
+	nbWires := int(a.r1cs.NbWires)
+	a.nbSecret, a.nbPublic = int(a.r1cs.NbSecretWires), int(a.r1cs.NbPublicWires)
+	a.nbInternal = nbWires - a.nbSecret - a.nbPublic
+
+	a.oneWire = -1
+	for i, name := range a.r1cs.PublicWires {
+		if name == backend.OneWire {
+			a.oneWire = a.nbInternal + a.nbSecret + i
+		}
+	}
+
+	a.constraints = make([][]int, nbWires)
+	a.isHint = make([]bool, nbWires)
+	a.isBoolean = make([]bool, nbWires)
+	a.isIthBitHint = make([]bool, nbWires)
+	a.debugInfo = make([][]string, nbWires)
+
+	ithBit := hint.UUID(hint.IthBit)
+	for _, h := range a.r1cs.Hints {
+		a.isHint[h.WireID] = true
+		a.isIthBitHint[h.WireID] = h.ID == ithBit
+	}
+
+	isBit := make([]bool, nbWires)
+	for i, c := range a.r1cs.Constraints {
+		seen := make(map[int]struct{})
+		for _, l := range [3]r1c.LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				w := t.VariableID()
+				if _, ok := seen[w]; ok {
+					continue
+				}
+				seen[w] = struct{}{}
+				a.constraints[w] = append(a.constraints[w], i)
+			}
+		}
+
+		if c.Solver == r1c.BinaryDec {
+			for _, t := range c.L {
+				isBit[t.VariableID()] = true
+			}
+		}
+		if w, ok := a.booleanConstraint(c); ok {
+			a.isBoolean[w] = true
+		}
+	}
+	for w := 0; w < nbWires; w++ {
+		if isBit[w] || a.isIthBitHint[w] {
+			a.bits = append(a.bits, w)
+		}
+	}
+
+	// debug info of the logs, and of the assertions (for all the wires of the assertion)
+	for _, entry := range a.r1cs.Logs {
+		formatted := a.format(entry)
+		for _, w := range entry.ToResolve {
+			a.addDebugInfo(w, formatted)
+		}
+	}
+	for k, entry := range a.r1cs.DebugInfo {
+		formatted := a.format(entry)
+		for _, w := range entry.ToResolve {
+			a.addDebugInfo(w, formatted)
+		}
+		c := a.r1cs.Constraints[int(a.r1cs.NbCOConstraints)+k]
+		for _, l := range [3]r1c.LinearExpression{c.L, c.R, c.O} {
+			for _, t := range l {
+				a.addDebugInfo(t.VariableID(), formatted)
+			}
+		}
+	}
+
+	return a
+}
+
+// name returns the name of the input w, or a description of the internal wire w
+func (a *analysis) name(w int) string {
+	switch {
+	case w < a.nbInternal:
+		if a.isHint[w] {
+			return fmt.Sprintf("internal wire %d (computed by a hint)", w)
+		}
+		for _, c := range a.constraints[w] {
+			if c < int(a.r1cs.NbCOConstraints) {
+				return fmt.Sprintf("internal wire %d (computed by constraint %d)", w, c)
+			}
+		}
+		return fmt.Sprintf("internal wire %d", w)
+	case w < a.nbInternal+a.nbSecret:
+		return a.r1cs.SecretWires[w-a.nbInternal] + " (secret input)"
+	default:
+		return a.r1cs.PublicWires[w-a.nbInternal-a.nbSecret] + " (public input)"
+	}
+}
+
+// format returns the debug info entry, with the names of the wires it references
+func (a *analysis) format(entry backend.LogEntry) string {
+	if len(entry.ToResolve) == 0 {
+		return entry.Format
+	}
+	names := make([]interface{}, len(entry.ToResolve))
+	for i, w := range entry.ToResolve {
+		names[i] = a.name(w)
+	}
+	return fmt.Sprintf(entry.Format, names...)
+}
+
+func (a *analysis) addDebugInfo(w int, debugInfo string) {
+	if n := len(a.debugInfo[w]); n == 0 || a.debugInfo[w][n-1] != debugInfo {
+		a.debugInfo[w] = append(a.debugInfo[w], debugInfo)
+	}
+}
+
+func (a *analysis) wireReport(w int) WireReport {
+	return WireReport{ID: w, Name: a.name(w), DebugInfo: a.debugInfo[w]}
+}
+
+// constrainsHint returns true if a wire of c (w excepted) is computed by a hint and appears in
+// other constraints: c is then a check of the hint result, rather than a computation of w
+func (a *analysis) constrainsHint(c r1c.R1C, w int) bool {
+	for _, l := range [3]r1c.LinearExpression{c.L, c.R, c.O} {
+		for _, t := range l {
+			if h := t.VariableID(); h != w && a.isHint[h] && len(a.constraints[h]) > 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coeff returns the coefficient of t, in [0, modulus)
+func (a *analysis) coeff(t r1c.Term) *big.Int {
+	c := new(big.Int).Set(&a.r1cs.Coefficients[t.CoeffID()])
+	if a.modulus != nil {
+		c.Mod(c, a.modulus)
+	}
+	return c
+}
+
+func (a *analysis) isMinusOne(t r1c.Term) bool {
+	c := a.coeff(t)
+	if a.modulus != nil {
+		return c.Add(c, big.NewInt(1)).Cmp(a.modulus) == 0
+	}
+	return c.Cmp(big.NewInt(-1)) == 0
+}
+
+func (a *analysis) isOne(t r1c.Term) bool {
+	return a.coeff(t).Cmp(big.NewInt(1)) == 0
+}
+
+// booleanConstraint returns the wire w if c is w * (1 - w) == 0, or w * w == w
+func (a *analysis) booleanConstraint(c r1c.R1C) (int, bool) {
+	single := func(l r1c.LinearExpression) (int, bool) {
+		if len(l) != 1 || !a.isOne(l[0]) {
+			return 0, false
+		}
+		return l[0].VariableID(), true
+	}
+
+	// w * w == w
+	if w, ok := single(c.L); ok {
+		if w2, ok := single(c.R); ok && w2 == w {
+			if w3, ok := single(c.O); ok && w3 == w {
+				return w, true
+			}
+		}
+	}
+
+	// w * (1 - w) == 0, or (1 - w) * w == 0
+	for _, t := range c.O {
+		if a.coeff(t).Sign() != 0 {
+			return 0, false
+		}
+	}
+	oneMinus := func(l r1c.LinearExpression, w int) bool {
+		if len(l) != 2 {
+			return false
+		}
+		for i := 0; i < 2; i++ {
+			one, minusW := l[i], l[1-i]
+			if one.VariableID() == a.oneWire && a.isOne(one) && minusW.VariableID() == w && a.isMinusOne(minusW) {
+				return true
+			}
+		}
+		return false
+	}
+	if w, ok := single(c.L); ok && oneMinus(c.R, w) {
+		return w, true
+	}
+	if w, ok := single(c.R); ok && oneMinus(c.L, w) {
+		return w, true
+	}
+	return 0, false
+}
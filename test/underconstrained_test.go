@@ -0,0 +1,83 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type wellConstrainedCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+// Define uses gadgets with auxiliary wires and hints
+func (circuit *wellConstrainedCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	bits := cs.ToBinary(circuit.X, 8)
+	isZero := cs.IsZero(circuit.Y)
+	bit := cs.NewHint(hint.IthBit, circuit.Y, 3)
+	cs.AssertIsBoolean(bit)
+	cs.AssertIsEqual(cs.Add(cs.Select(isZero, bits[0], bits[7]), bit), circuit.Z)
+	return nil
+}
+
+type underConstrainedCircuit struct {
+	X, Y, Unused frontend.Variable
+	Z            frontend.Variable `gnark:",public"`
+}
+
+func (circuit *underConstrainedCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	// the inverse is not used
+	cs.Inverse(circuit.X)
+
+	// the bit is not constrained to be boolean
+	bit := cs.NewHint(hint.IthBit, circuit.Y, 3)
+	cs.AssertIsEqual(cs.Add(circuit.X, bit), circuit.Z)
+	return nil
+}
+
+func TestUnderConstrained(t *testing.T) {
+	assert := NewAssert(t)
+
+	for _, curveID := range curves {
+		r1cs, err := frontend.Compile(curveID, &wellConstrainedCircuit{})
+		assert.NoError(err)
+		assert.NotUnderConstrained(r1cs)
+	}
+
+	r1cs, err := frontend.Compile(gurvy.BN256, &underConstrainedCircuit{})
+	assert.NoError(err)
+	report := FindUnderConstrained(r1cs)
+
+	// Y is only an input of the hint
+	assert.Len(report.Unconstrained, 2)
+	assert.Equal("Y (secret input)", report.Unconstrained[0].Name)
+	assert.Equal("Unused (secret input)", report.Unconstrained[1].Name)
+
+	assert.Len(report.UnusedOutputs, 1)
+	assert.True(strings.HasPrefix(report.UnusedOutputs[0].Name, "internal wire"), report.String())
+
+	assert.Len(report.NonBoolean, 1)
+	assert.Equal("internal wire 1 (computed by a hint)", report.NonBoolean[0].Name, report.String())
+	assert.Len(report.NonBoolean[0].DebugInfo, 1)
+	assert.Contains(report.NonBoolean[0].DebugInfo[0], "(internal wire 1 (computed by a hint) * 1) != (Z (public input) * 1)")
+}